@@ -0,0 +1,123 @@
+package binsearch
+
+import (
+ "math"
+ "strings"
+ "testing"
+)
+
+func TestCounterBytesFloat64(t *testing.T) {
+	c := new(CounterBytesFloat64)
+	long := strings.Repeat(`w`, 80)
+	for _, x := range []string{`a`, `b`, `a`, long, long, `ccccccccccc`, ``} {
+		if err := c.Add([]byte(x), 0.25); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.Build()
+	for x, want := range map[string]float64{`a`: 0.5, long: 0.5, `b`: 0.25, ``: 0.25} {
+		if v, ok := c.Find([]byte(x)); !ok || v != want {
+			t.Fatalf(`%q = %v, %v`, x, v, ok)
+		}
+	}
+	c.UpdateAll(func(f float64) float64 { return f * 2 })
+	c.Update([]byte(`b`), func(f float64) float64 { return f - 1 })
+	c2 := new(CounterBytesFloat64)
+	roundTrip(t, c.Write, c2.Read)
+	if v, _ := c2.Find([]byte(`a`)); v != 1 {
+		t.Fatal(v)
+	}
+	if v, _ := c2.Find([]byte(`b`)); v != -0.5 {
+		t.Fatal(v)
+	}
+	var sum float64
+	if c2.Reset() {
+		for {
+			_, v, eof := c2.Next()
+			sum += v
+			if eof {
+				break
+			}
+		}
+	}
+	if sum != 2.5 {
+		t.Fatal(sum)
+	}
+}
+
+func TestCounterBytesInt64(t *testing.T) {
+	c := new(CounterBytesInt64)
+	c.Add([]byte(`x`), math.MaxInt64)
+	c.Add([]byte(`y`), 5)
+	c.Add([]byte(`y`), -7)
+	c.Add([]byte(`z`), math.MinInt64)
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.Find([]byte(`y`)); v != -2 {
+		t.Fatal(v)
+	}
+	c2 := new(CounterBytesInt64)
+	roundTrip(t, c.Write, c2.Read)
+	for x, want := range map[string]int64{`x`: math.MaxInt64, `y`: -2, `z`: math.MinInt64} {
+		if v, ok := c2.Find([]byte(x)); !ok || v != want {
+			t.Fatalf(`%q = %d, %v`, x, v, ok)
+		}
+	}
+	up := new(CounterBytesInt64)
+	up.Add([]byte(`x`), math.MaxInt64)
+	up.Add([]byte(`x`), 1)
+	if err := up.Build(); err != ErrOverflow {
+		t.Fatal(`no overflow upwards`, err)
+	}
+	down := new(CounterBytesInt64)
+	down.Add([]byte(`x`), math.MinInt64)
+	down.Add([]byte(`x`), -1)
+	if err := down.Build(); err != ErrOverflow {
+		t.Fatal(`no overflow downwards`, err)
+	}
+}
+
+func TestCounterBuildChecked(t *testing.T) {
+	g := new(Counter[uint64, float64])
+	g.Add(3, 0.1)
+	g.Add(3, 0.2)
+	g.Add(1, 1.5)
+	g.Build()
+	if v, _ := g.Find(3); math.Abs(v - 0.3) > 1e-12 {
+		t.Fatal(v)
+	}
+	g2 := new(Counter[uint64, float64])
+	roundTrip(t, g.Write, g2.Read)
+	if v, _ := g2.Find(1); v != 1.5 {
+		t.Fatal(v)
+	}
+	h := new(Counter[string, int64])
+	h.Add(`a`, math.MaxInt64)
+	h.Add(`a`, 1)
+	h.Add(`b`, math.MinInt64)
+	if err := h.BuildChecked(); err != ErrOverflow {
+		t.Fatal(`int64`, err)
+	}
+	u := new(Counter[string, uint8])
+	u.Add(`a`, 200)
+	u.Add(`a`, 55)
+	if err := u.BuildChecked(); err != nil {
+		t.Fatal(err)
+	}
+	u = new(Counter[string, uint8])
+	u.Add(`a`, 200)
+	u.Add(`a`, 56)
+	if err := u.BuildChecked(); err != ErrOverflow {
+		t.Fatal(`uint8`, err)
+	}
+	ci := new(CounterInt)
+	ci.Add(4, -2)
+	ci.Add(4, -3)
+	ci.Build()
+	ci2 := new(CounterInt)
+	roundTrip(t, ci.Write, ci2.Read)
+	if v, _ := ci2.Find(4); v != -5 {
+		t.Fatal(v)
+	}
+}
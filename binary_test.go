@@ -0,0 +1,162 @@
+package binsearch
+
+import (
+ "bytes"
+ "testing"
+ "github.com/AlasdairF/Custom"
+)
+
+// binaryKeys are keys that differ from each other only by leading or trailing NUL bytes.
+var binaryKeys = [][]byte{{}, {0}, {0, 'a'}, []byte(`a`), {'a', 0}, {0, 0, 0, 0, 0, 0, 0, 0}, {0, 0, 0, 0, 0, 0, 0, 0, 0}, append(bytes.Repeat([]byte{0}, 20), 1), bytes.Repeat([]byte{0}, 64), bytes.Repeat([]byte{0}, 70)}
+
+// sameKeys checks that got holds exactly the keys of want, whatever their order.
+func sameKeys(t *testing.T, name string, got [][]byte, want [][]byte) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf(`%s: %d keys, want %d`, name, len(got), len(want))
+	}
+	have := make(map[string]int)
+	for _, x := range got {
+		have[string(x)]++
+	}
+	for _, x := range want {
+		if have[string(x)] != 1 {
+			t.Fatalf(`%s: %q found %d times in %q`, name, x, have[string(x)], got)
+		}
+	}
+}
+
+func TestBinaryKeys(t *testing.T) {
+	k := new(KeyBytes)
+	c := new(CounterBytes)
+	for i, x := range binaryKeys {
+		if err := k.AddUnsorted(x); err != nil {
+			t.Fatal(err)
+		}
+		c.Add(x, i+1)
+	}
+	if _, err := k.Build(); err != nil {
+		t.Fatal(err)
+	}
+	c.Build()
+	kv := c.KeyValBytes()
+	k2 := new(KeyBytes)
+	roundTrip(t, k.Write, k2.Read)
+	c2 := new(CounterBytes)
+	roundTrip(t, c.Write, c2.Read)
+	kv2 := new(KeyValBytes)
+	roundTrip(t, kv.Write, kv2.Read)
+	sameKeys(t, `KeyBytes`, k.Keys(), binaryKeys)
+	sameKeys(t, `KeyBytes after Read`, k2.Keys(), binaryKeys)
+	sameKeys(t, `CounterBytes`, c.Keys(), binaryKeys)
+	sameKeys(t, `CounterBytes after Read`, c2.Keys(), binaryKeys)
+	sameKeys(t, `KeyValBytes`, kv.Keys(), binaryKeys)
+	sameKeys(t, `KeyValBytes after Read`, kv2.Keys(), binaryKeys)
+	for i, x := range binaryKeys {
+		if v, ok := c2.Find(x); !ok || v != i+1 {
+			t.Fatalf(`CounterBytes %q = %d, %v`, x, v, ok)
+		}
+		if v, ok := kv2.Find(x); !ok || v != i+1 {
+			t.Fatalf(`KeyValBytes %q = %d, %v`, x, v, ok)
+		}
+	}
+	var got [][]byte
+	if k2.Reset() {
+		for {
+			x, eof := k2.Next()
+			got = append(got, append([]byte(nil), x...))
+			if eof {
+				break
+			}
+		}
+	}
+	sameKeys(t, `KeyBytes.Next`, got, binaryKeys)
+	got = got[:0]
+	if kv2.Reset() {
+		for {
+			x, v, eof := kv2.Next()
+			if w, _ := kv2.Find(x); w != v {
+				t.Fatalf(`KeyValBytes.Next %q = %d`, x, v)
+			}
+			got = append(got, append([]byte(nil), x...))
+			if eof {
+				break
+			}
+		}
+	}
+	sameKeys(t, `KeyValBytes.Next`, got, binaryKeys)
+}
+
+func TestBinaryKeysAdd(t *testing.T) {
+	k := new(KeyBytes)
+	if _, ok := k.Add([]byte(`a`)); ok {
+		t.Fatal(`a`)
+	}
+	for _, x := range [][]byte{{0, 'a'}, {}, {0}} {
+		if _, ok := k.Find(x); ok {
+			t.Fatalf(`%q found before it was added`, x)
+		}
+		if _, ok := k.Add(x); ok {
+			t.Fatalf(`%q reported as already present`, x)
+		}
+	}
+	if _, ok := k.Add([]byte{}); !ok {
+		t.Fatal(`the empty key was added twice`)
+	}
+	sameKeys(t, `KeyBytes`, k.Keys(), [][]byte{{}, {0}, {0, 'a'}, []byte(`a`)})
+}
+
+// TestBaselineFormat reads files laid out as the original KeyBytes and KeyValBytes wrote them, with the empty key stored as 0 in limit8[0].
+func TestBaselineFormat(t *testing.T) {
+	classes := [64][]uint64{0: {0, 'a', 'b'}, 1: {'h' << 8 | 'i'}}
+	want := [][]byte{{}, []byte(`a`), []byte(`b`), []byte(`hi`)}
+	k := new(KeyBytes)
+	roundTrip(t, func(w custom.Interface) {
+		w.WriteUint64Variable(uint64(len(want)))
+		var start int
+		for on:=0; on<64; on++ { // the index of the first key of each class
+			w.WriteUint64Variable(uint64(start))
+			start += len(classes[on])
+		}
+		for on:=0; on<64; on++ {
+			w.WriteUint64Variable(uint64(len(classes[on])))
+			for _, v := range classes[on] { // all in limit8, so one word each
+				w.WriteUint64(v)
+			}
+		}
+	}, k.Read)
+	kv := new(KeyValBytes)
+	roundTrip(t, func(w custom.Interface) {
+		w.WriteUint64Variable(uint64(len(want)))
+		var i int
+		for on:=0; on<64; on++ {
+			w.WriteUint64Variable(uint64(len(classes[on])))
+			for _, v := range classes[on] {
+				w.WriteUint64(v)
+				w.WriteUint64(uint64(i * 10))
+				i++
+			}
+		}
+	}, kv.Read)
+	for i, x := range want {
+		if j, ok := k.Find(x); !ok || j != i {
+			t.Fatalf(`KeyBytes.Find(%q) = %d, %v, want %d`, x, j, ok, i)
+		}
+		if v, ok := kv.Find(x); !ok || v != i * 10 {
+			t.Fatalf(`KeyValBytes.Find(%q) = %d, %v, want %d`, x, v, ok, i * 10)
+		}
+		if got := k.Keys()[i]; !bytes.Equal(got, x) {
+			t.Fatalf(`Keys()[%d] = %q, want %q`, i, got, x)
+		}
+	}
+	if _, ok := k.Find([]byte{0}); ok {
+		t.Fatal(`"\x00" found in a file that has only the empty key`)
+	}
+	// "\x00" goes after the other single bytes, so the keys before it keep their indexes.
+	if i, ok := k.Add([]byte{0}); ok || i != len(want) - 1 {
+		t.Fatal(`Add("\x00")`, i, ok)
+	}
+	if j, _ := k.Find([]byte(`b`)); j != 2 {
+		t.Fatal(`b moved to`, j)
+	}
+}
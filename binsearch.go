@@ -18,120 +18,273 @@ import (
  "github.com/AlasdairF/BinSearch/LimitVal48"
  "github.com/AlasdairF/BinSearch/LimitVal56"
  "github.com/AlasdairF/BinSearch/LimitVal64"
+// Sorting algorithm for keys longer than 64 bytes
+ "github.com/AlasdairF/BinSearch/Overflow"
 // General optimized sorting algorithms using primitives
  "github.com/AlasdairF/Sort/IntUint64"
  "github.com/AlasdairF/Sort/IntUint32"
  "github.com/AlasdairF/Sort/IntUint16"
  "github.com/AlasdairF/Sort/IntUint8"
  "github.com/AlasdairF/Sort/IntInt"
+// Sorting algorithm for the generic Key, KeyVal & Counter
+ "github.com/AlasdairF/BinSearch/Ordered"
 // Read/write custom file format
  "github.com/AlasdairF/Custom"
 // Error handling
  "errors"
+ "bytes"
+// Generic keys
+ "cmp"
+ "math"
+ "reflect"
+ "unsafe"
 )
 
 /*
 
 	INFORMATION
 	
-	Maximum key size is 64 bytes.
+	There is no maximum key size. Keys up to 64 bytes are compacted, longer keys are stored as they are in an overflow tier.
 
 	TYPES
 	
 	'Key' type (KeyBytes, KeyUint64 etc.) is a key-only store where the index of each key can be used to store any number of associated values.
-	'KeyVal' type (KeyValBytes, KeyValUint64, etc.) is a key-value store where the vals are ints. For custom value types use 'Key' (above).
+	'KeyVal' type (KeyValBytes, KeyValUint64, etc.) is a key-value store where the vals are ints. KeyVal[K, V] and KeyValBytesOf[V] take any value type.
 	'Counter' type (CounterBytes, CounterUint64, etc.) counts the number of occurances (equivalent to map[key]++) and allows very fast lookups.
 
 	INDEX
 	
-	KeyBytes, KeyRunes
+	KeyBytes, KeyRunes, KeyString
 		func (t *KeyBytes) Len() int
 		func (t *KeyBytes) Find(thekey []byte) (int, bool)					Returns: index, exists.
+		func (t *KeyBytes) FindMany(keys [][]byte, dstIndex []int, dstFound []bool)	Fills: index, exists for each key. Faster if keys are sorted.
 		func (t *KeyBytes) Add(thekey []byte) (int, bool)					Returns: index, exists. Adds the key if it does not already exist and returns the new index, otherwise returns the current index of the existing key.
-		func (t *KeyBytes) AddAt(thekey []byte, i int) error				Returns error if thekey > 64 bytes
-		func (t *KeyBytes) AddUnsorted(thekey []byte) error					Returns error if thekey > 64 bytes
+		func (t *KeyBytes) AddAt(thekey []byte, i int) error				Error is always nil
+		func (t *KeyBytes) AddUnsorted(thekey []byte) error					Error is always nil
+		func (t *KeyBytes) Remove(thekey []byte) (int, bool)				Returns: index, exists. Keys after it move down one index, so remove the same index from your values.
+		func (t *KeyBytes) RemoveAt(i int) bool								Removes the key at index i. Returns false if i is out of range.
+		func (t *KeyBytes) Retain(fn func([]byte) bool) []int				Keeps only the keys for which fn returns true. Returns slice mapping new indexes to old indexes, as Build.
 		func (t *KeyBytes) Build() ([]int, error)							Returns slice mapping old indexes to new indexes. Can only be used after AddUnsorted, otherwise returns an error.
 		func (t *KeyBytes) Optimize()										Copies all the data to new slices with capacity equal to length.
 		func (t *KeyBytes) Reset() bool										Returns false if the structure is empty (Len() == 0)
 		func (t *KeyBytes) Next() ([]byte, bool)							Returns: original slice of bytes, EOF (true = EOF)
 		func (t *KeyBytes) Keys() [][]byte									Returns slice containing all the keys in order
+		func (t *KeyBytes) ResetLex() bool									Must be called before NextLex. Returns false if the structure is empty (Len() == 0)
+		func (t *KeyBytes) NextLex() ([]byte, int, bool)					Returns: original slice of bytes, index, EOF (true = EOF) in the same order as bytes.Compare, instead of by length like Next
+		func (t *KeyBytes) LexOrder() []int									Returns the indexes of all the keys in the same order as bytes.Compare
+		func (t *KeyBytes) PrefixRange(prefix []byte) [][2]int				Returns the spans of indexes [from, to) of the keys starting with prefix
+		func (t *KeyBytes) CountPrefix(prefix []byte) int					Returns the number of keys starting with prefix
+		func (t *KeyBytes) KeyAt(i int) ([]byte, bool)						Returns: the key at index i, exists. The reverse of Find.
+		func (t *KeyBytes) Rank(thekey []byte) int							Returns the number of keys less than thekey in the order of bytes.Compare (rune order for KeyRunes, which decodes every key). This is not the index of Find.
+		func (t *KeyBytes) FindFuzzy(thekey []byte, maxDist int) ([][]byte, []int, []int)	Returns: keys within Levenshtein distance maxDist of thekey, their indexes, their distances
+		func (t *KeyBytes) MatchRegexp(re *regexp.Regexp) iter.Seq2[[]byte, int]	Iterates over: keys matching re, their indexes. Only keys with the literal prefix of an anchored (^) regexp are tested.
+		func (t *KeyBytes) MatchGlob(pattern string) (iter.Seq2[[]byte, int], error)	Iterates over: keys matching the glob pattern (* ? [abc] [!abc] \ as path.Match, so * and ? don't match /), their indexes
 		func (t *KeyBytes) Write(w custom.Interface)							Writes built structure out to custom.Writer (requires github.com/AlasdairF/Custom)
 		func (t *KeyBytes) Read(r *custom.Reader)							Reads structure in from custom.Reader (requires github.com/AlasdairF/Custom)
 		
-	KeyValBytes, KeyValRunes
+	KeyValBytes, KeyValRunes, KeyValString
 		func (t *KeyValBytes) Len() int
 		func (t *KeyValBytes) Find(thekey []byte) (int, bool)				Returns: value, exists
+		func (t *KeyValBytes) FindMany(keys [][]byte, dstVals []int, dstFound []bool)	Fills: value, exists for each key. Faster if keys are sorted.
 		func (t *KeyValBytes) Update(thekey []byte, fn func(int) int) bool	Returns boolean value for whether the key exists or not, if it exists the value is modified according to the fn function
 		func (t *KeyValBytes) UpdateAll(fn func(int) int)					Modifies all values by the fn function
 		func (t *KeyValBytes) Add(thekey []byte, theval int) bool			Returns whether it exists. Replaces old value with the new value if it exists, otherwise adds it in place.
-		func (t *KeyValBytes) AddUnsorted(thekey []byte, theval int) error	Returns error if thekey > 64 bytes
+		func (t *KeyValBytes) AddUnsorted(thekey []byte, theval int) error	Error is always nil
+		func (t *KeyValBytes) Remove(thekey []byte) (int, bool)			Returns: value, exists
+		func (t *KeyValBytes) Retain(fn func([]byte, int) bool)			Keeps only the keys for which fn(key, value) returns true
 		func (t *KeyValBytes) Build()										Only required to be called after AddUnsorted, otherwise it will shrink array capacity to length.
 		func (t *KeyValBytes) Optimize()									Copies all the data to new slices with capacity equal to length.
 		func (t *KeyValBytes) Reset() bool									Returns false if the structure is empty (Len() == 0)
 		func (t *KeyValBytes) Next() ([]byte, int, bool)					Returns: original slice of bytes, value, EOF (true = EOF)
+		func (t *KeyValBytes) TopK(k int) ([][]byte, []int)				Returns the k keys with the greatest values and their values, greatest first. Equal values are in key order.
+		func (t *KeyValBytes) BuildValueIndex()							Builds the index of keys by value used by FindByValue. Write saves it and Read loads it.
+		func (t *KeyValBytes) FindByValue(v int) ([]byte, bool)			Returns: the first key with value v, exists. Builds the value index if there isn't one.
+		func (t *KeyValBytes) FindAllByValue(v int) [][]byte				Returns all the keys with value v
+		func (t *KeyValBytes) ResetByValue(descending bool) bool			Must be called before NextByValue. Returns false if the structure is empty (Len() == 0)
+		func (t *KeyValBytes) NextByValue() ([]byte, int, bool)				The same as Next but ordered by value
 		func (t *KeyValBytes) Keys() [][]byte								Returns slice containing all the keys in order
+		func (t *KeyValBytes) ResetLex() bool								Must be called before NextLex. Returns false if the structure is empty (Len() == 0)
+		func (t *KeyValBytes) NextLex() ([]byte, int, bool)				Returns: original slice of bytes, value, EOF (true = EOF) in the same order as bytes.Compare
+		func (t *KeyValBytes) PrefixRange(prefix []byte) [][2]int			Returns the spans of indexes [from, to) of the keys starting with prefix, in the order of Next
+		func (t *KeyValBytes) CountPrefix(prefix []byte) int				Returns the number of keys starting with prefix
+		func (t *KeyValBytes) Rank(thekey []byte) int						Returns the number of keys less than thekey in the order of bytes.Compare
+		func (t *KeyValBytes) FindFuzzy(thekey []byte, maxDist int) ([][]byte, []int, []int)	Returns: keys within Levenshtein distance maxDist of thekey, their values, their distances
+		func (t *KeyValBytes) MatchRegexp(re *regexp.Regexp) iter.Seq2[[]byte, int]	Iterates over: keys matching re, their values. Only keys with the literal prefix of an anchored (^) regexp are tested.
+		func (t *KeyValBytes) MatchGlob(pattern string) (iter.Seq2[[]byte, int], error)	Iterates over: keys matching the glob pattern (* ? [abc] [!abc] \ as path.Match, so * and ? don't match /), their values
 		func (t *KeyValBytes) Write(w custom.Interface)						Writes built structure out to custom.Writer (requires github.com/AlasdairF/Custom)
 		func (t *KeyValBytes) Read(r *custom.Reader)						Reads structure in from custom.Reader (requires github.com/AlasdairF/Custom)
 		
-	CounterBytes, CounterRunes
+	CounterBytes, CounterRunes, CounterString
 		func (t *CounterBytes) Len() int									Len() is only accurate after Build()
 		func (t *CounterBytes) Find(thekey []byte) (int, bool)				Returns: frequency, exists. Will return nonsensical results if used before Build() is executed; only use after Build.
+		func (t *CounterBytes) FindMany(keys [][]byte, dstVals []int, dstFound []bool)	Fills: frequency, exists for each key. Faster if keys are sorted.
 		func (t *CounterBytes) Update(thekey []byte, fn func(int) int) bool	Returns boolean value for whether the key exists or not, if it exists the value is modified according to the fn function
 		func (t *CounterBytes) UpdateAll(fn func(int) int)					Modifies all values by the fn function
-		func (t *CounterBytes) Add(thekey []byte, theval int) error			Returns an error if thekey > 64 bytes
+		func (t *CounterBytes) Add(thekey []byte, theval int) error			Error is always nil
 		func (t *CounterBytes) Build()										Always required before Find.
+		func (t *CounterBytes) Retain(fn func([]byte, int) bool)			Keeps only the keys for which fn(key, value) returns true, e.g. to drop rare keys after Build
 		func (t *CounterBytes) Optimize()									Copies all the data to new slices with capacity equal to length.
 		func (t *CounterBytes) Reset() bool									Returns false if the structure is empty (Len() == 0)
 		func (t *CounterBytes) Next() ([]byte, int, bool)					Returns: original slice of bytes, value, EOF (true = EOF)
+		func (t *CounterBytes) TopK(k int) ([][]byte, []int)				Returns the k most frequent keys and their frequencies, greatest first. Equal values are in key order.
+		func (t *CounterBytes) ResetByValue(descending bool) bool			Must be called before NextByValue. Returns false if the structure is empty (Len() == 0)
+		func (t *CounterBytes) NextByValue() ([]byte, int, bool)			The same as Next but ordered by frequency
 		func (t *CounterBytes) Keys() [][]byte								Returns slice containing all the keys in order
+		func (t *CounterBytes) ResetLex() bool								Must be called before NextLex. Returns false if the structure is empty (Len() == 0)
+		func (t *CounterBytes) NextLex() ([]byte, int, bool)				Returns: original slice of bytes, value, EOF (true = EOF) in the same order as bytes.Compare
+		func (t *CounterBytes) PrefixRange(prefix []byte) [][2]int			Returns the spans of indexes [from, to) of the keys starting with prefix, in the order of Next
+		func (t *CounterBytes) CountPrefix(prefix []byte) int				Returns the number of keys starting with prefix
+		func (t *CounterBytes) Rank(thekey []byte) int						Returns the number of keys less than thekey in the order of bytes.Compare
+		func (t *CounterBytes) FindFuzzy(thekey []byte, maxDist int) ([][]byte, []int, []int)	Returns: keys within Levenshtein distance maxDist of thekey, their frequencies, their distances
+		func (t *CounterBytes) MatchRegexp(re *regexp.Regexp) iter.Seq2[[]byte, int]	Iterates over: keys matching re, their frequencies. Only keys with the literal prefix of an anchored (^) regexp are tested.
+		func (t *CounterBytes) MatchGlob(pattern string) (iter.Seq2[[]byte, int], error)	Iterates over: keys matching the glob pattern (* ? [abc] [!abc] \ as path.Match, so * and ? don't match /), their frequencies
 		func (t *CounterBytes) Write(w custom.Interface)						Writes built structure out to custom.Writer (requires github.com/AlasdairF/Custom)
 		func (t *CounterBytes) Read(r *custom.Reader)						Reads structure in from custom.Reader (requires github.com/AlasdairF/Custom)
 		func (t *CounterBytes) KeyBytes() *KeyBytes							Copies keys to a KeyBytes structure
 		func (t *CounterBytes) KeyValBytes() *KeyBytes						Copies keys and values to a KeyValBytes structure
 		
-	KeyInt, KeyUint64, KeyUint32, KeyUint16, KeyUint8
-		func (t *KeyInt) Len() int
-		func (t *KeyInt) Find(thekey []byte) (int, bool)					Returns: index, exists.
-		func (t *KeyInt) Add(thekey []byte) (int, bool)						Returns: index, exists.
-		func (t *KeyInt) AddAt(thekey []byte, i int)
-		func (t *KeyInt) AddUnsorted(thekey []byte)
-		func (t *KeyInt) Build() []int										Returns slice mapping old indexes to new indexes. Only required if AddUnsorted was used, otherwise it will shrink array capacity to length.
-		func (t *KeyInt) Optimize()											Copies all the data to new slices with capacity equal to length.
-		func (t *KeyInt) Reset() bool										Returns false if the structure is empty (Len() == 0)
-		func (t *KeyInt) Next() (uint64, bool)								Returns: key, EOF (true = EOF)
-		func (t *KeyInt) Keys() []uint64									Returns slice containing all the keys in order
-		func (t *KeyInt) Write(w custom.Interface)							Writes built structure out to custom.Writer (requires github.com/AlasdairF/Custom)
-		func (t *KeyInt) Read(r *custom.Reader)								Reads structure in from custom.Reader (requires github.com/AlasdairF/Custom)
+	CounterBytesFloat64, CounterBytesInt64
+		The same functions as CounterBytes with float64 or int64 values (except for KeyValBytes), and:
+		func (t *CounterBytesInt64) Build() error							Returns ErrOverflow if a total doesn't fit in an int64
 		
-	KeyValInt, KeyValUint64, KeyValUint32, KeyValUint16, KeyValUint8
-		func (t *KeyValInt) Len() int
-		func (t *KeyValInt) Find(thekey uint64) (int, bool)					Returns: value, exists
-		func (t *KeyValInt) Update(thekey uint64, fn func(int) int) bool	Returns boolean value for whether the key exists or not, if it exists the value is modified according to the fn function
-		func (t *KeyValInt) UpdateAll(fn func(int) int)						Modifies all values by the fn function
-		func (t *KeyValInt) Add(thekey uint64, theval int) bool				Returns whether it exists. Replaces old value with the new value if it exists, otherwise adds it in place.
-		func (t *KeyValInt) AddUnsorted(thekey uint64, theval int)
-		func (t *KeyValInt) Build()											Only required to be called after AddUnsorted, otherwise it will shrink array capacity to length.
-		func (t *KeyValInt) Optimize()										Copies all the data to new slices with capacity equal to length.
-		func (t *KeyValInt) Reset() bool									Returns false if the structure is empty (Len() == 0)
-		func (t *KeyValInt) Next() ([]byte, int, bool)						Returns: original slice of bytes, value, EOF (true = EOF)
-		func (t *KeyValInt) Keys() []uint64									Returns slice containing all the keys in order
-		func (t *KeyValInt) Write(w custom.Interface)							Writes built structure out to custom.Writer (requires github.com/AlasdairF/Custom)
-		func (t *KeyValInt) Read(r *custom.Reader)							Reads structure in from custom.Reader (requires github.com/AlasdairF/Custom))
+	The Runes and String types have the same functions with []rune or string in place of []byte.
+	The Runes types do not have ResetLex & NextLex since their encoding is not in rune order.
+	KeyValRunes and KeyValString also have BuildValueIndex, FindByValue & FindAllByValue. Every change to the keys or values drops the value index, and the next FindByValue builds it again. Read drops an index that is corrupt.
+	The String types do not have FindFuzzy, MatchRegexp or MatchGlob. For the Runes types FindFuzzy counts the distance in runes, and MatchRegexp matches the key as a string.
 		
-	CounterInt, CounterUint64, CounterUint32, CounterUint16, CounterUint8
-		func (t *CounterInt) Len() int										Len() is only accurate after Build()
-		func (t *CounterInt) Find(thekey uint64) (int, bool)				Returns: frequency, exists. Will return nonsensical results if used before Build() is executed; only use after Build.
-		func (t *CounterInt) Update(thekey uint64, fn func(int) int) bool	Returns boolean value for whether the key exists or not, if it exists the value is modified according to the fn function
-		func (t *CounterInt) UpdateAll(fn func(int) int)					Modifies all values by the fn function
-		func (t *CounterInt) Add(thekey uint64, theval int)
-		func (t *CounterInt) Build()										Always required before Find.
-		func (t *CounterInt) Optimize()										Copies all the data to new slices with capacity equal to length.
-		func (t *CounterInt) Reset() bool									Returns false if the structure is empty (Len() == 0)
-		func (t *CounterInt) Next() ([]byte, int, bool)						Returns: original slice of bytes, value, EOF (true = EOF)
-		func (t *CounterInt) Keys() []uint64								Returns slice containing all the keys in order
-		func (t *CounterInt) Write(w custom.Interface)						Writes built structure out to custom.Writer (requires github.com/AlasdairF/Custom)
-		func (t *CounterInt) Read(r *custom.Reader)							Reads structure in from custom.Reader (requires github.com/AlasdairF/Custom)
-		func (t *CounterInt) Copy() *KeyInt									Copies keys to a KeyInt structure
+	Key[K], KeyInt, KeyInt64, KeyInt32, KeyInt16, KeyInt8, KeyUint64, KeyUint32, KeyUint16, KeyUint8 (KeyUint64 = Key[uint64], etc.)
+		func (t *Key[K]) Len() int
+		func (t *Key[K]) Find(thekey K) (int, bool)							Returns: index, exists.
+		func (t *Key[K]) FindMany(keys []K, dstIndex []int, dstFound []bool)		Fills: index, exists for each key. Faster if keys are sorted.
+		func (t *Key[K]) Add(thekey K) (int, bool)							Returns: index, exists.
+		func (t *Key[K]) AddAt(thekey K, i int)
+		func (t *Key[K]) AddUnsorted(thekey K)
+		func (t *Key[K]) Remove(thekey K) (int, bool)						Returns: index, exists. Keys after it move down one index.
+		func (t *Key[K]) RemoveAt(i int) bool								Removes the key at index i. Returns false if i is out of range.
+		func (t *Key[K]) Retain(fn func(K) bool) []int						Keeps only the keys for which fn returns true. Returns slice mapping new indexes to old indexes, as Build.
+		func (t *Key[K]) Build() []int										Returns slice mapping old indexes to new indexes. Only required if AddUnsorted was used, otherwise it will shrink array capacity to length.
+		func (t *Key[K]) Optimize()											Copies all the data to new slices with capacity equal to length.
+		func (t *Key[K]) Reset() bool										Returns false if the structure is empty (Len() == 0)
+		func (t *Key[K]) Next() (K, bool)									Returns: key, EOF (true = EOF)
+		func (t *Key[K]) Keys() []K											Returns slice containing all the keys in order
+		func (t *Key[K]) LowerBound(thekey K) int							Returns the index of the first key >= thekey, or Len() if there isn't one
+		func (t *Key[K]) UpperBound(thekey K) int							Returns the index of the first key > thekey, or Len() if there isn't one
+		func (t *Key[K]) Floor(thekey K) (K, int, bool)						Returns: greatest key <= thekey, index, exists
+		func (t *Key[K]) Ceiling(thekey K) (K, int, bool)					Returns: smallest key >= thekey, index, exists
+		func (t *Key[K]) Between(lo, hi K) (int, int)						Returns the span of indexes [from, to) of the keys >= lo and < hi
+		func (t *Key[K]) Range(lo, hi K) iter.Seq2[K, int]					Iterates over: keys >= lo and < hi, their indexes
+		func (t *Key[K]) Seek(thekey K) bool								Moves Next to the first key >= thekey. Returns false if there isn't one.
+		func (t *Key[K]) KeyAt(i int) (K, bool)								Returns: the key at index i, exists. The reverse of Find.
+		func (t *Key[K]) Rank(thekey K) int									Returns the number of keys less than thekey. KeyVal & Counter also have Rank.
+		func (t *Key[K]) Write(w custom.Interface)							Writes built structure out to custom.Writer (requires github.com/AlasdairF/Custom)
+		func (t *Key[K]) Read(r *custom.Reader)								Reads structure in from custom.Reader (requires github.com/AlasdairF/Custom)
+		
+	KeyVal[K, V], KeyValInt, KeyValInt64, KeyValInt32, KeyValInt16, KeyValInt8, KeyValUint64, KeyValUint32, KeyValUint16, KeyValUint8 (KeyValUint64 = KeyVal[uint64, int], etc.)
+		func NewKeyValFunc[K, V](compare func(V, V) int) *KeyVal[K, V]		Orders the values by compare for TopK, NextByValue & FindByValue, for a V that isn't an integer, float or string
+		func (t *KeyVal[K, V]) Len() int
+		func (t *KeyVal[K, V]) Find(thekey K) (V, bool)						Returns: value, exists
+		func (t *KeyVal[K, V]) FindMany(keys []K, dstVals []V, dstFound []bool)	Fills: value, exists for each key. Faster if keys are sorted.
+		func (t *KeyVal[K, V]) Update(thekey K, fn func(V) V) bool			Returns boolean value for whether the key exists or not, if it exists the value is modified according to the fn function
+		func (t *KeyVal[K, V]) UpdateAll(fn func(V) V)						Modifies all values by the fn function
+		func (t *KeyVal[K, V]) Add(thekey K, theval V) bool					Returns whether it exists. Replaces old value with the new value if it exists, otherwise adds it in place.
+		func (t *KeyVal[K, V]) AddUnsorted(thekey K, theval V)
+		func (t *KeyVal[K, V]) Remove(thekey K) (V, bool)					Returns: value, exists
+		func (t *KeyVal[K, V]) Retain(fn func(K, V) bool)					Keeps only the keys for which fn(key, value) returns true
+		func (t *KeyVal[K, V]) Build()										Only required to be called after AddUnsorted, otherwise it will shrink array capacity to length.
+		func (t *KeyVal[K, V]) Optimize()									Copies all the data to new slices with capacity equal to length.
+		func (t *KeyVal[K, V]) Reset() bool									Returns false if the structure is empty (Len() == 0)
+		func (t *KeyVal[K, V]) Next() (K, V, bool)							Returns: key, value, EOF (true = EOF)
+		func (t *KeyVal[K, V]) TopK(k int) ([]K, []V)						Returns the k keys with the greatest values and their values, greatest first. V must be an integer, float or string type, or use NewKeyValFunc.
+		func (t *KeyVal[K, V]) BuildValueIndex()							Builds the index of keys by value used by FindByValue. Write saves it and Read loads it. V must be an integer, float or string type, or use NewKeyValFunc.
+		func (t *KeyVal[K, V]) FindByValue(v V) (K, bool)					Returns: the first key with value v, exists. Builds the value index if there isn't one.
+		func (t *KeyVal[K, V]) FindAllByValue(v V) []K					Returns all the keys with value v
+		func (t *KeyVal[K, V]) ResetByValue(descending bool) bool			Must be called before NextByValue. Returns false if the structure is empty (Len() == 0)
+		func (t *KeyVal[K, V]) NextByValue() (K, V, bool)					The same as Next but ordered by value
+		func (t *KeyVal[K, V]) Keys() []K									Returns slice containing all the keys in order
+		func (t *KeyVal[K, V]) LowerBound(thekey K) int						Returns the index of the first key >= thekey, or Len() if there isn't one
+		func (t *KeyVal[K, V]) UpperBound(thekey K) int						Returns the index of the first key > thekey, or Len() if there isn't one
+		func (t *KeyVal[K, V]) Floor(thekey K) (K, V, int, bool)			Returns: greatest key <= thekey, value, index, exists
+		func (t *KeyVal[K, V]) Ceiling(thekey K) (K, V, int, bool)			Returns: smallest key >= thekey, value, index, exists
+		func (t *KeyVal[K, V]) Between(lo, hi K) (int, int)					Returns the span of indexes [from, to) of the keys >= lo and < hi
+		func (t *KeyVal[K, V]) Range(lo, hi K) iter.Seq2[K, V]				Iterates over: keys >= lo and < hi, their values
+		func (t *KeyVal[K, V]) Seek(thekey K) bool							Moves Next to the first key >= thekey. Returns false if there isn't one.
+		func (t *KeyVal[K, V]) Write(w custom.Interface)						Writes built structure out to custom.Writer (requires github.com/AlasdairF/Custom)
+		func (t *KeyVal[K, V]) Read(r *custom.Reader)						Reads structure in from custom.Reader (requires github.com/AlasdairF/Custom)
+		func (t *KeyVal[K, V]) WriteWith(w custom.Interface, codec ValueCodec[V])	Write for any value type, the codec writes each value. Write supports integers, floats, bools, strings and []byte.
+		func DefaultCodec[V any]() (ValueCodec[V], error)					Returns the codec used by Write & Read, or ErrNoCodec if V is not supported. Write & Read panic before doing anything if V is not supported.
+		func DefaultCompare[V any]() (func(V, V) int, error)					Returns the value order used without NewKeyValFunc, or ErrNotOrdered. TopK, NextByValue & FindByValue panic before doing anything if V can't be ordered.
+		func (t *KeyVal[K, V]) ReadWith(r *custom.Reader, codec ValueCodec[V])		Read for any value type, the codec must match the one given to WriteWith
+		
+	Counter[K, N], CounterInt, CounterInt64, CounterInt32, CounterInt16, CounterInt8, CounterUint64, CounterUint32, CounterUint16, CounterUint8 (CounterUint64 embeds Counter[uint64, int], etc.)
+		func (t *Counter[K, N]) Len() int									Len() is only accurate after Build()
+		func (t *Counter[K, N]) Find(thekey K) (N, bool)					Returns: frequency, exists. Will return nonsensical results if used before Build() is executed; only use after Build.
+		func (t *Counter[K, N]) FindMany(keys []K, dstVals []N, dstFound []bool)	Fills: frequency, exists for each key. Faster if keys are sorted.
+		func (t *Counter[K, N]) Update(thekey K, fn func(N) N) bool			Returns boolean value for whether the key exists or not, if it exists the value is modified according to the fn function
+		func (t *Counter[K, N]) UpdateAll(fn func(N) N)						Modifies all values by the fn function
+		func (t *Counter[K, N]) Add(thekey K, theval N)
+		func (t *Counter[K, N]) Build()										Always required before Find.
+		func (t *Counter[K, N]) BuildChecked() error						Build, but returns ErrOverflow if a total doesn't fit in N. N can be any integer or float type.
+		func (t *Counter[K, N]) Retain(fn func(K, N) bool)					Keeps only the keys for which fn(key, value) returns true
+		func (t *Counter[K, N]) Optimize()									Copies all the data to new slices with capacity equal to length.
+		func (t *Counter[K, N]) Reset() bool								Returns false if the structure is empty (Len() == 0)
+		func (t *Counter[K, N]) Next() (K, N, bool)							Returns: key, value, EOF (true = EOF)
+		func (t *Counter[K, N]) TopK(k int) ([]K, []N)						Returns the k most frequent keys and their frequencies, greatest first
+		func (t *Counter[K, N]) ResetByValue(descending bool) bool			Must be called before NextByValue. Returns false if the structure is empty (Len() == 0)
+		func (t *Counter[K, N]) NextByValue() (K, N, bool)					The same as Next but ordered by frequency
+		func (t *Counter[K, N]) Keys() []K									Returns slice containing all the keys in order
+		func (t *Counter[K, N]) LowerBound(thekey K) int					Also UpperBound, Floor, Ceiling, Between, Range & Seek as for KeyVal[K, V]
+		func (t *Counter[K, N]) Write(w custom.Interface)					Writes built structure out to custom.Writer (requires github.com/AlasdairF/Custom)
+		func (t *Counter[K, N]) Read(r *custom.Reader)						Reads structure in from custom.Reader (requires github.com/AlasdairF/Custom)
+		func (t *Counter[K, N]) Key() *Key[K]								Copies keys to a Key structure (also CounterUint64.KeyUint64(), etc.)
+		func (t *Counter[K, N]) KeyVal() *KeyVal[K, N]						Copies keys and values to a KeyVal structure (also CounterUint64.KeyValUint64(), etc.)
+		func (t *CounterUint64) RawKey() []sortIntUint64.KeyVal				Returns the keys and frequencies, this is not a copy. NewCounterUint64(ar []sortIntUint64.KeyVal) reuses the memory of ar.
+		
+	KeyFloat64, KeyValFloat64, CounterFloat64, KeyFloat32, KeyValFloat32, CounterFloat32
+		The same functions as KeyUint64, KeyValUint64 and CounterUint64 with float keys, including LowerBound, UpperBound, Floor, Ceiling, Between, Range & Seek, plus:
+		func (t *CounterFloat64) KeyFloat64() *KeyFloat64					Copies keys to a KeyFloat64 structure
+		func (t *CounterFloat64) KeyValFloat64() *KeyValFloat64				Copies keys and values to a KeyValFloat64 structure
+		Keys are ordered: -Inf < negative numbers < -0 < +0 < positive numbers < +Inf < NaN. All NaNs are the same key.
+		
+	KeyUint128, KeyValUint128, CounterUint128, KeyUint160, KeyValUint160, CounterUint160, KeyUint256, KeyValUint256, CounterUint256
+		The same functions as KeyUint64, KeyValUint64 and CounterUint64 (except the range queries) with [16]byte, [20]byte or [32]byte keys (e.g. UUID, SHA-1, SHA-256), plus:
+		func (t *CounterUint128) KeyUint128() *KeyUint128					Copies keys to a KeyUint128 structure
+		func (t *CounterUint128) KeyValUint128() *KeyValUint128				Copies keys and values to a KeyValUint128 structure
+		
+	KeyValBytesOf[V]
+		The same functions as KeyValBytes (except the value index) with a value of any type V, plus WriteWith & ReadWith as for KeyVal[K, V], and:
+		func NewKeyValBytesOfFunc[V](compare func(V, V) int) *KeyValBytesOf[V]	Orders the values by compare for TopK & NextByValue
+		func (t *KeyValBytesOf[V]) Build() error							Reorders the values along with the keys. Only required after AddUnsorted, otherwise it does nothing.
+		func (t *KeyValBytesOf[V]) Values() []V								Returns the values in the same order as Keys(). This is not a copy.
+		TopK and NextByValue need V to be an integer, float or string type, or use NewKeyValBytesOfFunc.
+		
+	KeyAt and Rank are on all the Key types (including the float, fixed size and postings types), and Rank is on all the KeyVal and Counter types.
+	Rank is the index of thekey (KeyAt(Rank(thekey)) is thekey) for every type except the bytes, string and runes types, where it is in the order of NextLex and rune order.
+	FindMany is on all the Key, KeyVal and Counter types except the postings types. dstIndex/dstVals and dstFound must be at least as long as keys; nothing is allocated.
+		
+	KeyPostingsBytes, KeyPostings[K], KeyPostingsUint64, KeyPostingsUint32 (KeyPostingsUint64 = KeyPostings[uint64])
+		func (t *KeyPostingsBytes) Len() int
+		func (t *KeyPostingsBytes) Find(thekey []byte) ([]int, bool)			Returns: sorted unique IDs, exists. Only use after Build.
+		func (t *KeyPostingsBytes) AddUnsorted(thekey []byte, id int) error	The same key can be added any number of times
+		func (t *KeyPostingsBytes) Build() error							Groups the IDs of each key. It can be run again after more AddUnsorted.
+		func (t *KeyPostingsBytes) Reset() bool								Returns false if the structure is empty (Len() == 0)
+		func (t *KeyPostingsBytes) Next() ([]byte, []int, bool)				Returns: key, IDs, EOF (true = EOF)
+		func (t *KeyPostingsBytes) Keys() [][]byte							Returns slice containing all the keys in order
+		func (t *KeyPostingsBytes) Write(w custom.Interface)					The IDs are delta encoded
+		func (t *KeyPostingsBytes) Read(r *custom.Reader)
+		
+	Set operations on KeyBytes and Key[K] (KeyUint64, KeyUint32, etc.), both structures must be built
+		func (t *KeyBytes) Union(other *KeyBytes) *KeyBytes					Returns a new built structure with the keys in either
+		func (t *KeyBytes) Intersect(other *KeyBytes) *KeyBytes				Returns a new built structure with the keys in both
+		func (t *KeyBytes) Difference(other *KeyBytes) *KeyBytes			Returns a new built structure with the keys in t but not in other
+		func (t *KeyBytes) SymmetricDifference(other *KeyBytes) *KeyBytes	Returns a new built structure with the keys in only one of them
+		func (t *KeyBytes) UnionLen(other *KeyBytes) int					Returns the number of keys Union would return without allocating. Also IntersectLen, DifferenceLen & SymmetricDifferenceLen.
+		
+	Merge on KeyValBytes, CounterBytes, KeyVal[K, V] and Counter[K, N] (KeyValUint64, CounterUint64, etc.), both structures must be built
+		func (t *CounterBytes) Merge(other *CounterBytes, resolve func(a, b int) int) *CounterBytes		Returns a new built structure with the keys of both. The value of a key in both is resolve(t's value, other's value).
+		func (t *CounterUint64) Merge(other *CounterUint64, resolve func(a, b int) int) *CounterUint64		The Counter types (CounterUint64, CounterInt8, etc.) return their own type
+		func MergeCounterBytes(shards ...*CounterBytes) *CounterBytes						Merges any number of built shards in one pass, summing the values of identical keys
+		func MergeCounters[K, N](shards ...*Counter[K, N]) *Counter[K, N]					The same for Counter[K, N]
+		func MergeCountersUint64(shards ...*CounterUint64) *CounterUint64				The same for CounterUint64, and MergeCountersUint32, MergeCountersInt8, etc. for the other Counter types
 
 */
 
@@ -150,6 +303,7 @@ type KeyBytes struct {
  limit48 [8][][6]uint64
  limit56 [8][][7]uint64
  limit64 [8][][8]uint64
+ overflow [][]byte // where len(word) > 64, these are stored as they are
 // The order vars are used only when using AddSorted & Build. Build clears them. They are used for remembering the order that the keys were added in so the remap can be returned to the user by Build.
  order8 [8][]int
  order16 [8][]int
@@ -159,18 +313,42 @@ type KeyBytes struct {
  order48 [8][]int
  order56 [8][]int
  order64 [8][]int
+ orderOverflow []int
  count [64]int // Used to convert limit maps to the 1D array value indicating where the value exists
  total int
 // Used for iterating through all of it
  onlimit int
  on8 int
  oncursor int
+ lex lexOrder // used by ResetLex & NextLex
+}
+
+// bytesOrString is the key of the bytes types, so KeyString etc. can share them without converting each key.
+type bytesOrString interface {
+	string | []byte
+}
+
+// stringBytes returns the bytes of the string without copying them, they must not be modified.
+func stringBytes(s string) []byte {
+	return unsafe.Slice(unsafe.StringData(s), len(s))
+}
+
+// nulKey is how the key "\x00" is stored in limit8[0]. The empty key is stored as 0, as it always has been,
+// and as a single byte can only be 0 - 255 the two can't be confused. It is sorted after the other single bytes.
+const nulKey = 256
+
+// key2uint64 is bytes2uint64 for a whole key, so that the key "\x00" is nulKey. The last chunk of a longer key is never empty so it can be 0.
+func key2uint64[K bytesOrString](word K) (uint64, int) {
+	if len(word) == 1 && word[0] == 0 {
+		return nulKey, 0
+	}
+	return bytes2uint64(word)
 }
 
-func bytes2uint64(word []byte) (uint64, int) {
+func bytes2uint64[K bytesOrString](word K) (uint64, int) {
 	switch len(word) {
 		case 0:
-			return 0, 0 // an empty slice is sorted with the single characters
+			return 0, 0
 		case 1:
 			return uint64(word[0]), 0
 		case 2:
@@ -202,173 +380,231 @@ func uint642bytes(word []byte, v uint64) {
 }
 
 
-func uint642bytesend(word []byte, v uint64) int {
-	if v >> 56 != 0 {
-		word[7] = byte(v & 255)
-		word[6] = byte((v >> 8) & 255)
-		word[5] = byte((v >> 16) & 255)
-		word[4] = byte((v >> 24) & 255)
-		word[3] = byte((v >> 32) & 255)
-		word[2] = byte((v >> 40) & 255)
-		word[1] = byte((v >> 48) & 255)
-		word[0] = byte((v >> 56) & 255)
-		return 8
-	}
-	if v >> 48 != 0 {
-		word[6] = byte(v & 255)
-		word[5] = byte((v >> 8) & 255)
-		word[4] = byte((v >> 16) & 255)
-		word[3] = byte((v >> 24) & 255)
-		word[2] = byte((v >> 32) & 255)
-		word[1] = byte((v >> 40) & 255)
-		word[0] = byte((v >> 48) & 255)
-		return 7
-	}
-	if v >> 40 != 0 {
-		word[5] = byte(v & 255)
-		word[4] = byte((v >> 8) & 255)
-		word[3] = byte((v >> 16) & 255)
-		word[2] = byte((v >> 24) & 255)
-		word[1] = byte((v >> 32) & 255)
-		word[0] = byte((v >> 40) & 255)
-		return 6
-	}
-	if v >> 32 != 0 {
-		word[4] = byte(v & 255)
-		word[3] = byte((v >> 8) & 255)
-		word[2] = byte((v >> 16) & 255)
-		word[1] = byte((v >> 24) & 255)
-		word[0] = byte((v >> 32) & 255)
-		return 5
-	}
-	if v >> 24 != 0 {
-		word[3] = byte(v & 255)
-		word[2] = byte((v >> 8) & 255)
-		word[1] = byte((v >> 16) & 255)
-		word[0] = byte((v >> 24) & 255)
-		return 4
-	}
-	if v >> 16 != 0 {
-		word[2] = byte(v & 255)
-		word[1] = byte((v >> 8) & 255)
-		word[0] = byte((v >> 16) & 255)
-		return 3
-	}
-	if v >> 8 != 0 {
-		word[1] = byte(v & 255)
-		word[0] = byte((v >> 8) & 255)
-		return 2
-	}
-	if v != 0 {
-		word[0] = byte(v & 255)
+// uint642bytesend writes the last chunk of a key back out from its length class l (0 - 7).
+// The length class is needed as leading zeros are part of the key, e.g. "\x00a".
+func uint642bytesend(word []byte, v uint64, l int) int {
+	switch l {
+		case 7:
+			word[7] = byte(v & 255)
+			word[6] = byte((v >> 8) & 255)
+			word[5] = byte((v >> 16) & 255)
+			word[4] = byte((v >> 24) & 255)
+			word[3] = byte((v >> 32) & 255)
+			word[2] = byte((v >> 40) & 255)
+			word[1] = byte((v >> 48) & 255)
+			word[0] = byte((v >> 56) & 255)
+			return 8
+		case 6:
+			word[6] = byte(v & 255)
+			word[5] = byte((v >> 8) & 255)
+			word[4] = byte((v >> 16) & 255)
+			word[3] = byte((v >> 24) & 255)
+			word[2] = byte((v >> 32) & 255)
+			word[1] = byte((v >> 40) & 255)
+			word[0] = byte((v >> 48) & 255)
+			return 7
+		case 5:
+			word[5] = byte(v & 255)
+			word[4] = byte((v >> 8) & 255)
+			word[3] = byte((v >> 16) & 255)
+			word[2] = byte((v >> 24) & 255)
+			word[1] = byte((v >> 32) & 255)
+			word[0] = byte((v >> 40) & 255)
+			return 6
+		case 4:
+			word[4] = byte(v & 255)
+			word[3] = byte((v >> 8) & 255)
+			word[2] = byte((v >> 16) & 255)
+			word[1] = byte((v >> 24) & 255)
+			word[0] = byte((v >> 32) & 255)
+			return 5
+		case 3:
+			word[3] = byte(v & 255)
+			word[2] = byte((v >> 8) & 255)
+			word[1] = byte((v >> 16) & 255)
+			word[0] = byte((v >> 24) & 255)
+			return 4
+		case 2:
+			word[2] = byte(v & 255)
+			word[1] = byte((v >> 8) & 255)
+			word[0] = byte((v >> 16) & 255)
+			return 3
+		case 1:
+			word[1] = byte(v & 255)
+			word[0] = byte((v >> 8) & 255)
+			return 2
+		case 0:
+			word[0] = byte(v & 255)
+			return 1
+	}
+	return 0
+}
+func copyBytes[K bytesOrString](word K) []byte {
+	newword := make([]byte, len(word))
+	copy(newword, word)
+	return newword
+}
+
+
+// compareBytes is bytes.Compare for a string or []byte key against a stored key.
+func compareBytes[K bytesOrString](a K, b []byte) int {
+	l := len(a)
+	if len(b) < l {
+		l = len(b)
+	}
+	for i:=0; i<l; i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	if len(a) < len(b) {
+		return -1
+	}
+	if len(a) > len(b) {
 		return 1
 	}
 	return 0
 }
+// Keys longer than 64 bytes are written as their length followed by the bytes.
+func writeBytes(w custom.Interface, word []byte) {
+	w.WriteUint64Variable(uint64(len(word)))
+	for _, b := range word {
+		w.WriteByte(b)
+	}
+}
 
-func reverse8(v uint64) []byte {
+func readBytes(r *custom.Reader) []byte {
+	word := make([]byte, r.ReadUint64Variable())
+	for i := range word {
+		word[i] = r.ReadByte()
+	}
+	return word
+}
+
+// readOverflow reads the l keys longer than 64 bytes. Files written before these keys were supported have none, as l is always 0.
+func readOverflow(r *custom.Reader, l int) [][]byte {
+	if l <= 0 {
+		return nil
+	}
+	tmp := make([][]byte, l)
+	for i := range tmp {
+		tmp[i] = readBytes(r)
+	}
+	return tmp
+}
+
+func reverse8(v uint64, l int) []byte {
+	if v == 0 && l == 0 {
+		return []byte{}
+	}
 	word := make([]byte, 8)
-	i := uint642bytesend(word, v)
+	i := uint642bytesend(word, v, l)
 	return word[0:i]
 }
 
-func reverse8b(v [2]uint64) []byte {
+func reverse8b(v [2]uint64, l int) []byte {
+	if v[0] == 0 && l == 0 {
+		return []byte{}
+	}
 	word := make([]byte, 8)
-	i := uint642bytesend(word, v[0])
+	i := uint642bytesend(word, v[0], l)
 	return word[0:i]
 }
 
-func reverse16(v [2]uint64) []byte {
+func reverse16(v [2]uint64, l int) []byte {
 	word := make([]byte, 16)
 	uint642bytes(word, v[0])
-	i := uint642bytesend(word[8:], v[1])
+	i := uint642bytesend(word[8:], v[1], l)
 	return word[0 : 8 + i]
 }
 
-func reverse16b(v [3]uint64) []byte {
+func reverse16b(v [3]uint64, l int) []byte {
 	word := make([]byte, 16)
 	uint642bytes(word, v[0])
-	i := uint642bytesend(word[8:], v[1])
+	i := uint642bytesend(word[8:], v[1], l)
 	return word[0 : 8 + i]
 }
 
-func reverse24(v [3]uint64) []byte {
+func reverse24(v [3]uint64, l int) []byte {
 	word := make([]byte, 24)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
-	i := uint642bytesend(word[16:], v[2])
+	i := uint642bytesend(word[16:], v[2], l)
 	return word[0 : 16 + i]
 }
 
-func reverse24b(v [4]uint64) []byte {
+func reverse24b(v [4]uint64, l int) []byte {
 	word := make([]byte, 24)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
-	i := uint642bytesend(word[16:], v[2])
+	i := uint642bytesend(word[16:], v[2], l)
 	return word[0 : 16 + i]
 }
 
-func reverse32(v [4]uint64) []byte {
+func reverse32(v [4]uint64, l int) []byte {
 	word := make([]byte, 32)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
 	uint642bytes(word[16:], v[2])
-	i := uint642bytesend(word[24:], v[3])
+	i := uint642bytesend(word[24:], v[3], l)
 	return word[0 : 24 + i]
 }
 
-func reverse32b(v [5]uint64) []byte {
+func reverse32b(v [5]uint64, l int) []byte {
 	word := make([]byte, 32)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
 	uint642bytes(word[16:], v[2])
-	i := uint642bytesend(word[24:], v[3])
+	i := uint642bytesend(word[24:], v[3], l)
 	return word[0 : 24 + i]
 }
 
-func reverse40(v [5]uint64) []byte {
+func reverse40(v [5]uint64, l int) []byte {
 	word := make([]byte, 40)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
 	uint642bytes(word[16:], v[2])
 	uint642bytes(word[24:], v[3])
-	i := uint642bytesend(word[32:], v[4])
+	i := uint642bytesend(word[32:], v[4], l)
 	return word[0 : 32 + i]
 }
 
-func reverse40b(v [6]uint64) []byte {
+func reverse40b(v [6]uint64, l int) []byte {
 	word := make([]byte, 40)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
 	uint642bytes(word[16:], v[2])
 	uint642bytes(word[24:], v[3])
-	i := uint642bytesend(word[32:], v[4])
+	i := uint642bytesend(word[32:], v[4], l)
 	return word[0 : 32 + i]
 }
 
-func reverse48(v [6]uint64) []byte {
+func reverse48(v [6]uint64, l int) []byte {
 	word := make([]byte, 48)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
 	uint642bytes(word[16:], v[2])
 	uint642bytes(word[24:], v[3])
 	uint642bytes(word[32:], v[4])
-	i := uint642bytesend(word[40:], v[5])
+	i := uint642bytesend(word[40:], v[5], l)
 	return word[0 : 40 + i]
 }
 
-func reverse48b(v [7]uint64) []byte {
+func reverse48b(v [7]uint64, l int) []byte {
 	word := make([]byte, 48)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
 	uint642bytes(word[16:], v[2])
 	uint642bytes(word[24:], v[3])
 	uint642bytes(word[32:], v[4])
-	i := uint642bytesend(word[40:], v[5])
+	i := uint642bytesend(word[40:], v[5], l)
 	return word[0 : 40 + i]
 }
 
-func reverse56(v [7]uint64) []byte {
+func reverse56(v [7]uint64, l int) []byte {
 	word := make([]byte, 56)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
@@ -376,11 +612,11 @@ func reverse56(v [7]uint64) []byte {
 	uint642bytes(word[24:], v[3])
 	uint642bytes(word[32:], v[4])
 	uint642bytes(word[40:], v[5])
-	i := uint642bytesend(word[48:], v[6])
+	i := uint642bytesend(word[48:], v[6], l)
 	return word[0 : 48 + i]
 }
 
-func reverse56b(v [8]uint64) []byte {
+func reverse56b(v [8]uint64, l int) []byte {
 	word := make([]byte, 56)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
@@ -388,11 +624,11 @@ func reverse56b(v [8]uint64) []byte {
 	uint642bytes(word[24:], v[3])
 	uint642bytes(word[32:], v[4])
 	uint642bytes(word[40:], v[5])
-	i := uint642bytesend(word[48:], v[6])
+	i := uint642bytesend(word[48:], v[6], l)
 	return word[0 : 48 + i]
 }
 
-func reverse64(v [8]uint64) []byte {
+func reverse64(v [8]uint64, l int) []byte {
 	word := make([]byte, 64)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
@@ -401,11 +637,11 @@ func reverse64(v [8]uint64) []byte {
 	uint642bytes(word[32:], v[4])
 	uint642bytes(word[40:], v[5])
 	uint642bytes(word[48:], v[6])
-	i := uint642bytesend(word[56:], v[7])
+	i := uint642bytesend(word[56:], v[7], l)
 	return word[0 : 56 + i]
 }
 
-func reverse64b(v [9]uint64) []byte {
+func reverse64b(v [9]uint64, l int) []byte {
 	word := make([]byte, 64)
 	uint642bytes(word, v[0])
 	uint642bytes(word[8:], v[1])
@@ -414,7 +650,7 @@ func reverse64b(v [9]uint64) []byte {
 	uint642bytes(word[32:], v[4])
 	uint642bytes(word[40:], v[5])
 	uint642bytes(word[48:], v[6])
-	i := uint642bytesend(word[56:], v[7])
+	i := uint642bytesend(word[56:], v[7], l)
 	return word[0 : 56 + i]
 }
 
@@ -422,15 +658,49 @@ func (t *KeyBytes) Len() int {
 	return t.total
 }
 
+// tiered returns the number of keys in limit8 to limit64, i.e. total minus the overflow.
+func (t *KeyBytes) tiered() int {
+	var l int
+	for run:=0; run<8; run++ {
+		l += len(t.limit8[run]) + len(t.limit16[run]) + len(t.limit24[run]) + len(t.limit32[run]) + len(t.limit40[run]) + len(t.limit48[run]) + len(t.limit56[run]) + len(t.limit64[run])
+	}
+	return l
+}
+
+
+// recount sets count and total from the lengths of the tiers, for when keys have been removed.
+func (t *KeyBytes) recount() {
+	var run int
+	for run=0; run<8; run++ {
+		t.count[run + 1] = len(t.limit8[run])
+		t.count[run + 9] = len(t.limit16[run])
+		t.count[run + 17] = len(t.limit24[run])
+		t.count[run + 25] = len(t.limit32[run])
+		t.count[run + 33] = len(t.limit40[run])
+		t.count[run + 41] = len(t.limit48[run])
+		t.count[run + 49] = len(t.limit56[run])
+		if run < 7 {
+			t.count[run + 57] = len(t.limit64[run])
+		}
+	}
+	for run=2; run<64; run++ {
+		t.count[run] += t.count[run-1]
+	}
+	t.total = t.tiered() + len(t.overflow)
+}
 // Find returns the index based on the key.
 func (t *KeyBytes) Find(thekey []byte) (int, bool) {
+	return findKeyBytes(t, thekey)
+}
+
+func findKeyBytes[K bytesOrString](t *KeyBytes, thekey K) (int, bool) {
 	
 	var at, min int
 	var compare uint64
 	switch (len(thekey) - 1) / 8 {
 	
 		case 0: // 0 - 8 bytes
-			a, l := bytes2uint64(thekey)
+			a, l := key2uint64(thekey)
 			cur := t.limit8[l]
 			max := len(cur) - 1
 			for min <= max {
@@ -826,19 +1096,36 @@ func (t *KeyBytes) Find(thekey []byte) (int, bool) {
 			return min + t.count[l + 56], false // doesn't exist
 		
 		default: // > 64 bytes
-			return t.total, false
+			cur := t.overflow
+			max := len(cur) - 1
+			for min <= max {
+				at = min + ((max - min) / 2)
+				if c := compareBytes(thekey, cur[at]); c < 0 {
+					max = at - 1
+					continue
+				} else if c > 0 {
+					min = at + 1
+					continue
+				}
+				return at + t.total - len(cur), true // found
+			}
+			return min + t.total - len(cur), false // doesn't exist
 	}
 }
 
 // Add is equivalent to Find and then AddAt
 func (t *KeyBytes) Add(thekey []byte) (int, bool) {
+	return addKeyBytes(t, thekey)
+}
+
+func addKeyBytes[K bytesOrString](t *KeyBytes, thekey K) (int, bool) {
 	
 	var at, min int
 	var compare uint64
 	switch (len(thekey) - 1) / 8 {
 	
 		case 0: // 0 - 8 bytes
-			a, l := bytes2uint64(thekey)
+			a, l := key2uint64(thekey)
 			cur := t.limit8[l]
 			max := len(cur) - 1
 			for min <= max {
@@ -1386,15 +1673,48 @@ func (t *KeyBytes) Add(thekey []byte) (int, bool) {
 			return min, false
 		
 		default: // > 64 bytes
-			return t.total, false
+			cur := t.overflow
+			max := len(cur) - 1
+			for min <= max {
+				at = min + ((max - min) / 2)
+				if c := compareBytes(thekey, cur[at]); c < 0 {
+					max = at - 1
+					continue
+				} else if c > 0 {
+					min = at + 1
+					continue
+				}
+				return at + t.total - len(cur), true // found
+			}
+			// Doesn't exist so add it >
+			at = min
+			min += t.total - len(cur)
+			lc := len(cur)
+			if lc == cap(cur) {
+				tmp := make([][]byte, lc + 1, (lc * 2) + 1)
+				copy(tmp, cur[0:at])
+				copy(tmp[at+1:], cur[at:])
+				cur = tmp
+			} else {
+				cur = cur[0:lc+1]
+				copy(cur[at+1:], cur[at:])
+			}
+			cur[at] = copyBytes(thekey)
+			t.overflow = cur
+			t.total++
+			return min, false
 	}
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
 func (t *KeyBytes) AddUnsorted(thekey []byte) error {
+	return addUnsortedKeyBytes(t, thekey)
+}
+
+func addUnsortedKeyBytes[K bytesOrString](t *KeyBytes, thekey K) error {
 	switch (len(thekey) - 1) / 8 {
 		case 0:
-			a, i := bytes2uint64(thekey)
+			a, i := key2uint64(thekey)
 			t.limit8[i] = append(t.limit8[i], a)
 			t.order8[i] = append(t.order8[i], t.total)
 			t.count[i + 1]++
@@ -1481,16 +1801,25 @@ func (t *KeyBytes) AddUnsorted(thekey []byte) error {
 			t.total++
 			return nil
 		default:
-			return errors.New(`Maximum key length is 64 bytes`)
+			newkey := make([]byte, len(thekey))
+			copy(newkey, thekey)
+			t.overflow = append(t.overflow, newkey)
+			t.orderOverflow = append(t.orderOverflow, t.total)
+			t.total++
+			return nil
 	}
 }
 
 // AddAt adds this key to the index in this exact position, so it does not require later rebuilding.
 func (t *KeyBytes) AddAt(thekey []byte, i int) error {
+	return addAtKeyBytes(t, thekey, i)
+}
+
+func addAtKeyBytes[K bytesOrString](t *KeyBytes, thekey K, i int) error {
 
 	switch (len(thekey) - 1) / 8 {
 		case 0:
-			a, l := bytes2uint64(thekey)
+			a, l := key2uint64(thekey)
 			i -= t.count[l]
 			cur := t.limit8[l]
 			lc := len(cur)
@@ -1694,7 +2023,24 @@ func (t *KeyBytes) AddAt(thekey []byte, i int) error {
 			return nil
 			
 		default:
-			return errors.New(`Maximum key length is 64 bytes`)
+			i -= t.total - len(t.overflow)
+			cur := t.overflow
+			lc := len(cur)
+			if lc == cap(cur) {
+				tmp := make([][]byte, lc + 1, (lc * 2) + 1)
+				copy(tmp, cur[0:i])
+				copy(tmp[i+1:], cur[i:])
+				cur = tmp
+			} else {
+				cur = cur[0:lc+1]
+				copy(cur[i+1:], cur[i:])
+			}
+			newkey := make([]byte, len(thekey))
+			copy(newkey, thekey)
+			cur[i] = newkey
+			t.overflow = cur
+			t.total++
+			return nil
 	}
 }
 
@@ -1928,6 +2274,25 @@ func (t *KeyBytes) Build() ([]int, error) {
 	}
 	}
 	
+	if l = len(t.overflow); l > 0 {
+		m := t.orderOverflow
+		if l != len(m) {
+			return nil, errors.New(`Build can only be run once. After the first time use AddAt.`)
+		}
+		temp := make(sortOverflow.Slice, l)
+		for z, k := range t.overflow {
+			temp[z] = sortOverflow.KeyVal{m[z], k}
+		}
+		t.orderOverflow = nil
+		sortOverflow.Asc(temp)
+		newkey := t.overflow
+		for i, obj := range temp {
+			imap[on] = obj.K
+			on++
+			newkey[i] = obj.V
+		}
+	}
+	
 	// Correct all the counts
 	for run=2; run<64; run++ {
 		t.count[run] += t.count[run-1]
@@ -2003,6 +2368,12 @@ func (t *KeyBytes) Optimize() {
 			t.limit64[run] = newkey
 		}
 	}
+	
+	if l = len(t.overflow); l > 0 {
+		newkey := make([][]byte, l)
+		copy(newkey, t.overflow)
+		t.overflow = newkey
+	}
 }
 
 // Reset() must be called before Next(). Returns whether there are any entries.
@@ -2024,12 +2395,13 @@ func (t *KeyBytes) forward(l int) bool {
 	t.oncursor++
 	for t.oncursor >= l {
 		t.oncursor = 0
+		if t.onlimit == 8 { // overflow was the last
+			t.Reset()
+			return true
+		}
 		if t.on8++; t.on8 == 8 {
 			t.on8 = 0
-			if t.onlimit++; t.onlimit == 8 {
-				t.Reset()
-				return true
-			}
+			t.onlimit++
 		}
 		switch t.onlimit {
 			case 0: l = len(t.limit8[t.on8])
@@ -2040,6 +2412,7 @@ func (t *KeyBytes) forward(l int) bool {
 			case 5: l = len(t.limit48[t.on8])
 			case 6: l = len(t.limit56[t.on8])
 			case 7: l = len(t.limit64[t.on8])
+			default: l = len(t.overflow)
 		}
 	}
 	return false
@@ -2049,36 +2422,48 @@ func (t *KeyBytes) Next() ([]byte, bool) {
 	switch t.onlimit {
 		case 0:
 			v := t.limit8[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit8[t.on8]))
-			return reverse8(v), eof
+			return reverse8(v, l), eof
 		case 1:
 			v := t.limit16[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit16[t.on8]))
-			return reverse16(v), eof
+			return reverse16(v, l), eof
 		case 2:
 			v := t.limit24[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit24[t.on8]))
-			return reverse24(v), eof
+			return reverse24(v, l), eof
 		case 3:
 			v := t.limit32[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit32[t.on8]))
-			return reverse32(v), eof
+			return reverse32(v, l), eof
 		case 4:
 			v := t.limit40[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit40[t.on8]))
-			return reverse40(v), eof
+			return reverse40(v, l), eof
 		case 5:
 			v := t.limit48[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit48[t.on8]))
-			return reverse48(v), eof
+			return reverse48(v, l), eof
 		case 6:
 			v := t.limit56[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit56[t.on8]))
-			return reverse56(v), eof
-		default:
+			return reverse56(v, l), eof
+		case 7:
 			v := t.limit64[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit64[t.on8]))
-			return reverse64(v), eof
+			return reverse64(v, l), eof
+		default:
+			v := t.overflow[t.oncursor]
+			eof := t.forward(len(t.overflow))
+			return copyBytes(v), eof
 	}
 }
 
@@ -2089,52 +2474,56 @@ func (t *KeyBytes) Keys() [][]byte {
 	
 	for run=0; run<8; run++ {
 		for _, v := range t.limit8[run] {
-			keys[on] = reverse8(v)
+			keys[on] = reverse8(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit16[run] {
-			keys[on] = reverse16(v)
+			keys[on] = reverse16(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit24[run] {
-			keys[on] = reverse24(v)
+			keys[on] = reverse24(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit32[run] {
-			keys[on] = reverse32(v)
+			keys[on] = reverse32(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit40[run] {
-			keys[on] = reverse40(v)
+			keys[on] = reverse40(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit48[run] {
-			keys[on] = reverse48(v)
+			keys[on] = reverse48(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit56[run] {
-			keys[on] = reverse56(v)
+			keys[on] = reverse56(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit64[run] {
-			keys[on] = reverse64(v)
+			keys[on] = reverse64(v, run)
 			on++
 		}
 	}
+	for _, v := range t.overflow {
+		keys[on] = copyBytes(v)
+		on++
+	}
 	
 	return keys
 }
@@ -2242,15 +2631,22 @@ func (t *KeyBytes) Write(w custom.Interface) {
 			w.WriteUint64(v[7])
 		}
 	}
-	
+	// Write t.overflow, the number of keys is whatever remains of total
+	for _, v := range t.overflow {
+		writeBytes(w, v)
+	}
 }
 
 func (t *KeyBytes) Read(r *custom.Reader) {
+	t.read(r, int(r.ReadUint64Variable()))
+}
+
+// read is Read once the total has already been read, which lets the Runes types put a header before it.
+func (t *KeyBytes) read(r *custom.Reader, total int) {
 	var run int
 	var i, l, a, b, c, d, e, f, g, h uint64
 
-	// Write total
-	t.total = int(r.ReadUint64Variable())
+	t.total = total
 	
 	// Read count
 	for i=0; i<64; i++ {
@@ -2364,6 +2760,8 @@ func (t *KeyBytes) Read(r *custom.Reader) {
 		}
 		t.limit64[run] = tmp
 	}
+	// Read t.overflow
+	t.overflow = readOverflow(r, t.total - t.tiered())
 }
 
 // ---------- KeyValBytes ----------
@@ -2379,17 +2777,32 @@ type KeyValBytes struct {
  limit48 [8][][7]uint64
  limit56 [8][][8]uint64
  limit64 [8][][9]uint64
+ overflow sortOverflow.Slice // where len(word) > 64, K is the value
  total int
 // Used for iterating through all of it
  onlimit int
  on8 int
  oncursor int
+// Used for iterating by value
+ byvalue valueOrder
+ byoffsets [65]int
+ lex lexOrder // used by ResetLex & NextLex
+ valindex []int // positions sorted by value, used by FindByValue
 }
 
 func (t *KeyValBytes) Len() int {
 	return t.total
 }
 
+// tiered returns the number of keys in limit8 to limit64, i.e. total minus the overflow.
+func (t *KeyValBytes) tiered() int {
+	var l int
+	for run:=0; run<8; run++ {
+		l += len(t.limit8[run]) + len(t.limit16[run]) + len(t.limit24[run]) + len(t.limit32[run]) + len(t.limit40[run]) + len(t.limit48[run]) + len(t.limit56[run]) + len(t.limit64[run])
+	}
+	return l
+}
+
 func (t *KeyValBytes) GreatestVal() int {
 	var l, i2, this int
 	var max int = -9223372036854775808
@@ -2451,18 +2864,27 @@ func (t *KeyValBytes) GreatestVal() int {
 			}
 		}
 	}
+	for _, v := range t.overflow {
+		if v.K > max {
+			max = v.K
+		}
+	}
 	return max
 }
 
 // Find returns the index based on the key.
 func (t *KeyValBytes) Find(thekey []byte) (int, bool) {
+	return findKeyValBytes(t, thekey)
+}
+
+func findKeyValBytes[K bytesOrString](t *KeyValBytes, thekey K) (int, bool) {
 	
 	var at, min int
 	var compare uint64
 	switch (len(thekey) - 1) / 8 {
 	
 		case 0: // 0 - 8 bytes
-			a, l := bytes2uint64(thekey)
+			a, l := key2uint64(thekey)
 			cur := t.limit8[l]
 			max := len(cur) - 1
 			for min <= max {
@@ -2858,19 +3280,37 @@ func (t *KeyValBytes) Find(thekey []byte) (int, bool) {
 			return 0, false // doesn't exist
 		
 		default: // > 64 bytes
-			return t.total, false
+			cur := t.overflow
+			max := len(cur) - 1
+			for min <= max {
+				at = min + ((max - min) / 2)
+				if c := compareBytes(thekey, cur[at].V); c < 0 {
+					max = at - 1
+					continue
+				} else if c > 0 {
+					min = at + 1
+					continue
+				}
+				return cur[at].K, true // found
+			}
+			return 0, false // doesn't exist
 	}
 }
 
 // Modifies the value of the key by running it through the provided function.
 func (t *KeyValBytes) Update(thekey []byte, fn func(int) int) bool {
+	return updateKeyValBytes(t, thekey, fn)
+}
+
+func updateKeyValBytes[K bytesOrString](t *KeyValBytes, thekey K, fn func(int) int) bool {
+	t.valindex = nil
 	
 	var at, min int
 	var compare uint64
 	switch (len(thekey) - 1) / 8 {
 	
 		case 0: // 0 - 8 bytes
-			a, l := bytes2uint64(thekey)
+			a, l := key2uint64(thekey)
 			cur := t.limit8[l]
 			max := len(cur) - 1
 			for min <= max {
@@ -3274,12 +3714,27 @@ func (t *KeyValBytes) Update(thekey []byte, fn func(int) int) bool {
 			return false // doesn't exist
 		
 		default: // > 64 bytes
-			return false
+			cur := t.overflow
+			max := len(cur) - 1
+			for min <= max {
+				at = min + ((max - min) / 2)
+				if c := compareBytes(thekey, cur[at].V); c < 0 {
+					max = at - 1
+					continue
+				} else if c > 0 {
+					min = at + 1
+					continue
+				}
+				cur[at].K = fn(cur[at].K)
+				return true // found
+			}
+			return false // doesn't exist
 	}
 }
 
 // Modifies all values by running each through the provided function.
 func (t *KeyValBytes) UpdateAll(fn func(int) int) {
+	t.valindex = nil
 	var run, l, i int
 	for run=0; run<8; run++ {
 		tmp := t.limit8[run]
@@ -3337,17 +3792,27 @@ func (t *KeyValBytes) UpdateAll(fn func(int) int) {
 			tmp[i][8] = uint64(fn(int(tmp[i][8])))
 		}
 	}
+	tmp := t.overflow
+	l = len(tmp)
+	for i=0; i<l; i++ {
+		tmp[i].K = fn(tmp[i].K)
+	}
 }
 
 // Add is equivalent to Find and then AddAt
 func (t *KeyValBytes) Add(thekey []byte, theval int) bool {
+	return addKeyValBytes(t, thekey, theval)
+}
+
+func addKeyValBytes[K bytesOrString](t *KeyValBytes, thekey K, theval int) bool {
+	t.valindex = nil
 	
 	var at, min int
 	var compare uint64
 	switch (len(thekey) - 1) / 8 {
 	
 		case 0: // 0 - 8 bytes
-			a, l := bytes2uint64(thekey)
+			a, l := key2uint64(thekey)
 			cur := t.limit8[l]
 			max := len(cur) - 1
 			for min <= max {
@@ -3887,15 +4352,51 @@ func (t *KeyValBytes) Add(thekey []byte, theval int) bool {
 			return false
 		
 		default: // > 64 bytes
+			cur := t.overflow
+			max := len(cur) - 1
+			for min <= max {
+				at = min + ((max - min) / 2)
+				if c := compareBytes(thekey, cur[at].V); c < 0 {
+					max = at - 1
+					continue
+				} else if c > 0 {
+					min = at + 1
+					continue
+				}
+				if cur[at].K != theval {
+					cur[at].K = theval
+				}
+				return true // found
+			}
+			// Doesn't exist so add it >
+			at = min
+			lc := len(cur)
+			if lc == cap(cur) {
+				tmp := make(sortOverflow.Slice, lc + 1, (lc * 2) + 1)
+				copy(tmp, cur[0:at])
+				copy(tmp[at+1:], cur[at:])
+				cur = tmp
+			} else {
+				cur = cur[0:lc+1]
+				copy(cur[at+1:], cur[at:])
+			}
+			cur[at] = sortOverflow.KeyVal{theval, copyBytes(thekey)}
+			t.overflow = cur
+			t.total++
 			return false
 	}
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
 func (t *KeyValBytes) AddUnsorted(thekey []byte, theval int) error {
+	return addUnsortedKeyValBytes(t, thekey, theval)
+}
+
+func addUnsortedKeyValBytes[K bytesOrString](t *KeyValBytes, thekey K, theval int) error {
+	t.valindex = nil
 	switch (len(thekey) - 1) / 8 {
 		case 0:
-			a, i := bytes2uint64(thekey)
+			a, i := key2uint64(thekey)
 			t.limit8[i] = append(t.limit8[i], [2]uint64{a, uint64(theval)})
 			t.total++
 			return nil
@@ -3964,12 +4465,15 @@ func (t *KeyValBytes) AddUnsorted(thekey []byte, theval int) error {
 			t.total++
 			return nil
 		default:
-			return errors.New(`Maximum key length is 64 bytes`)
+			t.overflow = append(t.overflow, sortOverflow.KeyVal{theval, copyBytes(thekey)})
+			t.total++
+			return nil
 	}
 }
 
 // Build sorts the keys
 func (t *KeyValBytes) Build() {
+	t.valindex = nil
 
 	var run int
 	
@@ -4020,6 +4524,10 @@ func (t *KeyValBytes) Build() {
 			sortLimitVal64.Asc(sortLimitVal64.Slice(t.limit64[run]))
 		}
 	}
+	
+	if len(t.overflow) > 0 {
+		sortOverflow.Asc(t.overflow)
+	}
 }
 
 func (t *KeyValBytes) Optimize() {
@@ -4089,6 +4597,12 @@ func (t *KeyValBytes) Optimize() {
 			t.limit64[run] = newkey
 		}
 	}
+	
+	if l = len(t.overflow); l > 0 {
+		newkey := make(sortOverflow.Slice, l)
+		copy(newkey, t.overflow)
+		t.overflow = newkey
+	}
 }
 
 // Reset() must be called before Next(). Returns whether there are any entries.
@@ -4110,12 +4624,13 @@ func (t *KeyValBytes) forward(l int) bool {
 	t.oncursor++
 	for t.oncursor >= l {
 		t.oncursor = 0
+		if t.onlimit == 8 { // overflow was the last
+			t.Reset()
+			return true
+		}
 		if t.on8++; t.on8 == 8 {
 			t.on8 = 0
-			if t.onlimit++; t.onlimit == 8 {
-				t.Reset()
-				return true
-			}
+			t.onlimit++
 		}
 		switch t.onlimit {
 			case 0: l = len(t.limit8[t.on8])
@@ -4126,6 +4641,7 @@ func (t *KeyValBytes) forward(l int) bool {
 			case 5: l = len(t.limit48[t.on8])
 			case 6: l = len(t.limit56[t.on8])
 			case 7: l = len(t.limit64[t.on8])
+			default: l = len(t.overflow)
 		}
 	}
 	return false
@@ -4135,36 +4651,48 @@ func (t *KeyValBytes) Next() ([]byte, int, bool) {
 	switch t.onlimit {
 		case 0:
 			v := t.limit8[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit8[t.on8]))
-			return reverse8b(v), int(v[1]), eof
+			return reverse8b(v, l), int(v[1]), eof
 		case 1:
 			v := t.limit16[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit16[t.on8]))
-			return reverse16b(v), int(v[2]), eof
+			return reverse16b(v, l), int(v[2]), eof
 		case 2:
 			v := t.limit24[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit24[t.on8]))
-			return reverse24b(v), int(v[3]), eof
+			return reverse24b(v, l), int(v[3]), eof
 		case 3:
 			v := t.limit32[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit32[t.on8]))
-			return reverse32b(v), int(v[4]), eof
+			return reverse32b(v, l), int(v[4]), eof
 		case 4:
 			v := t.limit40[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit40[t.on8]))
-			return reverse40b(v), int(v[5]), eof
+			return reverse40b(v, l), int(v[5]), eof
 		case 5:
 			v := t.limit48[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit48[t.on8]))
-			return reverse48b(v), int(v[6]), eof
+			return reverse48b(v, l), int(v[6]), eof
 		case 6:
 			v := t.limit56[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit56[t.on8]))
-			return reverse56b(v), int(v[7]), eof
-		default:
+			return reverse56b(v, l), int(v[7]), eof
+		case 7:
 			v := t.limit64[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit64[t.on8]))
-			return reverse64b(v), int(v[8]), eof
+			return reverse64b(v, l), int(v[8]), eof
+		default:
+			v := t.overflow[t.oncursor]
+			eof := t.forward(len(t.overflow))
+			return copyBytes(v.V), v.K, eof
 	}
 }
 
@@ -4175,52 +4703,56 @@ func (t *KeyValBytes) Keys() [][]byte {
 	
 	for run=0; run<8; run++ {
 		for _, v := range t.limit8[run] {
-			keys[on] = reverse8b(v)
+			keys[on] = reverse8b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit16[run] {
-			keys[on] = reverse16b(v)
+			keys[on] = reverse16b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit24[run] {
-			keys[on] = reverse24b(v)
+			keys[on] = reverse24b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit32[run] {
-			keys[on] = reverse32b(v)
+			keys[on] = reverse32b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit40[run] {
-			keys[on] = reverse40b(v)
+			keys[on] = reverse40b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit48[run] {
-			keys[on] = reverse48b(v)
+			keys[on] = reverse48b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit56[run] {
-			keys[on] = reverse56b(v)
+			keys[on] = reverse56b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit64[run] {
-			keys[on] = reverse64b(v)
+			keys[on] = reverse64b(v, run)
 			on++
 		}
 	}
+	for _, v := range t.overflow {
+		keys[on] = copyBytes(v.V)
+		on++
+	}
 	
 	return keys
 }
@@ -4229,8 +4761,12 @@ func (t *KeyValBytes) Keys() [][]byte {
 func (t *KeyValBytes) Write(w custom.Interface) {
 	var run int
 
-	// Write total
-	w.WriteUint64Variable(uint64(t.total))
+	// Write total, flagged if the value index follows the keys
+	if t.valindex != nil {
+		w.WriteUint64Variable(uint64(t.total) | valueIndexFlag)
+	} else {
+		w.WriteUint64Variable(uint64(t.total))
+	}
 	
 	// Write t.limit8
 	for run=0; run<8; run++ {
@@ -4332,14 +4868,26 @@ func (t *KeyValBytes) Write(w custom.Interface) {
 			w.WriteUint64(v[8])
 		}
 	}
+	// Write t.overflow, the number of keys is whatever remains of total
+	for _, v := range t.overflow {
+		writeBytes(w, v.V)
+		w.WriteUint64(uint64(v.K))
+	}
+	// Write t.valindex
+	writeValueIndex(w, t.valindex)
 }
 
 func (t *KeyValBytes) Read(r *custom.Reader) {
+	t.read(r, int(r.ReadUint64Variable()))
+}
+
+// read is Read once the total has already been read, which lets the Runes types put a header before it.
+func (t *KeyValBytes) read(r *custom.Reader, total int) {
 	var run int
 	var i, l, a, b, c, d, e, f, g, h, z uint64
 
-	// Write total
-	t.total = int(r.ReadUint64Variable())
+	indexed := uint64(total) & valueIndexFlag != 0
+	t.total = int(uint64(total) &^ valueIndexFlag)
 	
 	// Read t.limit8
 	for run=0; run<8; run++ {
@@ -4457,6 +5005,27 @@ func (t *KeyValBytes) Read(r *custom.Reader) {
 		}
 		t.limit64[run] = tmp
 	}
+	// Read t.overflow
+	t.overflow = nil
+	if l = uint64(t.total - t.tiered()); l > 0 {
+		tmp := make(sortOverflow.Slice, l)
+		for i=0; i<l; i++ {
+			a := readBytes(r)
+			tmp[i] = sortOverflow.KeyVal{int(r.ReadUint64()), a}
+		}
+		t.overflow = tmp
+	}
+	// Read t.valindex
+	t.valindex = nil
+	if indexed {
+		vals := make([]int, t.total)
+		t.eachValue(func(i, v int) {
+			vals[i] = v
+		})
+		t.valindex = readValueIndex(r, t.total, func(a, b int) int {
+			return cmp.Compare(vals[a], vals[b])
+		})
+	}
 }
 
 // ---------- CounterBytes ----------
@@ -4472,11 +5041,16 @@ type CounterBytes struct {
  limit48 [8][][7]uint64
  limit56 [8][][8]uint64
  limit64 [8][][9]uint64
+ overflow sortOverflow.Slice // where len(word) > 64, K is the value
  total int
 // Used for iterating through all of it
  onlimit int
  on8 int
  oncursor int
+// Used for iterating by value
+ byvalue valueOrder
+ byoffsets [65]int
+ lex lexOrder // used by ResetLex & NextLex
 }
 
 func (t *CounterBytes) KeyBytes() *KeyBytes {
@@ -4585,6 +5159,13 @@ func (t *CounterBytes) KeyBytes() *KeyBytes {
 			obj.count[run + 57] = len(cpy)
 		}
 	}
+	if len(t.overflow) > 0 {
+		cpy := make([][]byte, len(t.overflow))
+		for i, v := range t.overflow {
+			cpy[i] = v.V
+		}
+		obj.overflow = cpy
+	}
 	// Correct all the counts
 	for run=2; run<64; run++ {
 		obj.count[run] += obj.count[run-1]
@@ -4636,6 +5217,11 @@ func (t *CounterBytes) KeyValBytes() *KeyValBytes {
 		copy(cpy, t.limit64[run])
 		obj.limit64[run] = cpy
 	}
+	if len(t.overflow) > 0 {
+		cpy := make(sortOverflow.Slice, len(t.overflow))
+		copy(cpy, t.overflow)
+		obj.overflow = cpy
+	}
 	return obj
 }
 
@@ -4643,15 +5229,28 @@ func (t *CounterBytes) Len() int {
 	return t.total
 }
 
+// tiered returns the number of keys in limit8 to limit64, i.e. total minus the overflow.
+func (t *CounterBytes) tiered() int {
+	var l int
+	for run:=0; run<8; run++ {
+		l += len(t.limit8[run]) + len(t.limit16[run]) + len(t.limit24[run]) + len(t.limit32[run]) + len(t.limit40[run]) + len(t.limit48[run]) + len(t.limit56[run]) + len(t.limit64[run])
+	}
+	return l
+}
+
 // Find returns the index based on the key.
 func (t *CounterBytes) Find(thekey []byte) (int, bool) {
+	return findCounterBytes(t, thekey)
+}
+
+func findCounterBytes[K bytesOrString](t *CounterBytes, thekey K) (int, bool) {
 	
 	var at, min int
 	var compare uint64
 	switch (len(thekey) - 1) / 8 {
 	
 		case 0: // 0 - 8 bytes
-			a, l := bytes2uint64(thekey)
+			a, l := key2uint64(thekey)
 			cur := t.limit8[l]
 			max := len(cur) - 1
 			for min <= max {
@@ -5047,19 +5646,36 @@ func (t *CounterBytes) Find(thekey []byte) (int, bool) {
 			return 0, false // doesn't exist
 		
 		default: // > 64 bytes
-			return t.total, false
+			cur := t.overflow
+			max := len(cur) - 1
+			for min <= max {
+				at = min + ((max - min) / 2)
+				if c := compareBytes(thekey, cur[at].V); c < 0 {
+					max = at - 1
+					continue
+				} else if c > 0 {
+					min = at + 1
+					continue
+				}
+				return cur[at].K, true // found
+			}
+			return 0, false // doesn't exist
 	}
 }
 
 // Modifies the value of the key by running it through the provided function.
 func (t *CounterBytes) Update(thekey []byte, fn func(int) int) bool {
+	return updateCounterBytes(t, thekey, fn)
+}
+
+func updateCounterBytes[K bytesOrString](t *CounterBytes, thekey K, fn func(int) int) bool {
 	
 	var at, min int
 	var compare uint64
 	switch (len(thekey) - 1) / 8 {
 	
 		case 0: // 0 - 8 bytes
-			a, l := bytes2uint64(thekey)
+			a, l := key2uint64(thekey)
 			cur := t.limit8[l]
 			max := len(cur) - 1
 			for min <= max {
@@ -5463,7 +6079,21 @@ func (t *CounterBytes) Update(thekey []byte, fn func(int) int) bool {
 			return false // doesn't exist
 		
 		default: // > 64 bytes
-			return false
+			cur := t.overflow
+			max := len(cur) - 1
+			for min <= max {
+				at = min + ((max - min) / 2)
+				if c := compareBytes(thekey, cur[at].V); c < 0 {
+					max = at - 1
+					continue
+				} else if c > 0 {
+					min = at + 1
+					continue
+				}
+				cur[at].K = fn(cur[at].K)
+				return true // found
+			}
+			return false // doesn't exist
 	}
 }
 
@@ -5526,13 +6156,22 @@ func (t *CounterBytes) UpdateAll(fn func(int) int) {
 			tmp[i][8] = uint64(fn(int(tmp[i][8])))
 		}
 	}
+	tmp := t.overflow
+	l = len(tmp)
+	for i=0; i<l; i++ {
+		tmp[i].K = fn(tmp[i].K)
+	}
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
 func (t *CounterBytes) Add(thekey []byte, theval int) error {
+	return addCounterBytes(t, thekey, theval)
+}
+
+func addCounterBytes[K bytesOrString](t *CounterBytes, thekey K, theval int) error {
 	switch (len(thekey) - 1) / 8 {
 		case 0:
-			a, i := bytes2uint64(thekey)
+			a, i := key2uint64(thekey)
 			t.limit8[i] = append(t.limit8[i], [2]uint64{a, uint64(theval)})
 			t.total++
 			return nil
@@ -5601,33 +6240,41 @@ func (t *CounterBytes) Add(thekey []byte, theval int) error {
 			t.total++
 			return nil
 		default:
-			return errors.New(`Maximum key length is 64 bytes`)
+			t.overflow = append(t.overflow, sortOverflow.KeyVal{theval, copyBytes(thekey)})
+			t.total++
+			return nil
 	}
 }
 
 func (t *CounterBytes) Build() {
+	t.build(sumInt)
+}
+
+// build is Build with the values added together by sum, which lets the values be something other than ints.
+func (t *CounterBytes) build(sum func(uint64, uint64) uint64) {
 
-	var l, run, n, total, on int
+	var l, run, total, on int
+	var n uint64
 	
 	for run=0; run<8; run++ {
 		if l = len(t.limit8[run]); l > 0 {
 			var temp sortLimitVal8.Slice = t.limit8[run]
 			sortLimitVal8.Asc(temp)
 			this := temp[0]
-			n = int(temp[0][1])
+			n = temp[0][1]
 			on = 0
 			for _, k := range temp[1:] {
 				if k[0] == this[0] {
-					n += int(k[1])
+					n = sum(n, k[1])
 				} else {
-					this[1] = uint64(n)
+					this[1] = n
 					temp[on] = this
 					on++
 					this = k
-					n = int(k[1])
+					n = k[1]
 				}
 			}
-			this[1] = uint64(n)
+			this[1] = n
 			temp[on] = this
 			on++
 			t.limit8[run] = temp[0:on]
@@ -5640,20 +6287,20 @@ func (t *CounterBytes) Build() {
 			var temp sortLimitVal16.Slice = t.limit16[run]
 			sortLimitVal16.Asc(temp)
 			this := temp[0]
-			n = int(temp[0][2])
+			n = temp[0][2]
 			on = 0
 			for _, k := range temp[1:] {
 				if k[0] == this[0] && k[1] == this[1] {
-					n += int(k[2])
+					n = sum(n, k[2])
 				} else {
-					this[2] = uint64(n)
+					this[2] = n
 					temp[on] = this
 					on++
 					this = k
-					n = int(k[2])
+					n = k[2]
 				}
 			}
-			this[2] = uint64(n)
+			this[2] = n
 			temp[on] = this
 			on++
 			t.limit16[run] = temp[0:on]
@@ -5666,20 +6313,20 @@ func (t *CounterBytes) Build() {
 			var temp sortLimitVal24.Slice = t.limit24[run]
 			sortLimitVal24.Asc(temp)
 			this := temp[0]
-			n = int(temp[0][3])
+			n = temp[0][3]
 			on = 0
 			for _, k := range temp[1:] {
 				if k[0] == this[0] && k[1] == this[1] && k[2] == this[2] {
-					n += int(k[3])
+					n = sum(n, k[3])
 				} else {
-					this[3] = uint64(n)
+					this[3] = n
 					temp[on] = this
 					on++
 					this = k
-					n = int(k[3])
+					n = k[3]
 				}
 			}
-			this[3] = uint64(n)
+			this[3] = n
 			temp[on] = this
 			on++
 			t.limit24[run] = temp[0:on]
@@ -5692,20 +6339,20 @@ func (t *CounterBytes) Build() {
 			var temp sortLimitVal32.Slice = t.limit32[run]
 			sortLimitVal32.Asc(temp)
 			this := temp[0]
-			n = int(temp[0][4])
+			n = temp[0][4]
 			on = 0
 			for _, k := range temp[1:] {
 				if k[0] == this[0] && k[1] == this[1] && k[2] == this[2] && k[3] == this[3] {
-					n += int(k[4])
+					n = sum(n, k[4])
 				} else {
-					this[4] = uint64(n)
+					this[4] = n
 					temp[on] = this
 					on++
 					this = k
-					n = int(k[4])
+					n = k[4]
 				}
 			}
-			this[4] = uint64(n)
+			this[4] = n
 			temp[on] = this
 			on++
 			t.limit32[run] = temp[0:on]
@@ -5718,20 +6365,20 @@ func (t *CounterBytes) Build() {
 			var temp sortLimitVal40.Slice = t.limit40[run]
 			sortLimitVal40.Asc(temp)
 			this := temp[0]
-			n = int(temp[0][5])
+			n = temp[0][5]
 			on = 0
 			for _, k := range temp[1:] {
 				if k[0] == this[0] && k[1] == this[1] && k[2] == this[2] && k[3] == this[3] && k[4] == this[4] {
-					n += int(k[5])
+					n = sum(n, k[5])
 				} else {
-					this[5] = uint64(n)
+					this[5] = n
 					temp[on] = this
 					on++
 					this = k
-					n = int(k[5])
+					n = k[5]
 				}
 			}
-			this[5] = uint64(n)
+			this[5] = n
 			temp[on] = this
 			on++
 			t.limit40[run] = temp[0:on]
@@ -5744,20 +6391,20 @@ func (t *CounterBytes) Build() {
 			var temp sortLimitVal48.Slice = t.limit48[run]
 			sortLimitVal48.Asc(temp)
 			this := temp[0]
-			n = int(temp[0][6])
+			n = temp[0][6]
 			on = 0
 			for _, k := range temp[1:] {
 				if k[0] == this[0] && k[1] == this[1] && k[2] == this[2] && k[3] == this[3] && k[4] == this[4] && k[5] == this[5] {
-					n += int(k[6])
+					n = sum(n, k[6])
 				} else {
-					this[6] = uint64(n)
+					this[6] = n
 					temp[on] = this
 					on++
 					this = k
-					n = int(k[6])
+					n = k[6]
 				}
 			}
-			this[6] = uint64(n)
+			this[6] = n
 			temp[on] = this
 			on++
 			t.limit48[run] = temp[0:on]
@@ -5770,20 +6417,20 @@ func (t *CounterBytes) Build() {
 			var temp sortLimitVal56.Slice = t.limit56[run]
 			sortLimitVal56.Asc(temp)
 			this := temp[0]
-			n = int(temp[0][7])
+			n = temp[0][7]
 			on = 0
 			for _, k := range temp[1:] {
 				if k[0] == this[0] && k[1] == this[1] && k[2] == this[2] && k[3] == this[3] && k[4] == this[4] && k[5] == this[5] && k[6] == this[6] {
-					n += int(k[7])
+					n = sum(n, k[7])
 				} else {
-					this[7] = uint64(n)
+					this[7] = n
 					temp[on] = this
 					on++
 					this = k
-					n = int(k[7])
+					n = k[7]
 				}
 			}
-			this[7] = uint64(n)
+			this[7] = n
 			temp[on] = this
 			on++
 			t.limit56[run] = temp[0:on]
@@ -5796,26 +6443,50 @@ func (t *CounterBytes) Build() {
 			var temp sortLimitVal64.Slice = t.limit64[run]
 			sortLimitVal64.Asc(temp)
 			this := temp[0]
-			n = int(temp[0][8])
+			n = temp[0][8]
 			on = 0
 			for _, k := range temp[1:] {
 				if k[0] == this[0] && k[1] == this[1] && k[2] == this[2] && k[3] == this[3] && k[4] == this[4] && k[5] == this[5] && k[6] == this[6] && k[7] == this[7] {
-					n += int(k[8])
+					n = sum(n, k[8])
 				} else {
-					this[8] = uint64(n)
+					this[8] = n
 					temp[on] = this
 					on++
 					this = k
-					n = int(k[8])
+					n = k[8]
 				}
 			}
-			this[8] = uint64(n)
+			this[8] = n
 			temp[on] = this
 			on++
 			t.limit64[run] = temp[0:on]
 			total += on
 		}
 	}
+	if l = len(t.overflow); l > 0 {
+		temp := t.overflow
+		sortOverflow.Asc(temp)
+		this := temp[0]
+		n = uint64(this.K)
+		on = 0
+		for _, k := range temp[1:] {
+			if bytes.Equal(k.V, this.V) {
+				n = sum(n, uint64(k.K))
+			} else {
+				this.K = int(n)
+				temp[on] = this
+				on++
+				this = k
+				n = uint64(k.K)
+			}
+		}
+		this.K = int(n)
+		temp[on] = this
+		on++
+		t.overflow = temp[0:on]
+		total += on
+	}
+	
 	t.total = total
 	
 }
@@ -5887,6 +6558,12 @@ func (t *CounterBytes) Optimize() {
 			t.limit64[run] = newkey
 		}
 	}
+	
+	if l = len(t.overflow); l > 0 {
+		newkey := make(sortOverflow.Slice, l)
+		copy(newkey, t.overflow)
+		t.overflow = newkey
+	}
 }
 
 // Reset() must be called before Next(). Returns whether there are any entries.
@@ -5908,12 +6585,13 @@ func (t *CounterBytes) forward(l int) bool {
 	t.oncursor++
 	for t.oncursor >= l {
 		t.oncursor = 0
+		if t.onlimit == 8 { // overflow was the last
+			t.Reset()
+			return true
+		}
 		if t.on8++; t.on8 == 8 {
 			t.on8 = 0
-			if t.onlimit++; t.onlimit == 8 {
-				t.Reset()
-				return true
-			}
+			t.onlimit++
 		}
 		switch t.onlimit {
 			case 0: l = len(t.limit8[t.on8])
@@ -5924,6 +6602,7 @@ func (t *CounterBytes) forward(l int) bool {
 			case 5: l = len(t.limit48[t.on8])
 			case 6: l = len(t.limit56[t.on8])
 			case 7: l = len(t.limit64[t.on8])
+			default: l = len(t.overflow)
 		}
 	}
 	return false
@@ -5933,36 +6612,48 @@ func (t *CounterBytes) Next() ([]byte, int, bool) {
 	switch t.onlimit {
 		case 0:
 			v := t.limit8[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit8[t.on8]))
-			return reverse8b(v), int(v[1]), eof
+			return reverse8b(v, l), int(v[1]), eof
 		case 1:
 			v := t.limit16[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit16[t.on8]))
-			return reverse16b(v), int(v[2]), eof
+			return reverse16b(v, l), int(v[2]), eof
 		case 2:
 			v := t.limit24[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit24[t.on8]))
-			return reverse24b(v), int(v[3]), eof
+			return reverse24b(v, l), int(v[3]), eof
 		case 3:
 			v := t.limit32[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit32[t.on8]))
-			return reverse32b(v), int(v[4]), eof
+			return reverse32b(v, l), int(v[4]), eof
 		case 4:
 			v := t.limit40[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit40[t.on8]))
-			return reverse40b(v), int(v[5]), eof
+			return reverse40b(v, l), int(v[5]), eof
 		case 5:
 			v := t.limit48[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit48[t.on8]))
-			return reverse48b(v), int(v[6]), eof
+			return reverse48b(v, l), int(v[6]), eof
 		case 6:
 			v := t.limit56[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit56[t.on8]))
-			return reverse56b(v), int(v[7]), eof
-		default:
+			return reverse56b(v, l), int(v[7]), eof
+		case 7:
 			v := t.limit64[t.on8][t.oncursor]
+			l := t.on8
 			eof := t.forward(len(t.limit64[t.on8]))
-			return reverse64b(v), int(v[8]), eof
+			return reverse64b(v, l), int(v[8]), eof
+		default:
+			v := t.overflow[t.oncursor]
+			eof := t.forward(len(t.overflow))
+			return copyBytes(v.V), v.K, eof
 	}
 }
 
@@ -5973,52 +6664,56 @@ func (t *CounterBytes) Keys() [][]byte {
 	
 	for run=0; run<8; run++ {
 		for _, v := range t.limit8[run] {
-			keys[on] = reverse8b(v)
+			keys[on] = reverse8b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit16[run] {
-			keys[on] = reverse16b(v)
+			keys[on] = reverse16b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit24[run] {
-			keys[on] = reverse24b(v)
+			keys[on] = reverse24b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit32[run] {
-			keys[on] = reverse32b(v)
+			keys[on] = reverse32b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit40[run] {
-			keys[on] = reverse40b(v)
+			keys[on] = reverse40b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit48[run] {
-			keys[on] = reverse48b(v)
+			keys[on] = reverse48b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit56[run] {
-			keys[on] = reverse56b(v)
+			keys[on] = reverse56b(v, run)
 			on++
 		}
 	}
 	for run=0; run<8; run++ {
 		for _, v := range t.limit64[run] {
-			keys[on] = reverse64b(v)
+			keys[on] = reverse64b(v, run)
 			on++
 		}
 	}
+	for _, v := range t.overflow {
+		keys[on] = copyBytes(v.V)
+		on++
+	}
 	
 	return keys
 }
@@ -6130,14 +6825,23 @@ func (t *CounterBytes) Write(w custom.Interface) {
 			w.WriteUint64(v[8])
 		}
 	}
+	// Write t.overflow, the number of keys is whatever remains of total
+	for _, v := range t.overflow {
+		writeBytes(w, v.V)
+		w.WriteUint64(uint64(v.K))
+	}
 }
 
 func (t *CounterBytes) Read(r *custom.Reader) {
+	t.read(r, int(r.ReadUint64Variable()))
+}
+
+// read is Read once the total has already been read, which lets the Runes types put a header before it.
+func (t *CounterBytes) read(r *custom.Reader, total int) {
 	var run int
 	var i, l, a, b, c, d, e, f, g, h, z uint64
 
-	// Write total
-	t.total = int(r.ReadUint64Variable())
+	t.total = total
 	
 	// Read t.limit8
 	for run=0; run<8; run++ {
@@ -6255,6 +6959,175 @@ func (t *CounterBytes) Read(r *custom.Reader) {
 		}
 		t.limit64[run] = tmp
 	}
+	// Read t.overflow
+	t.overflow = nil
+	if l = uint64(t.total - t.tiered()); l > 0 {
+		tmp := make(sortOverflow.Slice, l)
+		for i=0; i<l; i++ {
+			a := readBytes(r)
+			tmp[i] = sortOverflow.KeyVal{int(r.ReadUint64()), a}
+		}
+		t.overflow = tmp
+	}
+}
+
+// ---------- CounterBytesFloat64 & CounterBytesInt64 ----------
+
+/*
+	CounterBytesFloat64 and CounterBytesInt64 wrap CounterBytes with a different sum for the values.
+	The values are kept in the same uint64 column as the ints of CounterBytes, CounterBytesFloat64 keeps them as the bits of the float64.
+*/
+
+func sumInt(a, b uint64) uint64 {
+	return uint64(int(a) + int(b))
+}
+
+func sumFloat64(a, b uint64) uint64 {
+	return math.Float64bits(math.Float64frombits(a) + math.Float64frombits(b))
+}
+
+// Add this to any struct to make it binary searchable.
+type CounterBytesFloat64 struct {
+ child CounterBytes
+}
+
+// Find returns the total based on the key.
+func (t *CounterBytesFloat64) Find(thekey []byte) (float64, bool) {
+	v, ok := t.child.Find(thekey)
+	return math.Float64frombits(uint64(v)), ok
+}
+
+func (t *CounterBytesFloat64) Update(thekey []byte, fn func(float64) float64) bool {
+	return t.child.Update(thekey, func(v int) int {
+		return int(math.Float64bits(fn(math.Float64frombits(uint64(v)))))
+	})
+}
+
+func (t *CounterBytesFloat64) UpdateAll(fn func(float64) float64) {
+	t.child.UpdateAll(func(v int) int {
+		return int(math.Float64bits(fn(math.Float64frombits(uint64(v)))))
+	})
+}
+
+// Add adds this key to the end of the index for later building with Build.
+func (t *CounterBytesFloat64) Add(thekey []byte, theval float64) error {
+	return t.child.Add(thekey, int(math.Float64bits(theval)))
+}
+
+// Build sorts the keys and adds up the values of identical keys. It is always required before Find.
+func (t *CounterBytesFloat64) Build() {
+	t.child.build(sumFloat64)
+}
+
+func (t *CounterBytesFloat64) Optimize() {
+	t.child.Optimize()
+}
+
+func (t *CounterBytesFloat64) Len() int {
+	return t.child.Len()
+}
+
+func (t *CounterBytesFloat64) Reset() bool {
+	return t.child.Reset()
+}
+
+func (t *CounterBytesFloat64) Next() ([]byte, float64, bool) {
+	a, b, c := t.child.Next()
+	return a, math.Float64frombits(uint64(b)), c
+}
+
+func (t *CounterBytesFloat64) Keys() [][]byte {
+	return t.child.Keys()
+}
+
+func (t *CounterBytesFloat64) Write(w custom.Interface) {
+	t.child.Write(w)
+}
+
+func (t *CounterBytesFloat64) Read(r *custom.Reader) {
+	t.child.Read(r)
+}
+
+func (t *CounterBytesFloat64) KeyBytes() *KeyBytes {
+	return t.child.KeyBytes()
+}
+
+// Add this to any struct to make it binary searchable.
+type CounterBytesInt64 struct {
+ child CounterBytes
+}
+
+// Find returns the total based on the key.
+func (t *CounterBytesInt64) Find(thekey []byte) (int64, bool) {
+	v, ok := t.child.Find(thekey)
+	return int64(v), ok
+}
+
+func (t *CounterBytesInt64) Update(thekey []byte, fn func(int64) int64) bool {
+	return t.child.Update(thekey, func(v int) int {
+		return int(fn(int64(v)))
+	})
+}
+
+func (t *CounterBytesInt64) UpdateAll(fn func(int64) int64) {
+	t.child.UpdateAll(func(v int) int {
+		return int(fn(int64(v)))
+	})
+}
+
+// Add adds this key to the end of the index for later building with Build.
+func (t *CounterBytesInt64) Add(thekey []byte, theval int64) error {
+	return t.child.Add(thekey, int(theval))
+}
+
+// Build sorts the keys and adds up the values of identical keys. It is always required before Find.
+// It returns ErrOverflow if any total doesn't fit in an int64, that total is then wrapped around.
+func (t *CounterBytesInt64) Build() error {
+	var overflow bool
+	t.child.build(func(a, b uint64) uint64 {
+		c := int64(a) + int64(b)
+		if (int64(b) > 0 && c < int64(a)) || (int64(b) < 0 && c > int64(a)) {
+			overflow = true
+		}
+		return uint64(c)
+	})
+	if overflow {
+		return ErrOverflow
+	}
+	return nil
+}
+
+func (t *CounterBytesInt64) Optimize() {
+	t.child.Optimize()
+}
+
+func (t *CounterBytesInt64) Len() int {
+	return t.child.Len()
+}
+
+func (t *CounterBytesInt64) Reset() bool {
+	return t.child.Reset()
+}
+
+func (t *CounterBytesInt64) Next() ([]byte, int64, bool) {
+	a, b, c := t.child.Next()
+	return a, int64(b), c
+}
+
+func (t *CounterBytesInt64) Keys() [][]byte {
+	return t.child.Keys()
+}
+
+func (t *CounterBytesInt64) Write(w custom.Interface) {
+	t.child.Write(w)
+}
+
+func (t *CounterBytesInt64) Read(r *custom.Reader) {
+	t.child.Read(r)
+}
+
+func (t *CounterBytesInt64) KeyBytes() *KeyBytes {
+	return t.child.KeyBytes()
 }
 
 // ====================== runes ======================
@@ -6266,7 +7139,104 @@ func (t *CounterBytes) Read(r *custom.Reader) {
 	It is, however, more efficient to use KeyRunes than converting from runes to bytes with Go's encoding packages or via string.
 */
 
+/*
+	Runes are encoded as bytes as follows, so Latin-1 text uses one byte per rune:
+		U+0001 - U+0003		1, rune
+		< 256				rune
+		< 65536				2, low byte, high byte
+		>= 65536			3, low byte, middle byte, high byte
+	Code points 1, 2 & 3 are therefore always escape codes.
+*/
+
 func runes2bytes(word []rune) []byte {
+	// Count how many bytes are needed to represent the slice of runes
+	var l, i int
+	var r rune
+	for _, r = range word {
+		if r < 256 {
+			if r > 0 && r < 4 {
+				l += 2
+			} else {
+				l++
+			}
+		} else {
+			if r >= 65536 {
+				l += 4
+			} else {
+				l += 3
+			}
+		}
+	}
+	newword := make([]byte, l)
+	
+	// If each unicode value will fit into one byte
+	if len(word) == l {
+		for i, r = range word {
+			newword[i] = byte(r)
+		}
+		return newword
+	}
+	
+	// It's a bit more fancy
+	for _, r = range word {
+		if r < 256 {
+			if r > 0 && r < 4 {
+				newword[i] = 1 // code point 1 escapes the escape codes
+				newword[i+1] = byte(r)
+				i += 2
+			} else {
+				newword[i] = byte(r)
+				i++
+			}
+		} else {
+			if r >= 65536 {
+				newword[i] = 3 // code points 2 & 3 represent 2-3 additional characters needed
+				newword[i+1] = byte(r % 256)
+				r /= 256
+				newword[i+2] = byte(r % 256)
+				r /= 256
+				newword[i+3] = byte(r % 256)
+				i += 4
+			} else {
+				newword[i] = 2
+				newword[i+1] = byte(r % 256)
+				r /= 256
+				newword[i+2] = byte(r % 256)
+				i += 3
+			}
+		}
+	}
+	return newword
+}
+
+func bytes2runes(word []byte) []rune {
+	l := len(word)
+	newword := make([]rune, l)
+	var b byte
+	var i, on int
+	for i=0; i<l; i++ {
+		b = word[i]
+		switch b {
+			case 1:
+				newword[on] = rune(word[i+1])
+				i++
+			case 2:
+				newword[on] = (rune(word[i+2]) * 256) + rune(word[i+1])
+				i += 2
+			case 3:
+				newword[on] = (rune(word[i+3]) * 65536) + (rune(word[i+2]) * 256) + rune(word[i+1])
+				i += 3
+			default:
+				newword[on] = rune(b)
+		}
+		on++
+	}
+	return newword[0:on]
+}
+
+// runes2bytesLegacy is the original encoding, which can't tell U+0002 & U+0003 apart from the escape codes.
+// It is only used for structures read from files written before runesVersion 1.
+func runes2bytesLegacy(word []rune) []byte {
 	// Count how many bytes are needed to represent the slice of runes
 	var l, i int
 	var r rune
@@ -6317,7 +7287,7 @@ func runes2bytes(word []rune) []byte {
 	return newword
 }
 
-func bytes2runes(word []byte) []rune {
+func bytes2runesLegacy(word []byte) []rune {
 	l := len(word)
 	newword := make([]rune, l)
 	var b byte
@@ -6339,29 +7309,68 @@ func bytes2runes(word []byte) []rune {
 	return newword[0:on]
 }
 
+/*
+	The Runes types write a header before the total so that the encoding can be changed:
+	runesHeader | runesVersion. A total can never have the top bit set, so files written without the header are read as version 0.
+*/
+const runesHeader = 1 << 63
+const runesVersion = 1
+
+func writeRunesHeader(w custom.Interface, legacy bool) {
+	if legacy {
+		w.WriteUint64Variable(runesHeader) // version 0
+	} else {
+		w.WriteUint64Variable(runesHeader | runesVersion)
+	}
+}
+
+// readRunesHeader returns the total and whether the file uses the legacy encoding.
+func readRunesHeader(r *custom.Reader) (int, bool) {
+	v := r.ReadUint64Variable()
+	if v & runesHeader == 0 { // no header, this is the total
+		return int(v), true
+	}
+	return int(r.ReadUint64Variable()), v &^ runesHeader == 0
+}
+
+func encodeRunes(word []rune, legacy bool) []byte {
+	if legacy {
+		return runes2bytesLegacy(word)
+	}
+	return runes2bytes(word)
+}
+
+func decodeRunes(word []byte, legacy bool) []rune {
+	if legacy {
+		return bytes2runesLegacy(word)
+	}
+	return bytes2runes(word)
+}
+
 // Add this to any struct to make it binary searchable.
 type KeyRunes struct {
  child KeyBytes
+ legacy bool // read from a file written with the original encoding
 }
 
 // Find returns the index based on the key.
 func (t *KeyRunes) Find(thekey []rune) (int, bool) {
-	return t.child.Find(runes2bytes(thekey))
+	return t.child.Find(encodeRunes(thekey, t.legacy))
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
 func (t *KeyRunes) Add(thekey []rune) (int, bool) {
-	return t.child.Add(runes2bytes(thekey))
+	return t.child.Add(encodeRunes(thekey, t.legacy))
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
 func (t *KeyRunes) AddUnsorted(thekey []rune) error {
-	return t.child.AddUnsorted(runes2bytes(thekey))
+	return t.child.AddUnsorted(encodeRunes(thekey, t.legacy))
 }
 
 // AddAt adds this key to the index in this exact position, so it does not require later rebuilding.
 func (t *KeyRunes) AddAt(thekey []rune, i int) error {
-	return t.child.AddAt(runes2bytes(thekey), i)
+	return t.child.AddAt(encodeRunes(thekey, t.legacy), i)
 }
 
 func (t *KeyRunes) Build() ([]int, error) {
@@ -6382,48 +7391,52 @@ func (t *KeyRunes) Reset() bool {
 
 func (t *KeyRunes) Next() ([]rune, bool) {
 	a, b := t.child.Next()
-	return bytes2runes(a), b
+	return decodeRunes(a, t.legacy), b
 }
 
 func (t *KeyRunes) Keys() [][]rune {
 	keys := t.child.Keys()
 	newkeys := make([][]rune, len(keys))
 	for i, v := range keys {
-		newkeys[i] = bytes2runes(v)
+		newkeys[i] = decodeRunes(v, t.legacy)
 	}
 	return newkeys
 }
 
 func (t *KeyRunes) Write(w custom.Interface) {
+	writeRunesHeader(w, t.legacy)
 	t.child.Write(w)
 }
 
 func (t *KeyRunes) Read(r *custom.Reader) {
-	t.child.Read(r)
+	var total int
+	total, t.legacy = readRunesHeader(r)
+	t.child.read(r, total)
 }
 
 // Add this to any struct to make it binary searchable.
 type KeyValRunes struct {
  child KeyValBytes
+ legacy bool // read from a file written with the original encoding
 }
 
 // Find returns the index based on the key.
 func (t *KeyValRunes) Find(thekey []rune) (int, bool) {
-	return t.child.Find(runes2bytes(thekey))
+	return t.child.Find(encodeRunes(thekey, t.legacy))
 }
 
 func (t *KeyValRunes) Update(thekey []rune, fn func(int) int) bool {
-	return t.child.Update(runes2bytes(thekey), fn)
+	return t.child.Update(encodeRunes(thekey, t.legacy), fn)
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
 func (t *KeyValRunes) Add(thekey []rune, theval int) bool {
-	return t.child.Add(runes2bytes(thekey), theval)
+	return t.child.Add(encodeRunes(thekey, t.legacy), theval)
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
 func (t *KeyValRunes) AddUnsorted(thekey []rune, theval int) error {
-	return t.child.AddUnsorted(runes2bytes(thekey), theval)
+	return t.child.AddUnsorted(encodeRunes(thekey, t.legacy), theval)
 }
 
 func (t *KeyValRunes) Build() {
@@ -6444,43 +7457,47 @@ func (t *KeyValRunes) Reset() bool {
 
 func (t *KeyValRunes) Next() ([]rune, int, bool) {
 	a, b, c := t.child.Next()
-	return bytes2runes(a), b, c
+	return decodeRunes(a, t.legacy), b, c
 }
 
 func (t *KeyValRunes) Keys() [][]rune {
 	keys := t.child.Keys()
 	newkeys := make([][]rune, len(keys))
 	for i, v := range keys {
-		newkeys[i] = bytes2runes(v)
+		newkeys[i] = decodeRunes(v, t.legacy)
 	}
 	return newkeys
 }
 
 func (t *KeyValRunes) Write(w custom.Interface) {
+	writeRunesHeader(w, t.legacy)
 	t.child.Write(w)
 }
 
 func (t *KeyValRunes) Read(r *custom.Reader) {
-	t.child.Read(r)
+	var total int
+	total, t.legacy = readRunesHeader(r)
+	t.child.read(r, total)
 }
 
 // Add this to any struct to make it binary searchable.
 type CounterRunes struct {
  child CounterBytes
+ legacy bool // read from a file written with the original encoding
 }
 
 // Find returns the index based on the key.
 func (t *CounterRunes) Find(thekey []rune) (int, bool) {
-	return t.child.Find(runes2bytes(thekey))
+	return t.child.Find(encodeRunes(thekey, t.legacy))
 }
 
 func (t *CounterRunes) Update(thekey []rune, fn func(int) int) bool {
-	return t.child.Update(runes2bytes(thekey), fn)
+	return t.child.Update(encodeRunes(thekey, t.legacy), fn)
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
 func (t *CounterRunes) Add(thekey []rune, theval int) error {
-	return t.child.Add(runes2bytes(thekey), theval)
+	return t.child.Add(encodeRunes(thekey, t.legacy), theval)
 }
 
 func (t *CounterRunes) Build() {
@@ -6501,30 +7518,34 @@ func (t *CounterRunes) Reset() bool {
 
 func (t *CounterRunes) Next() ([]rune, int, bool) {
 	a, b, c := t.child.Next()
-	return bytes2runes(a), b, c
+	return decodeRunes(a, t.legacy), b, c
 }
 
 func (t *CounterRunes) Keys() [][]rune {
 	keys := t.child.Keys()
 	newkeys := make([][]rune, len(keys))
 	for i, v := range keys {
-		newkeys[i] = bytes2runes(v)
+		newkeys[i] = decodeRunes(v, t.legacy)
 	}
 	return newkeys
 }
 
 func (t *CounterRunes) Write(w custom.Interface) {
+	writeRunesHeader(w, t.legacy)
 	t.child.Write(w)
 }
 
 func (t *CounterRunes) Read(r *custom.Reader) {
-	t.child.Read(r)
+	var total int
+	total, t.legacy = readRunesHeader(r)
+	t.child.read(r, total)
 }
 
 func (t *CounterRunes) KeyRunes() *KeyRunes {
 	obj := new(KeyRunes)
 	child := t.child.KeyBytes()
 	obj.child = *child
+	obj.legacy = t.legacy
 	return obj
 }
 
@@ -6532,1866 +7553,579 @@ func (t *CounterRunes) KeyValRunes() *KeyValRunes {
 	obj := new(KeyValRunes)
 	child := t.child.KeyValBytes()
 	obj.child = *child
+	obj.legacy = t.legacy
 	return obj
 }
 
-// ====================== uint64 ======================
-// ---------- KeyUint64 ----------
+// ====================== strings ======================
+// ---------- KeyString ----------
 
-// Add this to any struct to make it binary searchable.
-type KeyUint64 struct {
- key []uint64
- cursor int
-}
+/*
+	KeyString wraps KeyBytes but packs the keys straight from the string, so Find does not allocate.
+	The storage and file format are exactly the same as KeyBytes.
+	Keys and Next do allocate, as the keys are not stored as strings.
+*/
 
-func (t *KeyUint64) Len() int {
-	return len(t.key)
+// Add this to any struct to make it binary searchable.
+type KeyString struct {
+ child KeyBytes
 }
 
 // Find returns the index based on the key.
-func (t *KeyUint64) Find(thekey uint64) (int, bool) {
-	var min, at int
-	var current uint64
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at]; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return at, true // found
-			}
-		}
-	}
-	return min, false // doesn't exist
+func (t *KeyString) Find(thekey string) (int, bool) {
+	return findKeyBytes(&t.child, thekey)
 }
 
-// Add is equivalent to Find and then AddAt
-func (t *KeyUint64) Add(thekey uint64) (int, bool) {
-	i, ok := t.Find(thekey)
-	if !ok {
-		t.AddAt(thekey, i)
-	}
-	return i, ok
+// Add adds this key to the index in the correct position, if it does not already exist.
+func (t *KeyString) Add(thekey string) (int, bool) {
+	return addKeyBytes(&t.child, thekey)
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *KeyUint64) AddUnsorted(thekey uint64) {
-	t.key = append(t.key, thekey)
-	return
+func (t *KeyString) AddUnsorted(thekey string) error {
+	return addUnsortedKeyBytes(&t.child, thekey)
 }
 
 // AddAt adds this key to the index in this exact position, so it does not require later rebuilding.
-func (t *KeyUint64) AddAt(thekey uint64, i int) {
-	cur := t.key
-	lc := len(cur)
-	if lc == cap(cur) {
-		tmp := make([]uint64, lc + 1, (lc * 2) + 1)
-		copy(tmp, cur[0:i])
-		copy(tmp[i+1:], cur[i:])
-		cur = tmp
-	} else {
-		cur = cur[0:lc+1]
-		copy(cur[i+1:], cur[i:])
-	}
-	cur[i] = thekey
-	t.key = cur
+func (t *KeyString) AddAt(thekey string, i int) error {
+	return addAtKeyBytes(&t.child, thekey, i)
 }
 
-// Build sorts the keys and returns an array telling you how to sort the values, you must do this yourself.
-func (t *KeyUint64) Build() []int {
-	l := len(t.key)
-	temp := make([]sortIntUint64.KeyVal, l)
-	var i int
-	var k uint64
-	for i, k = range t.key {
-		temp[i] = sortIntUint64.KeyVal{i, k}
-	}
-	sortIntUint64.Asc(temp)
-	imap := make([]int, l)
-	newkey := t.key
-	for i, obj := range temp {
-		imap[i] = obj.K
-		newkey[i] = obj.V
-	}
-	return imap
+func (t *KeyString) Build() ([]int, error) {
+	return t.child.Build()
 }
 
-func (t *KeyUint64) Optimize() {
-	temp := make([]uint64, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
+func (t *KeyString) Optimize() {
+	t.child.Optimize()
 }
 
-func (t *KeyUint64) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
+func (t *KeyString) Len() int {
+	return t.child.Len()
 }
 
-func (t *KeyUint64) Next() (uint64, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v, true
-	}
-	return v, false
+func (t *KeyString) Reset() bool {
+	return t.child.Reset()
 }
 
-func (t *KeyUint64) Keys() []uint64 {
-	return t.key
+func (t *KeyString) Next() (string, bool) {
+	a, b := t.child.Next()
+	return string(a), b
 }
 
-// ---------- KeyValUint64 ----------
-
-// Add this to any struct to make it binary searchable.
-type KeyValUint64 struct {
- key []sortIntUint64.KeyVal
- cursor int
+func (t *KeyString) Keys() []string {
+	keys := t.child.Keys()
+	newkeys := make([]string, len(keys))
+	for i, v := range keys {
+		newkeys[i] = string(v)
+	}
+	return newkeys
 }
 
-func (t *KeyValUint64) Len() int {
-	return len(t.key)
+func (t *KeyString) Write(w custom.Interface) {
+	t.child.Write(w)
 }
 
-// Find returns the index based on the key.
-func (t *KeyValUint64) Find(thekey uint64) (int, bool) {
-	var min, at int
-	var current uint64
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return t.key[at].K, true // found
-			}
-		}
-	}
-	return 0, false // doesn't exist
+func (t *KeyString) Read(r *custom.Reader) {
+	t.child.Read(r)
 }
 
-// Modifies the value of the key by running it through the provided function
-func (t *KeyValUint64) Update(thekey uint64, fn func(int) int) bool {
-	var min, at int
-	var current uint64
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				t.key[at].K = fn(t.key[at].K)
-				return true // found
-			}
-		}
-	}
-	return false // doesn't exist
-}
+// ---------- KeyValString ----------
 
-// Modifies all values by running each through the provided function
-func (t *KeyValUint64) UpdateAll(fn func(int) int) {
-	tmp := t.key
-	l := len(tmp)
-	for i:=0; i<l; i++ {
-		tmp[i].K = fn(tmp[i].K)
-	}
+// Add this to any struct to make it binary searchable.
+type KeyValString struct {
+ child KeyValBytes
 }
 
-// Add is equivalent to Find and then AddAt
-func (t *KeyValUint64) Add(thekey uint64, theval int) bool {
-	var min, at int
-	var current uint64
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				if t.key[at].K != theval {
-					t.key[at].K = theval
-				}
-				return true // found
-			}
-		}
-	}
-	cur := t.key
-	lc := len(cur)
-	if lc == cap(cur) {
-		tmp := make([]sortIntUint64.KeyVal, lc + 1, (lc * 2) + 1)
-		copy(tmp, cur[0:min])
-		copy(tmp[min+1:], cur[min:])
-		cur = tmp
-	} else {
-		cur = cur[0:lc+1]
-		copy(cur[min+1:], cur[min:])
-	}
-	cur[min] = sortIntUint64.KeyVal{theval, thekey}
-	t.key = cur
-	return false
+// Find returns the value based on the key.
+func (t *KeyValString) Find(thekey string) (int, bool) {
+	return findKeyValBytes(&t.child, thekey)
 }
 
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *KeyValUint64) AddUnsorted(thekey uint64, theval int) {
-	t.key = append(t.key, sortIntUint64.KeyVal{theval, thekey})
-	return
+func (t *KeyValString) Update(thekey string, fn func(int) int) bool {
+	return updateKeyValBytes(&t.child, thekey, fn)
 }
 
-// Build sorts the keys and values.
-func (t *KeyValUint64) Build() {
-	sortIntUint64.Asc(t.key)
+func (t *KeyValString) UpdateAll(fn func(int) int) {
+	t.child.UpdateAll(fn)
 }
 
-func (t *KeyValUint64) Optimize() {
-	temp := make([]sortIntUint64.KeyVal, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
+// Add adds this key and value to the index in the correct position, or replaces the value if the key already exists.
+func (t *KeyValString) Add(thekey string, theval int) bool {
+	return addKeyValBytes(&t.child, thekey, theval)
 }
 
-func (t *KeyValUint64) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
+// AddUnsorted adds this key to the end of the index for later building with Build.
+func (t *KeyValString) AddUnsorted(thekey string, theval int) error {
+	return addUnsortedKeyValBytes(&t.child, thekey, theval)
 }
 
-func (t *KeyValUint64) Next() (uint64, int, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v.V, v.K, true
-	}
-	return v.V, v.K, false
+func (t *KeyValString) Build() {
+	t.child.Build()
 }
 
-func (t *KeyValUint64) Keys() []uint64 {
-	keys := make([]uint64, len(t.key))
-	for i, v := range t.key {
-		keys[i] = v.V
-	}
-	return keys
+func (t *KeyValString) Optimize() {
+	t.child.Optimize()
 }
 
-// ---------- CounterUint64 ----------
+func (t *KeyValString) Len() int {
+	return t.child.Len()
+}
 
-// Add this to any struct to make it binary searchable.
-type CounterUint64 struct {
- key []sortIntUint64.KeyVal
- cursor int
+func (t *KeyValString) GreatestVal() int {
+	return t.child.GreatestVal()
 }
 
-func NewCounterUint64(ar []sortIntUint64.KeyVal) *CounterUint64 {
-	return &CounterUint64{key: ar[0:0]}
+func (t *KeyValString) Reset() bool {
+	return t.child.Reset()
 }
 
-func (t *CounterUint64) RawKey() []sortIntUint64.KeyVal {
-	return t.key
+func (t *KeyValString) Next() (string, int, bool) {
+	a, b, c := t.child.Next()
+	return string(a), b, c
 }
 
-func (t *CounterUint64) KeyUint64() *KeyUint64 {
-	obj := new(KeyUint64)
-	key := make([]uint64, len(t.key))
-	for i, v := range t.key {
-		key[i] = v.V
+func (t *KeyValString) Keys() []string {
+	keys := t.child.Keys()
+	newkeys := make([]string, len(keys))
+	for i, v := range keys {
+		newkeys[i] = string(v)
 	}
-	obj.key = key
-	return obj
+	return newkeys
 }
 
-func (t *CounterUint64) KeyValUint64() *KeyValUint64 {
-	obj := new(KeyValUint64)
-	key := make([]sortIntUint64.KeyVal, len(t.key))
-	copy(key, t.key)
-	obj.key = key
-	return obj
+func (t *KeyValString) Write(w custom.Interface) {
+	t.child.Write(w)
 }
 
-func (t *CounterUint64) Len() int {
-	return len(t.key)
+func (t *KeyValString) Read(r *custom.Reader) {
+	t.child.Read(r)
 }
 
-// Find returns the index based on the key.
-func (t *CounterUint64) Find(thekey uint64) (int, bool) {
-	var min, at int
-	var current uint64
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return t.key[at].K, true // found
-			}
-		}
-	}
-	return 0, false // doesn't exist
-}
+// ---------- CounterString ----------
 
-// Modifies the value of the key by running it through the provided function.
-func (t *CounterUint64) Update(thekey uint64, fn func(int) int) bool {
-	var min, at int
-	var current uint64
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				t.key[at].K = fn(t.key[at].K)
-				return true // found
-			}
-		}
-	}
-	return false // doesn't exist
+// Add this to any struct to make it binary searchable.
+type CounterString struct {
+ child CounterBytes
 }
 
-// Modifies all values by running each through the provided function.
-func (t *CounterUint64) UpdateAll(fn func(int) int) {
-	tmp := t.key
-	l := len(tmp)
-	for i:=0; i<l; i++ {
-		tmp[i].K = fn(tmp[i].K)
-	}
+// Find returns the frequency based on the key.
+func (t *CounterString) Find(thekey string) (int, bool) {
+	return findCounterBytes(&t.child, thekey)
 }
 
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *CounterUint64) Add(thekey uint64, theval int) {
-	t.key = append(t.key, sortIntUint64.KeyVal{theval, thekey})
+func (t *CounterString) Update(thekey string, fn func(int) int) bool {
+	return updateCounterBytes(&t.child, thekey, fn)
 }
 
-// Build sorts the keys and values.
-func (t *CounterUint64) Build() {
-	var temp = t.key
-	if len(temp) == 0 {
-		return
-	}
-	sortIntUint64.Asc(temp)
-	this := t.key[0].V
-	n := t.key[0].K
-	var on int
-	for _, k := range t.key[1:] {
-		if k.V == this {
-			n += k.K
-		} else {
-			temp[on] = sortIntUint64.KeyVal{n, this}
-			on++
-			this = k.V
-			n = k.K
-		}
-	}
-	temp[on] = sortIntUint64.KeyVal{n, this}
-	t.key = temp[0:on+1]
+func (t *CounterString) UpdateAll(fn func(int) int) {
+	t.child.UpdateAll(fn)
 }
 
-func (t *CounterUint64) Optimize() {
-	temp := make([]sortIntUint64.KeyVal, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
+// Add adds this key to the end of the index for later building with Build.
+func (t *CounterString) Add(thekey string, theval int) error {
+	return addCounterBytes(&t.child, thekey, theval)
 }
 
-func (t *CounterUint64) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
+func (t *CounterString) Build() {
+	t.child.Build()
 }
 
-func (t *CounterUint64) Next() (uint64, int, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v.V, v.K, true
-	}
-	return v.V, v.K, false
+func (t *CounterString) Optimize() {
+	t.child.Optimize()
 }
 
-func (t *CounterUint64) Keys() []uint64 {
-	keys := make([]uint64, len(t.key))
-	for i, v := range t.key {
-		keys[i] = v.V
-	}
-	return keys
+func (t *CounterString) Len() int {
+	return t.child.Len()
 }
 
-// ------------- export ---------------
+func (t *CounterString) Reset() bool {
+	return t.child.Reset()
+}
 
-func (t *KeyUint64) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint64Variable(v)
-	}
+func (t *CounterString) Next() (string, int, bool) {
+	a, b, c := t.child.Next()
+	return string(a), b, c
 }
 
-func (t *KeyUint64) Read(r *custom.Reader) {
-	l := int(r.ReadUint64Variable())
-	tmp := make([]uint64, l)
-	for i:=0; i<l; i++ {
-		tmp[i] = r.ReadUint64Variable()
+func (t *CounterString) Keys() []string {
+	keys := t.child.Keys()
+	newkeys := make([]string, len(keys))
+	for i, v := range keys {
+		newkeys[i] = string(v)
 	}
-	t.key = tmp
+	return newkeys
 }
 
-func (t *KeyValUint64) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v.K))
-		w.WriteUint64Variable(v.V)
-	}
+func (t *CounterString) Write(w custom.Interface) {
+	t.child.Write(w)
 }
 
-func (t *KeyValUint64) Read(r *custom.Reader) {
-	var k int
-	var v uint64
-	l := int(r.ReadUint64Variable())
-	tmp := make([]sortIntUint64.KeyVal, l)
-	for i:=0; i<l; i++ {
-		k = int(r.ReadUint64Variable())
-		v = r.ReadUint64Variable()
-		tmp[i] = sortIntUint64.KeyVal{k, v}
-	}
-	t.key = tmp
+func (t *CounterString) Read(r *custom.Reader) {
+	t.child.Read(r)
 }
 
-func (t *CounterUint64) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v.K))
-		w.WriteUint64Variable(v.V)
-	}
-}
-
-func (t *CounterUint64) Read(r *custom.Reader) {
-	var k int
-	var v uint64
-	l := int(r.ReadUint64Variable())
-	tmp := make([]sortIntUint64.KeyVal, l)
-	for i:=0; i<l; i++ {
-		k = int(r.ReadUint64Variable())
-		v = r.ReadUint64Variable()
-		tmp[i] = sortIntUint64.KeyVal{k, v}
-	}
-	t.key = tmp
-}
-
-// ====================== uint32 ======================
-// ---------- KeyUint32 ----------
-
-// Add this to any struct to make it binary searchable.
-type KeyUint32 struct {
- key []uint32
- cursor int
-}
-
-func (t *KeyUint32) Len() int {
-	return len(t.key)
-}
-
-func NewKeyUint32(i int) *KeyUint32 {
-	return &KeyUint32{key:make([]uint32, 0, i), cursor:0}
-}
-
-// Find returns the index based on the key.
-func (t *KeyUint32) Find(thekey uint32) (int, bool) {
-	var min, at int
-	var current uint32
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at]; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return at, true // found
-			}
-		}
-	}
-	return min, false // doesn't exist
-}
-
-// Add is equivalent to Find and then AddAt
-func (t *KeyUint32) Add(thekey uint32) (int, bool) {
-	i, ok := t.Find(thekey)
-	if !ok {
-		t.AddAt(thekey, i)
-	}
-	return i, ok
-}
-
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *KeyUint32) AddUnsorted(thekey uint32) {
-	t.key = append(t.key, thekey)
-	return
-}
-
-// AddAt adds this key to the index in this exact position, so it does not require later rebuilding.
-func (t *KeyUint32) AddAt(thekey uint32, i int) {
-	cur := t.key
-	lc := len(cur)
-	if lc == cap(cur) {
-		tmp := make([]uint32, lc + 1, (lc * 2) + 1)
-		copy(tmp, cur[0:i])
-		copy(tmp[i+1:], cur[i:])
-		cur = tmp
-	} else {
-		cur = cur[0:lc+1]
-		copy(cur[i+1:], cur[i:])
-	}
-	cur[i] = thekey
-	t.key = cur
-}
-
-// Build sorts the keys and returns an array telling you how to sort the values, you must do this yourself.
-func (t *KeyUint32) Build() []int {
-	l := len(t.key)
-	temp := make([]sortIntUint32.KeyVal, l)
-	var i int
-	var k uint32
-	for i, k = range t.key {
-		temp[i] = sortIntUint32.KeyVal{i, k}
-	}
-	sortIntUint32.Asc(temp)
-	imap := make([]int, l)
-	newkey := t.key
-	for i, obj := range temp {
-		imap[i] = obj.K
-		newkey[i] = obj.V
-	}
-	return imap
-}
-
-func (t *KeyUint32) Optimize() {
-	temp := make([]uint32, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
-}
-
-func (t *KeyUint32) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
-}
-
-func (t *KeyUint32) Next() (uint32, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v, true
-	}
-	return v, false
-}
-
-func (t *KeyUint32) Keys() []uint32 {
-	return t.key
-}
-
-// ---------- KeyValUint32 ----------
-
-// Add this to any struct to make it binary searchable.
-type KeyValUint32 struct {
- key []sortIntUint32.KeyVal
- cursor int
-}
-
-func (t *KeyValUint32) Len() int {
-	return len(t.key)
-}
-
-// Find returns the index based on the key.
-func (t *KeyValUint32) Find(thekey uint32) (int, bool) {
-	var min, at int
-	var current uint32
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return t.key[at].K, true // found
-			}
-		}
-	}
-	return 0, false // doesn't exist
-}
-
-// Modifies the value of the key by running it through the provided function
-func (t *KeyValUint32) Update(thekey uint32, fn func(int) int) bool {
-	var min, at int
-	var current uint32
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				t.key[at].K = fn(t.key[at].K)
-				return true // found
-			}
-		}
-	}
-	return false // doesn't exist
-}
-
-// Modifies all values by running each through the provided function
-func (t *KeyValUint32) UpdateAll(fn func(int) int) {
-	tmp := t.key
-	l := len(tmp)
-	for i:=0; i<l; i++ {
-		tmp[i].K = fn(tmp[i].K)
-	}
-}
-
-// Add is equivalent to Find and then AddAt
-func (t *KeyValUint32) Add(thekey uint32, theval int) bool {
-	var min, at int
-	var current uint32
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				if t.key[at].K != theval {
-					t.key[at].K = theval
-				}
-				return true // found
-			}
-		}
-	}
-	cur := t.key
-	lc := len(cur)
-	if lc == cap(cur) {
-		tmp := make([]sortIntUint32.KeyVal, lc + 1, (lc * 2) + 1)
-		copy(tmp, cur[0:min])
-		copy(tmp[min+1:], cur[min:])
-		cur = tmp
-	} else {
-		cur = cur[0:lc+1]
-		copy(cur[min+1:], cur[min:])
-	}
-	cur[min] = sortIntUint32.KeyVal{theval, thekey}
-	t.key = cur
-	return false
-}
-
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *KeyValUint32) AddUnsorted(thekey uint32, theval int) {
-	t.key = append(t.key, sortIntUint32.KeyVal{theval, thekey})
-	return
-}
-
-// Build sorts the keys and values.
-func (t *KeyValUint32) Build() {
-	sortIntUint32.Asc(t.key)
-}
-
-func (t *KeyValUint32) Optimize() {
-	temp := make([]sortIntUint32.KeyVal, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
-}
-
-func (t *KeyValUint32) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
-}
-
-func (t *KeyValUint32) Next() (uint32, int, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v.V, v.K, true
-	}
-	return v.V, v.K, false
-}
-
-func (t *KeyValUint32) Keys() []uint32 {
-	keys := make([]uint32, len(t.key))
-	for i, v := range t.key {
-		keys[i] = v.V
-	}
-	return keys
-}
-
-// ---------- CounterUint32 ----------
-
-// Add this to any struct to make it binary searchable.
-type CounterUint32 struct {
- key []sortIntUint32.KeyVal
- cursor int
-}
-
-func NewCounterUint32(ar []sortIntUint32.KeyVal) *CounterUint32 {
-	return &CounterUint32{key: ar[0:0]}
-}
-
-func (t *CounterUint32) RawKey() []sortIntUint32.KeyVal {
-	return t.key
-}
-
-func (t *CounterUint32) KeyUint32() *KeyUint32 {
-	obj := new(KeyUint32)
-	key := make([]uint32, len(t.key))
-	for i, v := range t.key {
-		key[i] = v.V
-	}
-	obj.key = key
-	return obj
-}
-
-func (t *CounterUint32) KeyValUint32() *KeyValUint32 {
-	obj := new(KeyValUint32)
-	key := make([]sortIntUint32.KeyVal, len(t.key))
-	copy(key, t.key)
-	obj.key = key
-	return obj
-}
-
-func (t *CounterUint32) Len() int {
-	return len(t.key)
-}
-
-// Find returns the index based on the key.
-func (t *CounterUint32) Find(thekey uint32) (int, bool) {
-	var min, at int
-	var current uint32
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return t.key[at].K, true // found
-			}
-		}
-	}
-	return 0, false // doesn't exist
-}
-
-// Modifies the value of the key by running it through the provided function.
-func (t *CounterUint32) Update(thekey uint32, fn func(int) int) bool {
-	var min, at int
-	var current uint32
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				t.key[at].K = fn(t.key[at].K)
-				return true // found
-			}
-		}
-	}
-	return false // doesn't exist
-}
-
-// Modifies all values by running each through the provided function.
-func (t *CounterUint32) UpdateAll(fn func(int) int) {
-	tmp := t.key
-	l := len(tmp)
-	for i:=0; i<l; i++ {
-		tmp[i].K = fn(tmp[i].K)
-	}
-}
-
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *CounterUint32) Add(thekey uint32, theval int) {
-	t.key = append(t.key, sortIntUint32.KeyVal{theval, thekey})
-}
-
-// Build sorts the keys and values.
-func (t *CounterUint32) Build() {
-	var temp = t.key
-	if len(temp) == 0 {
-		return
-	}
-	sortIntUint32.Asc(temp)
-	this := t.key[0].V
-	n := t.key[0].K
-	var on int
-	for _, k := range t.key[1:] {
-		if k.V == this {
-			n += k.K
-		} else {
-			temp[on] = sortIntUint32.KeyVal{n, this}
-			on++
-			this = k.V
-			n = k.K
-		}
-	}
-	temp[on] = sortIntUint32.KeyVal{n, this}
-	t.key = temp[0:on+1]
-}
-
-func (t *CounterUint32) Optimize() {
-	temp := make([]sortIntUint32.KeyVal, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
-}
-
-func (t *CounterUint32) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
-}
-
-func (t *CounterUint32) Next() (uint32, int, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v.V, v.K, true
-	}
-	return v.V, v.K, false
-}
-
-func (t *CounterUint32) Keys() []uint32 {
-	keys := make([]uint32, len(t.key))
-	for i, v := range t.key {
-		keys[i] = v.V
-	}
-	return keys
-}
-
-// ------------- export ---------------
-
-func (t *KeyUint32) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v))
-	}
-}
-
-func (t *KeyUint32) Read(r *custom.Reader) {
-	l := int(r.ReadUint64Variable())
-	tmp := make([]uint32, l)
-	for i:=0; i<l; i++ {
-		tmp[i] = uint32(r.ReadUint64Variable())
-	}
-	t.key = tmp
-}
-
-func (t *KeyValUint32) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v.K))
-		w.WriteUint64Variable(uint64(v.V))
-	}
-}
-
-func (t *KeyValUint32) Read(r *custom.Reader) {
-	var k int
-	var v uint32
-	l := int(r.ReadUint64Variable())
-	tmp := make([]sortIntUint32.KeyVal, l)
-	for i:=0; i<l; i++ {
-		k = int(r.ReadUint64Variable())
-		v = uint32(r.ReadUint64Variable())
-		tmp[i] = sortIntUint32.KeyVal{k, v}
-	}
-	t.key = tmp
-}
-
-func (t *CounterUint32) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v.K))
-		w.WriteUint64Variable(uint64(v.V))
-	}
-}
-
-func (t *CounterUint32) Read(r *custom.Reader) {
-	var k int
-	var v uint32
-	l := int(r.ReadUint64Variable())
-	tmp := make([]sortIntUint32.KeyVal, l)
-	for i:=0; i<l; i++ {
-		k = int(r.ReadUint64Variable())
-		v = uint32(r.ReadUint64Variable())
-		tmp[i] = sortIntUint32.KeyVal{k, v}
-	}
-	t.key = tmp
-}
-
-// ====================== uint16 ======================
-// ---------- KeyUint16 ----------
-
-// Add this to any struct to make it binary searchable.
-type KeyUint16 struct {
- key []uint16
- cursor int
-}
-
-func (t *KeyUint16) Len() int {
-	return len(t.key)
-}
-
-// Find returns the index based on the key.
-func (t *KeyUint16) Find(thekey uint16) (int, bool) {
-	var min, at int
-	var current uint16
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at]; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return at, true // found
-			}
-		}
-	}
-	return min, false // doesn't exist
-}
-
-// Add is equivalent to Find and then AddAt
-func (t *KeyUint16) Add(thekey uint16) (int, bool) {
-	i, ok := t.Find(thekey)
-	if !ok {
-		t.AddAt(thekey, i)
-	}
-	return i, ok
-}
-
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *KeyUint16) AddUnsorted(thekey uint16) {
-	t.key = append(t.key, thekey)
-	return
-}
-
-// AddAt adds this key to the index in this exact position, so it does not require later rebuilding.
-func (t *KeyUint16) AddAt(thekey uint16, i int) {
-	cur := t.key
-	lc := len(cur)
-	if lc == cap(cur) {
-		tmp := make([]uint16, lc + 1, (lc * 2) + 1)
-		copy(tmp, cur[0:i])
-		copy(tmp[i+1:], cur[i:])
-		cur = tmp
-	} else {
-		cur = cur[0:lc+1]
-		copy(cur[i+1:], cur[i:])
-	}
-	cur[i] = thekey
-	t.key = cur
-}
-
-// Build sorts the keys and returns an array telling you how to sort the values, you must do this yourself.
-func (t *KeyUint16) Build() []int {
-	l := len(t.key)
-	temp := make([]sortIntUint16.KeyVal, l)
-	var i int
-	var k uint16
-	for i, k = range t.key {
-		temp[i] = sortIntUint16.KeyVal{i, k}
-	}
-	sortIntUint16.Asc(temp)
-	imap := make([]int, l)
-	newkey := t.key
-	for i, obj := range temp {
-		imap[i] = obj.K
-		newkey[i] = obj.V
-	}
-	return imap
-}
-
-func (t *KeyUint16) Optimize() {
-	temp := make([]uint16, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
-}
-
-func (t *KeyUint16) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
-}
-
-func (t *KeyUint16) Next() (uint16, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v, true
-	}
-	return v, false
-}
-
-func (t *KeyUint16) Keys() []uint16 {
-	return t.key
-}
-
-// ---------- KeyValUint16 ----------
-
-// Add this to any struct to make it binary searchable.
-type KeyValUint16 struct {
- key []sortIntUint16.KeyVal
- cursor int
-}
-
-func (t *KeyValUint16) Len() int {
-	return len(t.key)
-}
-
-// Find returns the index based on the key.
-func (t *KeyValUint16) Find(thekey uint16) (int, bool) {
-	var min, at int
-	var current uint16
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return t.key[at].K, true // found
-			}
-		}
-	}
-	return 0, false // doesn't exist
-}
-
-// Modifies the value of the key by running it through the provided function
-func (t *KeyValUint16) Update(thekey uint16, fn func(int) int) bool {
-	var min, at int
-	var current uint16
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				t.key[at].K = fn(t.key[at].K)
-				return true // found
-			}
-		}
-	}
-	return false // doesn't exist
-}
-
-// Modifies all values by running each through the provided function
-func (t *KeyValUint16) UpdateAll(fn func(int) int) {
-	tmp := t.key
-	l := len(tmp)
-	for i:=0; i<l; i++ {
-		tmp[i].K = fn(tmp[i].K)
-	}
-}
-
-// Add is equivalent to Find and then AddAt
-func (t *KeyValUint16) Add(thekey uint16, theval int) bool {
-	var min, at int
-	var current uint16
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				if t.key[at].K != theval {
-					t.key[at].K = theval
-				}
-				return true // found
-			}
-		}
-	}
-	cur := t.key
-	lc := len(cur)
-	if lc == cap(cur) {
-		tmp := make([]sortIntUint16.KeyVal, lc + 1, (lc * 2) + 1)
-		copy(tmp, cur[0:min])
-		copy(tmp[min+1:], cur[min:])
-		cur = tmp
-	} else {
-		cur = cur[0:lc+1]
-		copy(cur[min+1:], cur[min:])
-	}
-	cur[min] = sortIntUint16.KeyVal{theval, thekey}
-	t.key = cur
-	return false
-}
-
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *KeyValUint16) AddUnsorted(thekey uint16, theval int) {
-	t.key = append(t.key, sortIntUint16.KeyVal{theval, thekey})
-	return
-}
-
-// Build sorts the keys and values.
-func (t *KeyValUint16) Build() {
-	sortIntUint16.Asc(t.key)
-}
-
-func (t *KeyValUint16) Optimize() {
-	temp := make([]sortIntUint16.KeyVal, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
-}
-
-func (t *KeyValUint16) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
-}
-
-func (t *KeyValUint16) Next() (uint16, int, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v.V, v.K, true
-	}
-	return v.V, v.K, false
-}
-
-func (t *KeyValUint16) Keys() []uint16 {
-	keys := make([]uint16, len(t.key))
-	for i, v := range t.key {
-		keys[i] = v.V
-	}
-	return keys
-}
-
-// ---------- CounterUint16 ----------
-
-// Add this to any struct to make it binary searchable.
-type CounterUint16 struct {
- key []sortIntUint16.KeyVal
- cursor int
-}
-
-func NewCounterUint16(ar []sortIntUint16.KeyVal) *CounterUint16 {
-	return &CounterUint16{key: ar[0:0]}
-}
-
-func (t *CounterUint16) RawKey() []sortIntUint16.KeyVal {
-	return t.key
-}
-
-func (t *CounterUint16) KeyUint16() *KeyUint16 {
-	obj := new(KeyUint16)
-	key := make([]uint16, len(t.key))
-	for i, v := range t.key {
-		key[i] = v.V
-	}
-	obj.key = key
-	return obj
-}
-
-func (t *CounterUint16) KeyValUint16() *KeyValUint16 {
-	obj := new(KeyValUint16)
-	key := make([]sortIntUint16.KeyVal, len(t.key))
-	copy(key, t.key)
-	obj.key = key
-	return obj
-}
-
-func (t *CounterUint16) Len() int {
-	return len(t.key)
-}
-
-// Find returns the index based on the key.
-func (t *CounterUint16) Find(thekey uint16) (int, bool) {
-	var min, at int
-	var current uint16
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return t.key[at].K, true // found
-			}
-		}
-	}
-	return 0, false // doesn't exist
-}
-
-// Modifies the value of the key by running it through the provided function.
-func (t *CounterUint16) Update(thekey uint16, fn func(int) int) bool {
-	var min, at int
-	var current uint16
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				t.key[at].K = fn(t.key[at].K)
-				return true // found
-			}
-		}
-	}
-	return false // doesn't exist
-}
-
-// Modifies all values by running each through the provided function.
-func (t *CounterUint16) UpdateAll(fn func(int) int) {
-	tmp := t.key
-	l := len(tmp)
-	for i:=0; i<l; i++ {
-		tmp[i].K = fn(tmp[i].K)
-	}
-}
-
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *CounterUint16) Add(thekey uint16, theval int) {
-	t.key = append(t.key, sortIntUint16.KeyVal{theval, thekey})
-}
-
-// Build sorts the keys and values.
-func (t *CounterUint16) Build() {
-	var temp = t.key
-	if len(temp) == 0 {
-		return
-	}
-	sortIntUint16.Asc(temp)
-	this := t.key[0].V
-	n := t.key[0].K
-	var on int
-	for _, k := range t.key[1:] {
-		if k.V == this {
-			n += k.K
-		} else {
-			temp[on] = sortIntUint16.KeyVal{n, this}
-			on++
-			this = k.V
-			n = k.K
-		}
-	}
-	temp[on] = sortIntUint16.KeyVal{n, this}
-	t.key = temp[0:on+1]
-}
-
-func (t *CounterUint16) Optimize() {
-	temp := make([]sortIntUint16.KeyVal, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
-}
-
-func (t *CounterUint16) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
-}
-
-func (t *CounterUint16) Next() (uint16, int, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v.V, v.K, true
-	}
-	return v.V, v.K, false
-}
-
-func (t *CounterUint16) Keys() []uint16 {
-	keys := make([]uint16, len(t.key))
-	for i, v := range t.key {
-		keys[i] = v.V
-	}
-	return keys
-}
-
-// ------------- export ---------------
-
-func (t *KeyUint16) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint16(v)
-	}
-}
-
-func (t *KeyUint16) Read(r *custom.Reader) {
-	l := int(r.ReadUint64Variable())
-	tmp := make([]uint16, l)
-	for i:=0; i<l; i++ {
-		tmp[i] = r.ReadUint16()
-	}
-	t.key = tmp
-}
-
-func (t *KeyValUint16) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v.K))
-		w.WriteUint16(v.V)
-	}
-}
-
-func (t *KeyValUint16) Read(r *custom.Reader) {
-	var k int
-	var v uint16
-	l := int(r.ReadUint64Variable())
-	tmp := make([]sortIntUint16.KeyVal, l)
-	for i:=0; i<l; i++ {
-		k = int(r.ReadUint64Variable())
-		v = r.ReadUint16()
-		tmp[i] = sortIntUint16.KeyVal{k, v}
-	}
-	t.key = tmp
-}
-
-func (t *CounterUint16) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v.K))
-		w.WriteUint16(v.V)
-	}
+func (t *CounterString) KeyString() *KeyString {
+	obj := new(KeyString)
+	child := t.child.KeyBytes()
+	obj.child = *child
+	return obj
 }
 
-func (t *CounterUint16) Read(r *custom.Reader) {
-	var k int
-	var v uint16
-	l := int(r.ReadUint64Variable())
-	tmp := make([]sortIntUint16.KeyVal, l)
-	for i:=0; i<l; i++ {
-		k = int(r.ReadUint64Variable())
-		v = r.ReadUint16()
-		tmp[i] = sortIntUint16.KeyVal{k, v}
-	}
-	t.key = tmp
+func (t *CounterString) KeyValString() *KeyValString {
+	obj := new(KeyValString)
+	child := t.child.KeyValBytes()
+	obj.child = *child
+	return obj
 }
 
-// ====================== uint8 ======================
-// ---------- KeyUint8 ----------
+// ====================== generic ======================
 
-// Add this to any struct to make it binary searchable.
-type KeyUint8 struct {
- key []uint8
- cursor int
-}
+/*
+	Key, KeyVal and Counter are generic over any ordered key type (see cmp.Ordered).
+	KeyUint64, KeyValUint32, CounterInt, etc. are aliases of these and work exactly as they always did.
+	Note that floats are ordered with <, so NaN keys cannot be found. KeyFloat64, etc. (float.go) have a total order.
+*/
 
-func (t *KeyUint8) Len() int {
-	return len(t.key)
+// Integer is the set of integer types.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
 }
 
-// Find returns the index based on the key.
-func (t *KeyUint8) Find(thekey uint8) (int, bool) {
-	var min, at int
-	var current uint8
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at]; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return at, true // found
-			}
-		}
-	}
-	return min, false // doesn't exist
+// Number is the set of value types that can be added up by a Counter structure.
+type Number interface {
+	Integer | ~float32 | ~float64
 }
 
-// Add is equivalent to Find and then AddAt
-func (t *KeyUint8) Add(thekey uint8) (int, bool) {
-	i, ok := t.Find(thekey)
-	if !ok {
-		t.AddAt(thekey, i)
-	}
-	return i, ok
-}
+// ErrOverflow is returned by the Build functions that check for overflow when the values of a key don't fit in the value type.
+var ErrOverflow = errors.New(`Counter value overflowed during Build`)
 
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *KeyUint8) AddUnsorted(thekey uint8) {
-	t.key = append(t.key, thekey)
-	return
-}
+/*
+	KeyUint64, KeyValUint64, etc. are aliases of the generic types. The Counter types embed Counter[K, int] instead,
+	so that they keep the methods they had before the generic types: KeyUint64() and KeyValUint64() as well as Key() and KeyVal(),
+	and the RawKey and constructors that use the KeyVal of github.com/AlasdairF/Sort, which shares its memory with sortOrdered.KeyVal.
+*/
 
-// AddAt adds this key to the index in this exact position, so it does not require later rebuilding.
-func (t *KeyUint8) AddAt(thekey uint8, i int) {
-	cur := t.key
-	lc := len(cur)
-	if lc == cap(cur) {
-		tmp := make([]uint8, lc + 1, (lc * 2) + 1)
-		copy(tmp, cur[0:i])
-		copy(tmp[i+1:], cur[i:])
-		cur = tmp
-	} else {
-		cur = cur[0:lc+1]
-		copy(cur[i+1:], cur[i:])
-	}
-	cur[i] = thekey
-	t.key = cur
-}
+type KeyUint64 = Key[uint64]
+type KeyValUint64 = KeyVal[uint64, int]
 
-// Build sorts the keys and returns an array telling you how to sort the values, you must do this yourself.
-func (t *KeyUint8) Build() []int {
-	l := len(t.key)
-	temp := make([]sortIntUint8.KeyVal, l)
-	var i int
-	var k uint8
-	for i, k = range t.key {
-		temp[i] = sortIntUint8.KeyVal{i, k}
-	}
-	sortIntUint8.Asc(temp)
-	imap := make([]int, l)
-	newkey := t.key
-	for i, obj := range temp {
-		imap[i] = obj.K
-		newkey[i] = obj.V
-	}
-	return imap
+// Add this to any struct to make it binary searchable.
+type CounterUint64 struct {
+ Counter[uint64, int]
 }
 
-func (t *KeyUint8) Optimize() {
-	temp := make([]uint8, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
+// KeyUint64 copies the keys to a KeyUint64 structure.
+func (t *CounterUint64) KeyUint64() *KeyUint64 {
+	return t.Key()
 }
 
-func (t *KeyUint8) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
+// KeyValUint64 copies the keys and values to a KeyValUint64 structure.
+func (t *CounterUint64) KeyValUint64() *KeyValUint64 {
+	return t.KeyVal()
 }
 
-func (t *KeyUint8) Next() (uint8, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v, true
-	}
-	return v, false
+// NewCounterUint64 reuses the memory of ar for the counter.
+func NewCounterUint64(ar []sortIntUint64.KeyVal) *CounterUint64 {
+	key := unsafe.Slice((*sortOrdered.KeyVal[uint64, int])(unsafe.SliceData(ar)), cap(ar))
+	return &CounterUint64{Counter[uint64, int]{key: key[0:0]}}
 }
 
-func (t *KeyUint8) Keys() []uint8 {
-	return t.key
+// RawKey returns the keys and frequencies, this is not a copy.
+func (t *CounterUint64) RawKey() []sortIntUint64.KeyVal {
+	return unsafe.Slice((*sortIntUint64.KeyVal)(unsafe.SliceData(t.key)), cap(t.key))[0:len(t.key)]
 }
 
-// ---------- KeyValUint8 ----------
+type KeyUint32 = Key[uint32]
+type KeyValUint32 = KeyVal[uint32, int]
 
 // Add this to any struct to make it binary searchable.
-type KeyValUint8 struct {
- key []sortIntUint8.KeyVal
- cursor int
+type CounterUint32 struct {
+ Counter[uint32, int]
 }
 
-func (t *KeyValUint8) Len() int {
-	return len(t.key)
+// KeyUint32 copies the keys to a KeyUint32 structure.
+func (t *CounterUint32) KeyUint32() *KeyUint32 {
+	return t.Key()
 }
 
-// Find returns the index based on the key.
-func (t *KeyValUint8) Find(thekey uint8) (int, bool) {
-	var min, at int
-	var current uint8
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return t.key[at].K, true // found
-			}
-		}
-	}
-	return 0, false // doesn't exist
+// KeyValUint32 copies the keys and values to a KeyValUint32 structure.
+func (t *CounterUint32) KeyValUint32() *KeyValUint32 {
+	return t.KeyVal()
 }
 
-// Modifies the value of the key by running it through the provided function
-func (t *KeyValUint8) Update(thekey uint8, fn func(int) int) bool {
-	var min, at int
-	var current uint8
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				t.key[at].K = fn(t.key[at].K)
-				return true // found
-			}
-		}
-	}
-	return false // doesn't exist
+// NewCounterUint32 reuses the memory of ar for the counter.
+func NewCounterUint32(ar []sortIntUint32.KeyVal) *CounterUint32 {
+	key := unsafe.Slice((*sortOrdered.KeyVal[uint32, int])(unsafe.SliceData(ar)), cap(ar))
+	return &CounterUint32{Counter[uint32, int]{key: key[0:0]}}
 }
 
-// Modifies all values by running each through the provided function
-func (t *KeyValUint8) UpdateAll(fn func(int) int) {
-	tmp := t.key
-	l := len(tmp)
-	for i:=0; i<l; i++ {
-		tmp[i].K = fn(tmp[i].K)
-	}
+// RawKey returns the keys and frequencies, this is not a copy.
+func (t *CounterUint32) RawKey() []sortIntUint32.KeyVal {
+	return unsafe.Slice((*sortIntUint32.KeyVal)(unsafe.SliceData(t.key)), cap(t.key))[0:len(t.key)]
 }
 
-// Add is equivalent to Find and then AddAt
-func (t *KeyValUint8) Add(thekey uint8, theval int) bool {
-	var min, at int
-	var current uint8
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				if t.key[at].K != theval {
-					t.key[at].K = theval
-				}
-				return true // found
-			}
-		}
-	}
-	cur := t.key
-	lc := len(cur)
-	if lc == cap(cur) {
-		tmp := make([]sortIntUint8.KeyVal, lc + 1, (lc * 2) + 1)
-		copy(tmp, cur[0:min])
-		copy(tmp[min+1:], cur[min:])
-		cur = tmp
-	} else {
-		cur = cur[0:lc+1]
-		copy(cur[min+1:], cur[min:])
-	}
-	cur[min] = sortIntUint8.KeyVal{theval, thekey}
-	t.key = cur
-	return false
+func NewKeyUint32(i int) *KeyUint32 {
+	return NewKey[uint32](i)
 }
 
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *KeyValUint8) AddUnsorted(thekey uint8, theval int) {
-	t.key = append(t.key, sortIntUint8.KeyVal{theval, thekey})
-	return
-}
+type KeyUint16 = Key[uint16]
+type KeyValUint16 = KeyVal[uint16, int]
 
-// Build sorts the keys and values.
-func (t *KeyValUint8) Build() {
-	sortIntUint8.Asc(t.key)
+// Add this to any struct to make it binary searchable.
+type CounterUint16 struct {
+ Counter[uint16, int]
 }
 
-func (t *KeyValUint8) Optimize() {
-	temp := make([]sortIntUint8.KeyVal, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
+// KeyUint16 copies the keys to a KeyUint16 structure.
+func (t *CounterUint16) KeyUint16() *KeyUint16 {
+	return t.Key()
 }
 
-func (t *KeyValUint8) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
+// KeyValUint16 copies the keys and values to a KeyValUint16 structure.
+func (t *CounterUint16) KeyValUint16() *KeyValUint16 {
+	return t.KeyVal()
 }
 
-func (t *KeyValUint8) Next() (uint8, int, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v.V, v.K, true
-	}
-	return v.V, v.K, false
+// NewCounterUint16 reuses the memory of ar for the counter.
+func NewCounterUint16(ar []sortIntUint16.KeyVal) *CounterUint16 {
+	key := unsafe.Slice((*sortOrdered.KeyVal[uint16, int])(unsafe.SliceData(ar)), cap(ar))
+	return &CounterUint16{Counter[uint16, int]{key: key[0:0]}}
 }
 
-func (t *KeyValUint8) Keys() []uint8 {
-	keys := make([]uint8, len(t.key))
-	for i, v := range t.key {
-		keys[i] = v.V
-	}
-	return keys
+// RawKey returns the keys and frequencies, this is not a copy.
+func (t *CounterUint16) RawKey() []sortIntUint16.KeyVal {
+	return unsafe.Slice((*sortIntUint16.KeyVal)(unsafe.SliceData(t.key)), cap(t.key))[0:len(t.key)]
 }
 
-// ---------- CounterUint8 ----------
+type KeyUint8 = Key[uint8]
+type KeyValUint8 = KeyVal[uint8, int]
 
 // Add this to any struct to make it binary searchable.
 type CounterUint8 struct {
- key []sortIntUint8.KeyVal
- cursor int
+ Counter[uint8, int]
+}
+
+// KeyUint8 copies the keys to a KeyUint8 structure.
+func (t *CounterUint8) KeyUint8() *KeyUint8 {
+	return t.Key()
+}
+
+// KeyValUint8 copies the keys and values to a KeyValUint8 structure.
+func (t *CounterUint8) KeyValUint8() *KeyValUint8 {
+	return t.KeyVal()
 }
 
+// NewCounterUint8 reuses the memory of ar for the counter.
 func NewCounterUint8(ar []sortIntUint8.KeyVal) *CounterUint8 {
-	return &CounterUint8{key: ar[0:0]}
+	key := unsafe.Slice((*sortOrdered.KeyVal[uint8, int])(unsafe.SliceData(ar)), cap(ar))
+	return &CounterUint8{Counter[uint8, int]{key: key[0:0]}}
 }
 
+// RawKey returns the keys and frequencies, this is not a copy.
 func (t *CounterUint8) RawKey() []sortIntUint8.KeyVal {
-	return t.key
+	return unsafe.Slice((*sortIntUint8.KeyVal)(unsafe.SliceData(t.key)), cap(t.key))[0:len(t.key)]
 }
 
-func (t *CounterUint8) KeyUint8() *KeyUint8 {
-	obj := new(KeyUint8)
-	key := make([]uint8, len(t.key))
-	for i, v := range t.key {
-		key[i] = v.V
-	}
-	obj.key = key
-	return obj
+type KeyInt = Key[int]
+type KeyValInt = KeyVal[int, int]
+
+// Add this to any struct to make it binary searchable.
+type CounterInt struct {
+ Counter[int, int]
 }
 
-func (t *CounterUint8) KeyValUint8() *KeyValUint8 {
-	obj := new(KeyValUint8)
-	key := make([]sortIntUint8.KeyVal, len(t.key))
-	copy(key, t.key)
-	obj.key = key
-	return obj
+// KeyInt copies the keys to a KeyInt structure.
+func (t *CounterInt) KeyInt() *KeyInt {
+	return t.Key()
 }
 
-func (t *CounterUint8) Len() int {
-	return len(t.key)
+// KeyValInt copies the keys and values to a KeyValInt structure.
+func (t *CounterInt) KeyValInt() *KeyValInt {
+	return t.KeyVal()
 }
 
-// Find returns the index based on the key.
-func (t *CounterUint8) Find(thekey uint8) (int, bool) {
-	var min, at int
-	var current uint8
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				return t.key[at].K, true // found
-			}
-		}
-	}
-	return 0, false // doesn't exist
+// NewCounterInt reuses the memory of ar for the counter.
+func NewCounterInt(ar []sortIntInt.KeyVal) *CounterInt {
+	key := unsafe.Slice((*sortOrdered.KeyVal[int, int])(unsafe.SliceData(ar)), cap(ar))
+	return &CounterInt{Counter[int, int]{key: key[0:0]}}
 }
 
-// Modifies the value of the key by running it through the provided function.
-func (t *CounterUint8) Update(thekey uint8, fn func(int) int) bool {
-	var min, at int
-	var current uint8
-	max := len(t.key) - 1
-	for min <= max {
-		at = min + ((max - min) / 2)
-		if current=t.key[at].V; thekey < current {
-			max = at - 1
-		} else {
-		if thekey > current {
-			min = at + 1
-			} else {
-				t.key[at].K = fn(t.key[at].K)
-				return true // found
-			}
-		}
-	}
-	return false // doesn't exist
+// RawKey returns the keys and frequencies, this is not a copy.
+func (t *CounterInt) RawKey() []sortIntInt.KeyVal {
+	return unsafe.Slice((*sortIntInt.KeyVal)(unsafe.SliceData(t.key)), cap(t.key))[0:len(t.key)]
+}
+
+type KeyInt64 = Key[int64]
+type KeyValInt64 = KeyVal[int64, int]
+
+// Add this to any struct to make it binary searchable.
+type CounterInt64 struct {
+ Counter[int64, int]
 }
 
-// Modifies all values by running each through the provided function.
-func (t *CounterUint8) UpdateAll(fn func(int) int) {
-	tmp := t.key
-	l := len(tmp)
-	for i:=0; i<l; i++ {
-		tmp[i].K = fn(tmp[i].K)
-	}
+// KeyInt64 copies the keys to a KeyInt64 structure.
+func (t *CounterInt64) KeyInt64() *KeyInt64 {
+	return t.Key()
 }
 
-// AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *CounterUint8) Add(thekey uint8, theval int) {
-	t.key = append(t.key, sortIntUint8.KeyVal{theval, thekey})
+// KeyValInt64 copies the keys and values to a KeyValInt64 structure.
+func (t *CounterInt64) KeyValInt64() *KeyValInt64 {
+	return t.KeyVal()
 }
 
-// Build sorts the keys and values.
-func (t *CounterUint8) Build() {
-	var temp = t.key
-	if len(temp) == 0 {
-		return
-	}
-	sortIntUint8.Asc(temp)
-	this := t.key[0].V
-	n := t.key[0].K
-	var on int
-	for _, k := range t.key[1:] {
-		if k.V == this {
-			n += k.K
-		} else {
-			temp[on] = sortIntUint8.KeyVal{n, this}
-			on++
-			this = k.V
-			n = k.K
-		}
-	}
-	temp[on] = sortIntUint8.KeyVal{n, this}
-	t.key = temp[0:on+1]
+// NewCounterInt64 reuses the memory of ar for the counter.
+func NewCounterInt64(ar []sortOrdered.KeyVal[int64, int]) *CounterInt64 {
+	return &CounterInt64{Counter[int64, int]{key: ar[0:0]}}
 }
 
-func (t *CounterUint8) Optimize() {
-	temp := make([]sortIntUint8.KeyVal, len(t.key))
-	copy(temp, t.key)
-	t.key = temp
+type KeyInt32 = Key[int32]
+type KeyValInt32 = KeyVal[int32, int]
+
+// Add this to any struct to make it binary searchable.
+type CounterInt32 struct {
+ Counter[int32, int]
 }
 
-func (t *CounterUint8) Reset() bool {
-	t.cursor = 0
-	if len(t.key) == 0 {
-		return false
-	}
-	return true
+// KeyInt32 copies the keys to a KeyInt32 structure.
+func (t *CounterInt32) KeyInt32() *KeyInt32 {
+	return t.Key()
 }
 
-func (t *CounterUint8) Next() (uint8, int, bool) {
-	v := t.key[t.cursor]
-	if t.cursor++; t.cursor == len(t.key) {
-		t.cursor = 0
-		return v.V, v.K, true
-	}
-	return v.V, v.K, false
+// KeyValInt32 copies the keys and values to a KeyValInt32 structure.
+func (t *CounterInt32) KeyValInt32() *KeyValInt32 {
+	return t.KeyVal()
 }
 
-func (t *CounterUint8) Keys() []uint8 {
-	keys := make([]uint8, len(t.key))
-	for i, v := range t.key {
-		keys[i] = v.V
-	}
-	return keys
+// NewCounterInt32 reuses the memory of ar for the counter.
+func NewCounterInt32(ar []sortOrdered.KeyVal[int32, int]) *CounterInt32 {
+	return &CounterInt32{Counter[int32, int]{key: ar[0:0]}}
 }
 
-// ------------- export ---------------
+type KeyInt16 = Key[int16]
+type KeyValInt16 = KeyVal[int16, int]
 
-func (t *KeyUint8) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteByte(v)
-	}
+// Add this to any struct to make it binary searchable.
+type CounterInt16 struct {
+ Counter[int16, int]
 }
 
-func (t *KeyUint8) Read(r *custom.Reader) {
-	l := int(r.ReadUint64Variable())
-	tmp := make([]uint8, l)
-	for i:=0; i<l; i++ {
-		tmp[i] = r.ReadByte()
-	}
-	t.key = tmp
+// KeyInt16 copies the keys to a KeyInt16 structure.
+func (t *CounterInt16) KeyInt16() *KeyInt16 {
+	return t.Key()
 }
 
-func (t *KeyValUint8) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v.K))
-		w.WriteByte(v.V)
+// KeyValInt16 copies the keys and values to a KeyValInt16 structure.
+func (t *CounterInt16) KeyValInt16() *KeyValInt16 {
+	return t.KeyVal()
+}
+
+// NewCounterInt16 reuses the memory of ar for the counter.
+func NewCounterInt16(ar []sortOrdered.KeyVal[int16, int]) *CounterInt16 {
+	return &CounterInt16{Counter[int16, int]{key: ar[0:0]}}
+}
+
+type KeyInt8 = Key[int8]
+type KeyValInt8 = KeyVal[int8, int]
+
+// Add this to any struct to make it binary searchable.
+type CounterInt8 struct {
+ Counter[int8, int]
+}
+
+// KeyInt8 copies the keys to a KeyInt8 structure.
+func (t *CounterInt8) KeyInt8() *KeyInt8 {
+	return t.Key()
+}
+
+// KeyValInt8 copies the keys and values to a KeyValInt8 structure.
+func (t *CounterInt8) KeyValInt8() *KeyValInt8 {
+	return t.KeyVal()
+}
+
+// NewCounterInt8 reuses the memory of ar for the counter.
+func NewCounterInt8(ar []sortOrdered.KeyVal[int8, int]) *CounterInt8 {
+	return &CounterInt8{Counter[int8, int]{key: ar[0:0]}}
+}
+
+// lowerBound returns the index of the first key >= thekey, or len(key) if there isn't one.
+func lowerBound[K cmp.Ordered](key []K, thekey K) int {
+	var at, min int
+	max := len(key)
+	for min < max {
+		at = min + ((max - min) / 2)
+		if key[at] < thekey {
+			min = at + 1
+		} else {
+			max = at
+		}
 	}
+	return min
 }
 
-func (t *KeyValUint8) Read(r *custom.Reader) {
-	var k int
-	var v uint8
-	l := int(r.ReadUint64Variable())
-	tmp := make([]sortIntUint8.KeyVal, l)
-	for i:=0; i<l; i++ {
-		k = int(r.ReadUint64Variable())
-		v = r.ReadByte()
-		tmp[i] = sortIntUint8.KeyVal{k, v}
+// upperBound returns the index of the first key > thekey, or len(key) if there isn't one.
+func upperBound[K cmp.Ordered](key []K, thekey K) int {
+	var at, min int
+	max := len(key)
+	for min < max {
+		at = min + ((max - min) / 2)
+		if key[at] <= thekey {
+			min = at + 1
+		} else {
+			max = at
+		}
 	}
-	t.key = tmp
+	return min
 }
 
-func (t *CounterUint8) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
-	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v.K))
-		w.WriteByte(v.V)
+// lowerBoundKeyVal is lowerBound for the KeyVal & Counter structures, where the key is V.
+func lowerBoundKeyVal[K cmp.Ordered, V any](key []sortOrdered.KeyVal[K, V], thekey K) int {
+	var at, min int
+	max := len(key)
+	for min < max {
+		at = min + ((max - min) / 2)
+		if key[at].V < thekey {
+			min = at + 1
+		} else {
+			max = at
+		}
 	}
+	return min
 }
 
-func (t *CounterUint8) Read(r *custom.Reader) {
-	var k int
-	var v uint8
-	l := int(r.ReadUint64Variable())
-	tmp := make([]sortIntUint8.KeyVal, l)
-	for i:=0; i<l; i++ {
-		k = int(r.ReadUint64Variable())
-		v = r.ReadByte()
-		tmp[i] = sortIntUint8.KeyVal{k, v}
+// upperBoundKeyVal is upperBound for the KeyVal & Counter structures, where the key is V.
+func upperBoundKeyVal[K cmp.Ordered, V any](key []sortOrdered.KeyVal[K, V], thekey K) int {
+	var at, min int
+	max := len(key)
+	for min < max {
+		at = min + ((max - min) / 2)
+		if key[at].V <= thekey {
+			min = at + 1
+		} else {
+			max = at
+		}
 	}
-	t.key = tmp
+	return min
 }
 
-// ====================== int ======================
-// ---------- KeyInt ----------
+// ---------- Key ----------
 
 // Add this to any struct to make it binary searchable.
-type KeyInt struct {
- key []int
+type Key[K cmp.Ordered] struct {
+ key []K
  cursor int
 }
 
-func (t *KeyInt) Len() int {
+func NewKey[K cmp.Ordered](i int) *Key[K] {
+	return &Key[K]{key:make([]K, 0, i), cursor:0}
+}
+
+func (t *Key[K]) Len() int {
 	return len(t.key)
 }
 
 // Find returns the index based on the key.
-func (t *KeyInt) Find(thekey int) (int, bool) {
+func (t *Key[K]) Find(thekey K) (int, bool) {
 	var min, at int
-	var current int
+	var current K
 	max := len(t.key) - 1
 	for min <= max {
 		at = min + ((max - min) / 2)
@@ -8409,7 +8143,7 @@ func (t *KeyInt) Find(thekey int) (int, bool) {
 }
 
 // Add is equivalent to Find and then AddAt
-func (t *KeyInt) Add(thekey int) (int, bool) {
+func (t *Key[K]) Add(thekey K) (int, bool) {
 	i, ok := t.Find(thekey)
 	if !ok {
 		t.AddAt(thekey, i)
@@ -8418,17 +8152,17 @@ func (t *KeyInt) Add(thekey int) (int, bool) {
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *KeyInt) AddUnsorted(thekey int) {
+func (t *Key[K]) AddUnsorted(thekey K) {
 	t.key = append(t.key, thekey)
 	return
 }
 
 // AddAt adds this key to the index in this exact position, so it does not require later rebuilding.
-func (t *KeyInt) AddAt(thekey int, i int) {
+func (t *Key[K]) AddAt(thekey K, i int) {
 	cur := t.key
 	lc := len(cur)
 	if lc == cap(cur) {
-		tmp := make([]int, lc + 1, (lc * 2) + 1)
+		tmp := make([]K, lc + 1, (lc * 2) + 1)
 		copy(tmp, cur[0:i])
 		copy(tmp[i+1:], cur[i:])
 		cur = tmp
@@ -8441,15 +8175,15 @@ func (t *KeyInt) AddAt(thekey int, i int) {
 }
 
 // Build sorts the keys and returns an array telling you how to sort the values, you must do this yourself.
-func (t *KeyInt) Build() []int {
+func (t *Key[K]) Build() []int {
 	l := len(t.key)
-	temp := make([]sortIntInt.KeyVal, l)
+	temp := make([]sortOrdered.KeyVal[K, int], l)
 	var i int
-	var k int
+	var k K
 	for i, k = range t.key {
-		temp[i] = sortIntInt.KeyVal{i, k}
+		temp[i] = sortOrdered.KeyVal[K, int]{i, k}
 	}
-	sortIntInt.Asc(temp)
+	sortOrdered.Asc(temp)
 	imap := make([]int, l)
 	newkey := t.key
 	for i, obj := range temp {
@@ -8459,13 +8193,13 @@ func (t *KeyInt) Build() []int {
 	return imap
 }
 
-func (t *KeyInt) Optimize() {
-	temp := make([]int, len(t.key))
+func (t *Key[K]) Optimize() {
+	temp := make([]K, len(t.key))
 	copy(temp, t.key)
 	t.key = temp
 }
 
-func (t *KeyInt) Reset() bool {
+func (t *Key[K]) Reset() bool {
 	t.cursor = 0
 	if len(t.key) == 0 {
 		return false
@@ -8473,7 +8207,7 @@ func (t *KeyInt) Reset() bool {
 	return true
 }
 
-func (t *KeyInt) Next() (int, bool) {
+func (t *Key[K]) Next() (K, bool) {
 	v := t.key[t.cursor]
 	if t.cursor++; t.cursor == len(t.key) {
 		t.cursor = 0
@@ -8482,26 +8216,35 @@ func (t *KeyInt) Next() (int, bool) {
 	return v, false
 }
 
-func (t *KeyInt) Keys() []int {
+func (t *Key[K]) Keys() []K {
 	return t.key
 }
 
-// ---------- KeyValInt ----------
+// ---------- KeyVal ----------
 
 // Add this to any struct to make it binary searchable.
-type KeyValInt struct {
- key []sortIntInt.KeyVal
+// V can be any type. Write & Read support integers, floats, bools, strings and []byte (see DefaultCodec), for anything else use WriteWith & ReadWith.
+type KeyVal[K cmp.Ordered, V any] struct {
+ key []sortOrdered.KeyVal[K, V]
  cursor int
+ byvalue valueOrder // used by ResetByValue & NextByValue
+ valindex []int // positions sorted by value, used by FindByValue
+ compare func(V, V) int // the order of the values, set by NewKeyValFunc or DefaultCompare
+}
+
+// NewKeyValFunc returns a KeyVal whose values are ordered by compare, for TopK, NextByValue & FindByValue when V is not an integer, float or string type.
+func NewKeyValFunc[K cmp.Ordered, V any](compare func(V, V) int) *KeyVal[K, V] {
+	return &KeyVal[K, V]{compare: compare}
 }
 
-func (t *KeyValInt) Len() int {
+func (t *KeyVal[K, V]) Len() int {
 	return len(t.key)
 }
 
 // Find returns the index based on the key.
-func (t *KeyValInt) Find(thekey int) (int, bool) {
+func (t *KeyVal[K, V]) Find(thekey K) (V, bool) {
 	var min, at int
-	var current int
+	var current K
 	max := len(t.key) - 1
 	for min <= max {
 		at = min + ((max - min) / 2)
@@ -8515,13 +8258,14 @@ func (t *KeyValInt) Find(thekey int) (int, bool) {
 			}
 		}
 	}
-	return 0, false // doesn't exist
+	return *new(V), false // doesn't exist
 }
 
 // Modifies the value of the key by running it through the provided function
-func (t *KeyValInt) Update(thekey int, fn func(int) int) bool {
+func (t *KeyVal[K, V]) Update(thekey K, fn func(V) V) bool {
+	t.valindex = nil
 	var min, at int
-	var current int
+	var current K
 	max := len(t.key) - 1
 	for min <= max {
 		at = min + ((max - min) / 2)
@@ -8540,7 +8284,8 @@ func (t *KeyValInt) Update(thekey int, fn func(int) int) bool {
 }
 
 // Modifies all values by running each through the provided function
-func (t *KeyValInt) UpdateAll(fn func(int) int) {
+func (t *KeyVal[K, V]) UpdateAll(fn func(V) V) {
+	t.valindex = nil
 	tmp := t.key
 	l := len(tmp)
 	for i:=0; i<l; i++ {
@@ -8549,9 +8294,10 @@ func (t *KeyValInt) UpdateAll(fn func(int) int) {
 }
 
 // Add is equivalent to Find and then AddAt
-func (t *KeyValInt) Add(thekey int, theval int) bool {
+func (t *KeyVal[K, V]) Add(thekey K, theval V) bool {
+	t.valindex = nil
 	var min, at int
-	var current int
+	var current K
 	max := len(t.key) - 1
 	for min <= max {
 		at = min + ((max - min) / 2)
@@ -8561,9 +8307,7 @@ func (t *KeyValInt) Add(thekey int, theval int) bool {
 		if thekey > current {
 			min = at + 1
 			} else {
-				if t.key[at].K != theval {
-					t.key[at].K = theval
-				}
+				t.key[at].K = theval
 				return true // found
 			}
 		}
@@ -8571,7 +8315,7 @@ func (t *KeyValInt) Add(thekey int, theval int) bool {
 	cur := t.key
 	lc := len(cur)
 	if lc == cap(cur) {
-		tmp := make([]sortIntInt.KeyVal, lc + 1, (lc * 2) + 1)
+		tmp := make([]sortOrdered.KeyVal[K, V], lc + 1, (lc * 2) + 1)
 		copy(tmp, cur[0:min])
 		copy(tmp[min+1:], cur[min:])
 		cur = tmp
@@ -8579,29 +8323,31 @@ func (t *KeyValInt) Add(thekey int, theval int) bool {
 		cur = cur[0:lc+1]
 		copy(cur[min+1:], cur[min:])
 	}
-	cur[min] = sortIntInt.KeyVal{theval, thekey}
+	cur[min] = sortOrdered.KeyVal[K, V]{theval, thekey}
 	t.key = cur
 	return false
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *KeyValInt) AddUnsorted(thekey int, theval int) {
-	t.key = append(t.key, sortIntInt.KeyVal{theval, thekey})
+func (t *KeyVal[K, V]) AddUnsorted(thekey K, theval V) {
+	t.valindex = nil
+	t.key = append(t.key, sortOrdered.KeyVal[K, V]{theval, thekey})
 	return
 }
 
 // Build sorts the keys and values.
-func (t *KeyValInt) Build() {
-	sortIntInt.Asc(t.key)
+func (t *KeyVal[K, V]) Build() {
+	t.valindex = nil
+	sortOrdered.Asc(t.key)
 }
 
-func (t *KeyValInt) Optimize() {
-	temp := make([]sortIntInt.KeyVal, len(t.key))
+func (t *KeyVal[K, V]) Optimize() {
+	temp := make([]sortOrdered.KeyVal[K, V], len(t.key))
 	copy(temp, t.key)
 	t.key = temp
 }
 
-func (t *KeyValInt) Reset() bool {
+func (t *KeyVal[K, V]) Reset() bool {
 	t.cursor = 0
 	if len(t.key) == 0 {
 		return false
@@ -8609,7 +8355,7 @@ func (t *KeyValInt) Reset() bool {
 	return true
 }
 
-func (t *KeyValInt) Next() (int, int, bool) {
+func (t *KeyVal[K, V]) Next() (K, V, bool) {
 	v := t.key[t.cursor]
 	if t.cursor++; t.cursor == len(t.key) {
 		t.cursor = 0
@@ -8618,33 +8364,36 @@ func (t *KeyValInt) Next() (int, int, bool) {
 	return v.V, v.K, false
 }
 
-func (t *KeyValInt) Keys() []int {
-	keys := make([]int, len(t.key))
+func (t *KeyVal[K, V]) Keys() []K {
+	keys := make([]K, len(t.key))
 	for i, v := range t.key {
 		keys[i] = v.V
 	}
 	return keys
 }
 
-// ---------- CounterInt ----------
+// ---------- Counter ----------
 
 // Add this to any struct to make it binary searchable.
-type CounterInt struct {
- key []sortIntInt.KeyVal
+type Counter[K cmp.Ordered, N Number] struct {
+ key []sortOrdered.KeyVal[K, N]
  cursor int
+ byvalue valueOrder // used by ResetByValue & NextByValue
 }
 
-func NewCounterInt(ar []sortIntInt.KeyVal) *CounterInt {
-	return &CounterInt{key: ar[0:0]}
+// NewCounter reuses the memory of ar for the counter.
+func NewCounter[K cmp.Ordered, N Number](ar []sortOrdered.KeyVal[K, N]) *Counter[K, N] {
+	return &Counter[K, N]{key: ar[0:0]}
 }
 
-func (t *CounterInt) RawKey() []sortIntInt.KeyVal {
+func (t *Counter[K, N]) RawKey() []sortOrdered.KeyVal[K, N] {
 	return t.key
 }
 
-func (t *CounterInt) KeyInt() *KeyInt {
-	obj := new(KeyInt)
-	key := make([]int, len(t.key))
+// Key copies the keys to a Key structure.
+func (t *Counter[K, N]) Key() *Key[K] {
+	obj := new(Key[K])
+	key := make([]K, len(t.key))
 	for i, v := range t.key {
 		key[i] = v.V
 	}
@@ -8652,22 +8401,23 @@ func (t *CounterInt) KeyInt() *KeyInt {
 	return obj
 }
 
-func (t *CounterInt) KeyValInt() *KeyValInt {
-	obj := new(KeyValInt)
-	key := make([]sortIntInt.KeyVal, len(t.key))
+// KeyVal copies the keys and values to a KeyVal structure.
+func (t *Counter[K, N]) KeyVal() *KeyVal[K, N] {
+	obj := new(KeyVal[K, N])
+	key := make([]sortOrdered.KeyVal[K, N], len(t.key))
 	copy(key, t.key)
 	obj.key = key
 	return obj
 }
 
-func (t *CounterInt) Len() int {
+func (t *Counter[K, N]) Len() int {
 	return len(t.key)
 }
 
 // Find returns the index based on the key.
-func (t *CounterInt) Find(thekey int) (int, bool) {
+func (t *Counter[K, N]) Find(thekey K) (N, bool) {
 	var min, at int
-	var current int
+	var current K
 	max := len(t.key) - 1
 	for min <= max {
 		at = min + ((max - min) / 2)
@@ -8685,9 +8435,9 @@ func (t *CounterInt) Find(thekey int) (int, bool) {
 }
 
 // Modifies the value of the key by running it through the provided function.
-func (t *CounterInt) Update(thekey int, fn func(int) int) bool {
+func (t *Counter[K, N]) Update(thekey K, fn func(N) N) bool {
 	var min, at int
-	var current int
+	var current K
 	max := len(t.key) - 1
 	for min <= max {
 		at = min + ((max - min) / 2)
@@ -8706,7 +8456,7 @@ func (t *CounterInt) Update(thekey int, fn func(int) int) bool {
 }
 
 // Modifies all values by running each through the provided function.
-func (t *CounterInt) UpdateAll(fn func(int) int) {
+func (t *Counter[K, N]) UpdateAll(fn func(N) N) {
 	tmp := t.key
 	l := len(tmp)
 	for i:=0; i<l; i++ {
@@ -8715,41 +8465,61 @@ func (t *CounterInt) UpdateAll(fn func(int) int) {
 }
 
 // AddUnsorted adds this key to the end of the index for later building with Build.
-func (t *CounterInt) Add(thekey int, theval int) {
-	t.key = append(t.key, sortIntInt.KeyVal{theval, thekey})
+func (t *Counter[K, N]) Add(thekey K, theval N) {
+	t.key = append(t.key, sortOrdered.KeyVal[K, N]{theval, thekey})
 }
 
 // Build sorts the keys and values.
-func (t *CounterInt) Build() {
+func (t *Counter[K, N]) Build() {
+	t.build(false)
+}
+
+// BuildChecked is Build but it returns ErrOverflow if any total doesn't fit in N (the total is then wrapped around).
+// Floats never overflow, they become +Inf or -Inf.
+func (t *Counter[K, N]) BuildChecked() error {
+	if t.build(true) {
+		return ErrOverflow
+	}
+	return nil
+}
+
+// build returns true if check is true and there was an overflow.
+func (t *Counter[K, N]) build(check bool) bool {
 	var temp = t.key
 	if len(temp) == 0 {
-		return
+		return false
 	}
-	sortIntInt.Asc(temp)
+	sortOrdered.Asc(temp)
 	this := t.key[0].V
 	n := t.key[0].K
 	var on int
+	var zero, c N
+	var overflow bool
 	for _, k := range t.key[1:] {
 		if k.V == this {
-			n += k.K
+			c = n + k.K
+			if check && ((k.K > zero && c < n) || (k.K < zero && c > n)) {
+				overflow = true
+			}
+			n = c
 		} else {
-			temp[on] = sortIntInt.KeyVal{n, this}
+			temp[on] = sortOrdered.KeyVal[K, N]{n, this}
 			on++
 			this = k.V
 			n = k.K
 		}
 	}
-	temp[on] = sortIntInt.KeyVal{n, this}
+	temp[on] = sortOrdered.KeyVal[K, N]{n, this}
 	t.key = temp[0:on+1]
+	return overflow
 }
-
-func (t *CounterInt) Optimize() {
-	temp := make([]sortIntInt.KeyVal, len(t.key))
+func (t *Counter[K, N]) Optimize() {
+	temp := make([]sortOrdered.KeyVal[K, N], len(t.key))
 	copy(temp, t.key)
 	t.key = temp
 }
 
-func (t *CounterInt) Reset() bool {
+func (t *Counter[K, N]) Reset() bool {
 	t.cursor = 0
 	if len(t.key) == 0 {
 		return false
@@ -8757,7 +8527,7 @@ func (t *CounterInt) Reset() bool {
 	return true
 }
 
-func (t *CounterInt) Next() (int, int, bool) {
+func (t *Counter[K, N]) Next() (K, N, bool) {
 	v := t.key[t.cursor]
 	if t.cursor++; t.cursor == len(t.key) {
 		t.cursor = 0
@@ -8766,8 +8536,8 @@ func (t *CounterInt) Next() (int, int, bool) {
 	return v.V, v.K, false
 }
 
-func (t *CounterInt) Keys() []int {
-	keys := make([]int, len(t.key))
+func (t *Counter[K, N]) Keys() []K {
+	keys := make([]K, len(t.key))
 	for i, v := range t.key {
 		keys[i] = v.V
 	}
@@ -8776,60 +8546,488 @@ func (t *CounterInt) Keys() []int {
 
 // ------------- export ---------------
 
-func (t *KeyInt) Write(w custom.Interface) {
+/*
+	Keys are written in the same format the per-type structures always used, so existing files can still be read:
+	uint8 as a byte, uint16 as 2 bytes, strings as their length followed by the bytes, floats as 8 bytes, and all other integers as variable length uint64.
+	The funcs are selected once per Write or Read, named types (e.g. type ID uint32) fall back to reflection.
+*/
+
+// zigzag maps small negative numbers to small positive numbers so they stay short as varints: 0, -1, 1, -2 = 0, 1, 2, 3.
+// It is used for int32 & int64 keys. int keys are written as they always were.
+func zigzag(v int64) uint64 {
+	return uint64(v << 1) ^ uint64(v >> 63)
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v >> 1) ^ -int64(v & 1)
+}
+
+func orderedWriter[K cmp.Ordered]() func(custom.Interface, K) {
+	var fn interface{}
+	switch interface{}(*new(K)).(type) {
+		case uint8: fn = func(w custom.Interface, v uint8) { w.WriteByte(v) }
+		case uint16: fn = func(w custom.Interface, v uint16) { w.WriteUint16(v) }
+		case uint32: fn = func(w custom.Interface, v uint32) { w.WriteUint64Variable(uint64(v)) }
+		case uint64: fn = func(w custom.Interface, v uint64) { w.WriteUint64Variable(v) }
+		case int: fn = func(w custom.Interface, v int) { w.WriteUint64Variable(uint64(v)) }
+		case int8: fn = func(w custom.Interface, v int8) { w.WriteByte(uint8(v)) }
+		case int16: fn = func(w custom.Interface, v int16) { w.WriteUint16(uint16(v)) }
+		case int32: fn = func(w custom.Interface, v int32) { w.WriteUint64Variable(zigzag(int64(v))) }
+		case int64: fn = func(w custom.Interface, v int64) { w.WriteUint64Variable(zigzag(v)) }
+	}
+	if f, ok := fn.(func(custom.Interface, K)); ok {
+		return f
+	}
+	return func(w custom.Interface, v K) {
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+			case reflect.Uint8: w.WriteByte(uint8(rv.Uint()))
+			case reflect.Int8: w.WriteByte(uint8(rv.Int()))
+			case reflect.Uint16: w.WriteUint16(uint16(rv.Uint()))
+			case reflect.Int16: w.WriteUint16(uint16(rv.Int()))
+			case reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr: w.WriteUint64Variable(rv.Uint())
+			case reflect.Int: w.WriteUint64Variable(uint64(rv.Int()))
+			case reflect.Int32, reflect.Int64: w.WriteUint64Variable(zigzag(rv.Int()))
+			case reflect.Float32, reflect.Float64: w.WriteUint64(math.Float64bits(rv.Float()))
+			case reflect.String:
+				s := rv.String()
+				w.WriteUint64Variable(uint64(len(s)))
+				for i:=0; i<len(s); i++ {
+					w.WriteByte(s[i])
+				}
+		}
+	}
+}
+
+func orderedReader[K cmp.Ordered]() func(*custom.Reader) K {
+	var fn interface{}
+	switch interface{}(*new(K)).(type) {
+		case uint8: fn = func(r *custom.Reader) uint8 { return r.ReadByte() }
+		case uint16: fn = func(r *custom.Reader) uint16 { return r.ReadUint16() }
+		case uint32: fn = func(r *custom.Reader) uint32 { return uint32(r.ReadUint64Variable()) }
+		case uint64: fn = func(r *custom.Reader) uint64 { return r.ReadUint64Variable() }
+		case int: fn = func(r *custom.Reader) int { return int(r.ReadUint64Variable()) }
+		case int8: fn = func(r *custom.Reader) int8 { return int8(r.ReadByte()) }
+		case int16: fn = func(r *custom.Reader) int16 { return int16(r.ReadUint16()) }
+		case int32: fn = func(r *custom.Reader) int32 { return int32(unzigzag(r.ReadUint64Variable())) }
+		case int64: fn = func(r *custom.Reader) int64 { return unzigzag(r.ReadUint64Variable()) }
+	}
+	if f, ok := fn.(func(*custom.Reader) K); ok {
+		return f
+	}
+	return func(r *custom.Reader) K {
+		var v K
+		rv := reflect.ValueOf(&v).Elem()
+		switch rv.Kind() {
+			case reflect.Uint8: rv.SetUint(uint64(r.ReadByte()))
+			case reflect.Int8: rv.SetInt(int64(int8(r.ReadByte())))
+			case reflect.Uint16: rv.SetUint(uint64(r.ReadUint16()))
+			case reflect.Int16: rv.SetInt(int64(int16(r.ReadUint16())))
+			case reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr: rv.SetUint(r.ReadUint64Variable())
+			case reflect.Int: rv.SetInt(int64(r.ReadUint64Variable()))
+			case reflect.Int32, reflect.Int64: rv.SetInt(unzigzag(r.ReadUint64Variable()))
+			case reflect.Float32, reflect.Float64: rv.SetFloat(math.Float64frombits(r.ReadUint64()))
+			case reflect.String:
+				s := make([]byte, r.ReadUint64Variable())
+				for i := range s {
+					s[i] = r.ReadByte()
+				}
+				rv.SetString(string(s))
+		}
+		return v
+	}
+}
+
+// ValueCodec writes and reads the values of a KeyVal structure, see WriteWith & ReadWith.
+type ValueCodec[V any] interface {
+	WriteValue(w custom.Interface, v V)
+	ReadValue(r *custom.Reader) V
+}
+
+// ErrNoCodec is returned by DefaultCodec for value types that Write & Read don't support.
+var ErrNoCodec = errors.New(`There is no default codec for this value type, use WriteWith & ReadWith`)
+
+/*
+	DefaultCodec returns the codec used by Write & Read for the value type V, or ErrNoCodec.
+	Integers (and types based on them) are written as varints of their uint64 value, as they always were, floats as their float64 bits,
+	bools as a byte, and strings and []byte with their length first.
+	The type of V is checked once here and not for each value, so a structure can check it before it's filled.
+*/
+func DefaultCodec[V any]() (ValueCodec[V], error) {
+	typ := reflect.TypeOf((*V)(nil)).Elem()
+	switch typ.Kind() {
+		case reflect.Int: return integerCodec[V, int]{}, nil
+		case reflect.Int8: return integerCodec[V, int8]{}, nil
+		case reflect.Int16: return integerCodec[V, int16]{}, nil
+		case reflect.Int32: return integerCodec[V, int32]{}, nil
+		case reflect.Int64: return integerCodec[V, int64]{}, nil
+		case reflect.Uint: return integerCodec[V, uint]{}, nil
+		case reflect.Uint8: return integerCodec[V, uint8]{}, nil
+		case reflect.Uint16: return integerCodec[V, uint16]{}, nil
+		case reflect.Uint32: return integerCodec[V, uint32]{}, nil
+		case reflect.Uint64: return integerCodec[V, uint64]{}, nil
+		case reflect.Uintptr: return integerCodec[V, uintptr]{}, nil
+		case reflect.Float32: return floatCodec[V, float32]{}, nil
+		case reflect.Float64: return floatCodec[V, float64]{}, nil
+		case reflect.Bool: return boolCodec[V]{}, nil
+		case reflect.String: return stringCodec[V]{}, nil
+		case reflect.Slice:
+			if typ.Elem().Kind() == reflect.Uint8 {
+				return bytesCodec[V]{}, nil
+			}
+	}
+	return nil, ErrNoCodec
+}
+
+// mustCodec is DefaultCodec for Write & Read, it panics before anything is written or read.
+func mustCodec[V any]() ValueCodec[V] {
+	codec, err := DefaultCodec[V]()
+	if err != nil {
+		panic(err)
+	}
+	return codec
+}
+
+// The codecs convert V to the type it's based on, DefaultCodec has already checked the kind of V.
+
+type integerCodec[V any, T Integer] struct{}
+
+func (integerCodec[V, T]) WriteValue(w custom.Interface, v V) {
+	w.WriteUint64Variable(uint64(*(*T)(unsafe.Pointer(&v))))
+}
+
+func (integerCodec[V, T]) ReadValue(r *custom.Reader) V {
+	var v V
+	*(*T)(unsafe.Pointer(&v)) = T(r.ReadUint64Variable())
+	return v
+}
+
+type floatCodec[V any, T float32 | float64] struct{}
+
+func (floatCodec[V, T]) WriteValue(w custom.Interface, v V) {
+	w.WriteUint64(math.Float64bits(float64(*(*T)(unsafe.Pointer(&v)))))
+}
+
+func (floatCodec[V, T]) ReadValue(r *custom.Reader) V {
+	var v V
+	*(*T)(unsafe.Pointer(&v)) = T(math.Float64frombits(r.ReadUint64()))
+	return v
+}
+
+type boolCodec[V any] struct{}
+
+func (boolCodec[V]) WriteValue(w custom.Interface, v V) {
+	if *(*bool)(unsafe.Pointer(&v)) {
+		w.WriteByte(1)
+	} else {
+		w.WriteByte(0)
+	}
+}
+
+func (boolCodec[V]) ReadValue(r *custom.Reader) V {
+	var v V
+	*(*bool)(unsafe.Pointer(&v)) = r.ReadByte() == 1
+	return v
+}
+
+type stringCodec[V any] struct{}
+
+func (stringCodec[V]) WriteValue(w custom.Interface, v V) {
+	s := *(*string)(unsafe.Pointer(&v))
+	w.WriteUint64Variable(uint64(len(s)))
+	for i:=0; i<len(s); i++ {
+		w.WriteByte(s[i])
+	}
+}
+
+func (stringCodec[V]) ReadValue(r *custom.Reader) V {
+	var v V
+	s := make([]byte, r.ReadUint64Variable())
+	for i := range s {
+		s[i] = r.ReadByte()
+	}
+	*(*string)(unsafe.Pointer(&v)) = string(s)
+	return v
+}
+
+type bytesCodec[V any] struct{}
+
+func (bytesCodec[V]) WriteValue(w custom.Interface, v V) {
+	writeBytes(w, *(*[]byte)(unsafe.Pointer(&v)))
+}
+
+func (bytesCodec[V]) ReadValue(r *custom.Reader) V {
+	var v V
+	*(*[]byte)(unsafe.Pointer(&v)) = readBytes(r)
+	return v
+}
+
+func (t *Key[K]) Write(w custom.Interface) {
+	write := orderedWriter[K]()
 	w.WriteUint64Variable(uint64(len(t.key)))
 	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v))
+		write(w, v)
 	}
 }
 
-func (t *KeyInt) Read(r *custom.Reader) {
+func (t *Key[K]) Read(r *custom.Reader) {
+	read := orderedReader[K]()
 	l := int(r.ReadUint64Variable())
-	tmp := make([]int, l)
+	tmp := make([]K, l)
 	for i:=0; i<l; i++ {
-		tmp[i] = int(r.ReadUint64Variable())
+		tmp[i] = read(r)
 	}
 	t.key = tmp
 }
 
-func (t *KeyValInt) Write(w custom.Interface) {
-	w.WriteUint64Variable(uint64(len(t.key)))
+func (t *KeyVal[K, V]) Write(w custom.Interface) {
+	t.write(w, mustCodec[V]().WriteValue)
+}
+
+// WriteWith is Write for values that Write doesn't support, the codec writes each value.
+func (t *KeyVal[K, V]) WriteWith(w custom.Interface, codec ValueCodec[V]) {
+	t.write(w, codec.WriteValue)
+}
+
+func (t *KeyVal[K, V]) write(w custom.Interface, writeval func(custom.Interface, V)) {
+	write := orderedWriter[K]()
+	if t.valindex != nil { // flagged if the value index follows the keys
+		w.WriteUint64Variable(uint64(len(t.key)) | valueIndexFlag)
+	} else {
+		w.WriteUint64Variable(uint64(len(t.key)))
+	}
 	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v.K))
-		w.WriteUint64Variable(uint64(v.V))
+		writeval(w, v.K)
+		write(w, v.V)
 	}
+	writeValueIndex(w, t.valindex)
 }
 
-func (t *KeyValInt) Read(r *custom.Reader) {
-	var k int
-	var v int
-	l := int(r.ReadUint64Variable())
-	tmp := make([]sortIntInt.KeyVal, l)
-	for i:=0; i<l; i++ {
-		k = int(r.ReadUint64Variable())
-		v = int(r.ReadUint64Variable())
-		tmp[i] = sortIntInt.KeyVal{k, v}
+func (t *KeyVal[K, V]) Read(r *custom.Reader) {
+	t.read(r, mustCodec[V]().ReadValue)
+}
+
+// ReadWith is Read for values that Read doesn't support, the codec must match the one given to WriteWith.
+func (t *KeyVal[K, V]) ReadWith(r *custom.Reader, codec ValueCodec[V]) {
+	t.read(r, codec.ReadValue)
+}
+
+func (t *KeyVal[K, V]) read(r *custom.Reader, readval func(*custom.Reader) V) {
+	read := orderedReader[K]()
+	var k V
+	var v K
+	l := r.ReadUint64Variable()
+	indexed := l & valueIndexFlag != 0
+	l &^= valueIndexFlag
+	tmp := make([]sortOrdered.KeyVal[K, V], l)
+	for i:=0; i<len(tmp); i++ {
+		k = readval(r)
+		v = read(r)
+		tmp[i] = sortOrdered.KeyVal[K, V]{k, v}
 	}
 	t.key = tmp
+	t.valindex = nil
+	if indexed {
+		compare := t.compare
+		if compare == nil {
+			compare, _ = DefaultCompare[V]() // nil if V can't be ordered, then the index is dropped
+		}
+		var at func(a, b int) int
+		if compare != nil {
+			at = func(a, b int) int {
+				return compare(tmp[a].K, tmp[b].K)
+			}
+		}
+		t.valindex = readValueIndex(r, len(tmp), at)
+	}
 }
-
-func (t *CounterInt) Write(w custom.Interface) {
+func (t *Counter[K, N]) Write(w custom.Interface) {
+	write := orderedWriter[K]()
+	writeval := mustCodec[N]().WriteValue
 	w.WriteUint64Variable(uint64(len(t.key)))
 	for _, v := range t.key {
-		w.WriteUint64Variable(uint64(v.K))
-		w.WriteUint64Variable(uint64(v.V))
+		writeval(w, v.K)
+		write(w, v.V)
 	}
 }
 
-func (t *CounterInt) Read(r *custom.Reader) {
-	var k int
-	var v int
+func (t *Counter[K, N]) Read(r *custom.Reader) {
+	read := orderedReader[K]()
+	readval := mustCodec[N]().ReadValue
+	var k N
+	var v K
 	l := int(r.ReadUint64Variable())
-	tmp := make([]sortIntInt.KeyVal, l)
+	tmp := make([]sortOrdered.KeyVal[K, N], l)
 	for i:=0; i<l; i++ {
-		k = int(r.ReadUint64Variable())
-		v = int(r.ReadUint64Variable())
-		tmp[i] = sortIntInt.KeyVal{k, v}
+		k = readval(r)
+		v = read(r)
+		tmp[i] = sortOrdered.KeyVal[K, N]{k, v}
 	}
 	t.key = tmp
 }
+
+// ---------- KeyValBytesOf ----------
+
+/*
+	KeyValBytesOf is KeyValBytes for any value type V, it is the KeyBytes and a slice of values pattern (see Example 3) done for you.
+	The values are kept in a slice in the same order as the KeyBytes indexes, and Build reorders them along with the keys.
+*/
+
+// Add this to any struct to make it binary searchable.
+type KeyValBytesOf[V any] struct {
+ child KeyBytes
+ val []V
+ cursor int
+ byvalue valueOrder // used by ResetByValue & NextByValue
+ unsorted bool // keys have been added with AddUnsorted since the last Build
+ compare func(V, V) int // the order of the values, set by NewKeyValBytesOfFunc or DefaultCompare
+}
+
+// NewKeyValBytesOfFunc returns a KeyValBytesOf whose values are ordered by compare, for TopK & NextByValue when V is not an integer, float or string type.
+func NewKeyValBytesOfFunc[V any](compare func(V, V) int) *KeyValBytesOf[V] {
+	return &KeyValBytesOf[V]{compare: compare}
+}
+
+func (t *KeyValBytesOf[V]) Len() int {
+	return t.child.Len()
+}
+
+// Find returns the value based on the key.
+func (t *KeyValBytesOf[V]) Find(thekey []byte) (V, bool) {
+	if i, ok := t.child.Find(thekey); ok {
+		return t.val[i], true
+	}
+	return *new(V), false
+}
+
+// Modifies the value of the key by running it through the provided function.
+func (t *KeyValBytesOf[V]) Update(thekey []byte, fn func(V) V) bool {
+	if i, ok := t.child.Find(thekey); ok {
+		t.val[i] = fn(t.val[i])
+		return true
+	}
+	return false
+}
+
+// Modifies all values by running each through the provided function.
+func (t *KeyValBytesOf[V]) UpdateAll(fn func(V) V) {
+	for i, v := range t.val {
+		t.val[i] = fn(v)
+	}
+}
+
+// Add adds this key and value in the correct position, or replaces the value if the key already exists.
+func (t *KeyValBytesOf[V]) Add(thekey []byte, theval V) bool {
+	i, ok := t.child.Add(thekey)
+	if ok {
+		t.val[i] = theval
+		return true
+	}
+	cur := t.val
+	lc := len(cur)
+	if lc == cap(cur) {
+		tmp := make([]V, lc + 1, (lc * 2) + 1)
+		copy(tmp, cur[0:i])
+		copy(tmp[i+1:], cur[i:])
+		cur = tmp
+	} else {
+		cur = cur[0:lc+1]
+		copy(cur[i+1:], cur[i:])
+	}
+	cur[i] = theval
+	t.val = cur
+	return false
+}
+
+// AddUnsorted adds this key to the end of the index for later building with Build.
+func (t *KeyValBytesOf[V]) AddUnsorted(thekey []byte, theval V) error {
+	if err := t.child.AddUnsorted(thekey); err != nil {
+		return err
+	}
+	t.val = append(t.val, theval)
+	t.unsorted = true
+	return nil
+}
+
+// Build sorts the keys and moves the values with them. It is only required after AddUnsorted, otherwise it does nothing.
+func (t *KeyValBytesOf[V]) Build() error {
+	if !t.unsorted {
+		return nil
+	}
+	imap, err := t.child.Build()
+	if err != nil {
+		return err
+	}
+	t.unsorted = false
+	newval := make([]V, len(imap))
+	for i, old := range imap {
+		newval[i] = t.val[old]
+	}
+	t.val = newval
+	return nil
+}
+
+func (t *KeyValBytesOf[V]) Optimize() {
+	t.child.Optimize()
+	temp := make([]V, len(t.val))
+	copy(temp, t.val)
+	t.val = temp
+}
+
+func (t *KeyValBytesOf[V]) Reset() bool {
+	t.cursor = 0
+	return t.child.Reset()
+}
+
+func (t *KeyValBytesOf[V]) Next() ([]byte, V, bool) {
+	a, eof := t.child.Next()
+	v := t.val[t.cursor]
+	if t.cursor++; eof {
+		t.cursor = 0
+	}
+	return a, v, eof
+}
+
+func (t *KeyValBytesOf[V]) Keys() [][]byte {
+	return t.child.Keys()
+}
+
+// Values returns the values in the same order as Keys. This is not a copy.
+func (t *KeyValBytesOf[V]) Values() []V {
+	return t.val
+}
+
+func (t *KeyValBytesOf[V]) Write(w custom.Interface) {
+	t.write(w, mustCodec[V]().WriteValue)
+}
+
+// WriteWith is Write for values that Write doesn't support, the codec writes each value.
+func (t *KeyValBytesOf[V]) WriteWith(w custom.Interface, codec ValueCodec[V]) {
+	t.write(w, codec.WriteValue)
+}
+
+func (t *KeyValBytesOf[V]) write(w custom.Interface, writeval func(custom.Interface, V)) {
+	t.child.Write(w)
+	for _, v := range t.val {
+		writeval(w, v)
+	}
+}
+
+func (t *KeyValBytesOf[V]) Read(r *custom.Reader) {
+	t.read(r, mustCodec[V]().ReadValue)
+}
+
+// ReadWith is Read for values that Read doesn't support, the codec must match the one given to WriteWith.
+func (t *KeyValBytesOf[V]) ReadWith(r *custom.Reader, codec ValueCodec[V]) {
+	t.read(r, codec.ReadValue)
+}
+
+func (t *KeyValBytesOf[V]) read(r *custom.Reader, readval func(*custom.Reader) V) {
+	t.child.Read(r)
+	l := t.child.Len()
+	tmp := make([]V, l)
+	for i:=0; i<l; i++ {
+		tmp[i] = readval(r)
+	}
+	t.val = tmp
+}
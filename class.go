@@ -0,0 +1,271 @@
+package binsearch
+
+/*
+	The bytes types keep their keys in 64 length classes (limit8[0] to limit64[7]) and the overflow.
+	classLen and classEntry go through them one class at a time, on is the class (tier * 8 + run) or 64 for the overflow.
+	classWords returns a key as it is stored, so that it can be written out with putClassKey without allocating.
+*/
+
+// ---------- KeyBytes ----------
+
+// classLen returns the number of keys in length class on.
+func (t *KeyBytes) classLen(on int) int {
+	if on == 64 {
+		return len(t.overflow)
+	}
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			return len(t.limit8[run])
+		case 1:
+			return len(t.limit16[run])
+		case 2:
+			return len(t.limit24[run])
+		case 3:
+			return len(t.limit32[run])
+		case 4:
+			return len(t.limit40[run])
+		case 5:
+			return len(t.limit48[run])
+		case 6:
+			return len(t.limit56[run])
+		case 7:
+			return len(t.limit64[run])
+	}
+	return 0
+}
+
+// classEntry returns the key and value at position at in length class on.
+func (t *KeyBytes) classEntry(on, at int) ([]byte, int) {
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			return reverse8(t.limit8[run][at], run), 0
+		case 1:
+			return reverse16(t.limit16[run][at], run), 0
+		case 2:
+			return reverse24(t.limit24[run][at], run), 0
+		case 3:
+			return reverse32(t.limit32[run][at], run), 0
+		case 4:
+			return reverse40(t.limit40[run][at], run), 0
+		case 5:
+			return reverse48(t.limit48[run][at], run), 0
+		case 6:
+			return reverse56(t.limit56[run][at], run), 0
+		case 7:
+			return reverse64(t.limit64[run][at], run), 0
+	}
+	return t.overflow[at], 0
+}
+
+// classWords returns the words of the key at position at in length class on, only for on < 64.
+func (t *KeyBytes) classWords(on, at int) [8]uint64 {
+	var words [8]uint64
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			words[0] = t.limit8[run][at]
+		case 1:
+			copy(words[:], t.limit16[run][at][0:2])
+		case 2:
+			copy(words[:], t.limit24[run][at][0:3])
+		case 3:
+			copy(words[:], t.limit32[run][at][0:4])
+		case 4:
+			copy(words[:], t.limit40[run][at][0:5])
+		case 5:
+			copy(words[:], t.limit48[run][at][0:6])
+		case 6:
+			copy(words[:], t.limit56[run][at][0:7])
+		default:
+			copy(words[:], t.limit64[run][at][0:8])
+	}
+	return words
+}
+
+// ---------- KeyValBytes ----------
+
+// classLen returns the number of keys in length class on.
+func (t *KeyValBytes) classLen(on int) int {
+	if on == 64 {
+		return len(t.overflow)
+	}
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			return len(t.limit8[run])
+		case 1:
+			return len(t.limit16[run])
+		case 2:
+			return len(t.limit24[run])
+		case 3:
+			return len(t.limit32[run])
+		case 4:
+			return len(t.limit40[run])
+		case 5:
+			return len(t.limit48[run])
+		case 6:
+			return len(t.limit56[run])
+		case 7:
+			return len(t.limit64[run])
+	}
+	return 0
+}
+
+// classEntry returns the key and value at position at in length class on.
+func (t *KeyValBytes) classEntry(on, at int) ([]byte, int) {
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			v := t.limit8[run][at]
+			return reverse8b(v, run), int(v[1])
+		case 1:
+			v := t.limit16[run][at]
+			return reverse16b(v, run), int(v[2])
+		case 2:
+			v := t.limit24[run][at]
+			return reverse24b(v, run), int(v[3])
+		case 3:
+			v := t.limit32[run][at]
+			return reverse32b(v, run), int(v[4])
+		case 4:
+			v := t.limit40[run][at]
+			return reverse40b(v, run), int(v[5])
+		case 5:
+			v := t.limit48[run][at]
+			return reverse48b(v, run), int(v[6])
+		case 6:
+			v := t.limit56[run][at]
+			return reverse56b(v, run), int(v[7])
+		case 7:
+			v := t.limit64[run][at]
+			return reverse64b(v, run), int(v[8])
+	}
+	v := t.overflow[at]
+	return v.V, v.K
+}
+
+// classWords returns the words of the key at position at in length class on, only for on < 64.
+func (t *KeyValBytes) classWords(on, at int) [8]uint64 {
+	var words [8]uint64
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			words[0] = t.limit8[run][at][0]
+		case 1:
+			copy(words[:], t.limit16[run][at][0:2])
+		case 2:
+			copy(words[:], t.limit24[run][at][0:3])
+		case 3:
+			copy(words[:], t.limit32[run][at][0:4])
+		case 4:
+			copy(words[:], t.limit40[run][at][0:5])
+		case 5:
+			copy(words[:], t.limit48[run][at][0:6])
+		case 6:
+			copy(words[:], t.limit56[run][at][0:7])
+		default:
+			copy(words[:], t.limit64[run][at][0:8])
+	}
+	return words
+}
+
+// ---------- CounterBytes ----------
+
+// classLen returns the number of keys in length class on.
+func (t *CounterBytes) classLen(on int) int {
+	if on == 64 {
+		return len(t.overflow)
+	}
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			return len(t.limit8[run])
+		case 1:
+			return len(t.limit16[run])
+		case 2:
+			return len(t.limit24[run])
+		case 3:
+			return len(t.limit32[run])
+		case 4:
+			return len(t.limit40[run])
+		case 5:
+			return len(t.limit48[run])
+		case 6:
+			return len(t.limit56[run])
+		case 7:
+			return len(t.limit64[run])
+	}
+	return 0
+}
+
+// classEntry returns the key and value at position at in length class on.
+func (t *CounterBytes) classEntry(on, at int) ([]byte, int) {
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			v := t.limit8[run][at]
+			return reverse8b(v, run), int(v[1])
+		case 1:
+			v := t.limit16[run][at]
+			return reverse16b(v, run), int(v[2])
+		case 2:
+			v := t.limit24[run][at]
+			return reverse24b(v, run), int(v[3])
+		case 3:
+			v := t.limit32[run][at]
+			return reverse32b(v, run), int(v[4])
+		case 4:
+			v := t.limit40[run][at]
+			return reverse40b(v, run), int(v[5])
+		case 5:
+			v := t.limit48[run][at]
+			return reverse48b(v, run), int(v[6])
+		case 6:
+			v := t.limit56[run][at]
+			return reverse56b(v, run), int(v[7])
+		case 7:
+			v := t.limit64[run][at]
+			return reverse64b(v, run), int(v[8])
+	}
+	v := t.overflow[at]
+	return v.V, v.K
+}
+
+// classWords returns the words of the key at position at in length class on, only for on < 64.
+func (t *CounterBytes) classWords(on, at int) [8]uint64 {
+	var words [8]uint64
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			words[0] = t.limit8[run][at][0]
+		case 1:
+			copy(words[:], t.limit16[run][at][0:2])
+		case 2:
+			copy(words[:], t.limit24[run][at][0:3])
+		case 3:
+			copy(words[:], t.limit32[run][at][0:4])
+		case 4:
+			copy(words[:], t.limit40[run][at][0:5])
+		case 5:
+			copy(words[:], t.limit48[run][at][0:6])
+		case 6:
+			copy(words[:], t.limit56[run][at][0:7])
+		default:
+			copy(words[:], t.limit64[run][at][0:8])
+	}
+	return words
+}
+
+// putClassKey writes the key with these words in length class on to dst, it is classEntry without allocating.
+func putClassKey(dst *[64]byte, words *[8]uint64, on int) []byte {
+	if on == 0 && words[0] == 0 {
+		return dst[0:0] // the empty key
+	}
+	n := on >> 3 // the number of full words
+	for i:=0; i<n; i++ {
+		uint642bytes(dst[i * 8:], words[i])
+	}
+	return dst[0 : n * 8 + uint642bytesend(dst[n * 8:], words[n], on & 7)]
+}
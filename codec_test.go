@@ -0,0 +1,160 @@
+package binsearch
+
+import (
+ "bytes"
+ "strings"
+ "testing"
+ "github.com/AlasdairF/Custom"
+)
+
+type testPoint struct {
+ X, Y int
+}
+
+type testPointCodec struct{}
+
+func (testPointCodec) WriteValue(w custom.Interface, v testPoint) {
+	w.WriteUint64Variable(uint64(v.X))
+	w.WriteUint64Variable(uint64(v.Y))
+}
+
+func (testPointCodec) ReadValue(r *custom.Reader) testPoint {
+	x := int(r.ReadUint64Variable())
+	return testPoint{x, int(r.ReadUint64Variable())}
+}
+
+type testName string
+
+func TestDefaultCodec(t *testing.T) {
+	if _, err := DefaultCodec[testPoint](); err != ErrNoCodec {
+		t.Fatal(err)
+	}
+	if _, err := DefaultCodec[[]int](); err != ErrNoCodec {
+		t.Fatal(err)
+	}
+	for _, err := range []error{codecErr[int](), codecErr[testID](), codecErr[int8](), codecErr[float32](), codecErr[bool](), codecErr[testName](), codecErr[[]byte]()} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	kv := new(KeyVal[uint32, testPoint])
+	kv.Add(1, testPoint{1, 2})
+	var buf bytes.Buffer
+	w := custom.NewWriter(&buf)
+	func() {
+		defer func() {
+			if recover() != ErrNoCodec {
+				t.Fatal(`Write did not panic with ErrNoCodec`)
+			}
+		}()
+		kv.Write(w)
+	}()
+	w.Close()
+	if buf.Len() != 0 {
+		t.Fatal(`Write wrote before it panicked`, buf.Len())
+	}
+}
+
+func codecErr[V any]() error {
+	_, err := DefaultCodec[V]()
+	return err
+}
+
+func TestKeyValCodec(t *testing.T) {
+	kv := new(KeyVal[uint32, testPoint])
+	for i:=0; i<100; i++ {
+		kv.AddUnsorted(uint32(i * 7 % 100), testPoint{i, -i})
+	}
+	kv.Build()
+	kv2 := new(KeyVal[uint32, testPoint])
+	roundTrip(t, func(w custom.Interface) { kv.WriteWith(w, testPointCodec{}) }, func(r *custom.Reader) { kv2.ReadWith(r, testPointCodec{}) })
+	for i:=0; i<100; i++ {
+		if p, ok := kv2.Find(uint32(i * 7 % 100)); !ok || p.X != i || p.Y != -i {
+			t.Fatal(i, p, ok)
+		}
+	}
+	ks := new(KeyVal[string, testName])
+	ks.Add(`b`, `bee`)
+	ks.Add(`a`, `ay`)
+	ks.Add(`b`, `bea`)
+	ks.Add(``, ``)
+	ks2 := new(KeyVal[string, testName])
+	roundTrip(t, ks.Write, ks2.Read)
+	if v, ok := ks2.Find(`b`); !ok || v != `bea` || ks2.Len() != 3 {
+		t.Fatal(v, ok)
+	}
+	kb := new(KeyVal[int, []byte])
+	kb.Add(-1, []byte{0, 1})
+	kb.Add(2, nil)
+	kb2 := new(KeyVal[int, []byte])
+	roundTrip(t, kb.Write, kb2.Read)
+	if v, ok := kb2.Find(-1); !ok || !bytes.Equal(v, []byte{0, 1}) {
+		t.Fatal(v, ok)
+	}
+	// KeyValUint64 keeps writing its int values as they always were.
+	ki := new(KeyValUint64)
+	ki.Add(5, -3)
+	ki2 := new(KeyValUint64)
+	roundTrip(t, ki.Write, ki2.Read)
+	if v, _ := ki2.Find(5); v != -3 {
+		t.Fatal(v)
+	}
+}
+
+func TestKeyValBytesOf(t *testing.T) {
+	b := new(KeyValBytesOf[[]string])
+	words := []string{`zeta`, `alpha`, strings.Repeat(`long`, 30), `m`, ``}
+	for i, x := range words {
+		if err := b.AddUnsorted([]byte(x), []string{x, strings.Repeat(`!`, i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.Build(); err != nil {
+		t.Fatal(err)
+	}
+	for i, x := range words {
+		if v, ok := b.Find([]byte(x)); !ok || v[0] != x || len(v[1]) != i {
+			t.Fatal(x, v, ok)
+		}
+	}
+	n := 0
+	if b.Reset() {
+		for {
+			k, v, eof := b.Next()
+			if string(k) != v[0] {
+				t.Fatalf(`Next %q = %q`, k, v)
+			}
+			n++
+			if eof {
+				break
+			}
+		}
+	}
+	if n != len(words) {
+		t.Fatal(n)
+	}
+	b.Add([]byte(`beta`), []string{`beta`, ``})
+	keys := b.Keys()
+	// Build only does something after AddUnsorted.
+	if err := b.Build(); err != nil {
+		t.Fatal(err)
+	}
+	for i, k := range b.Keys() {
+		if !bytes.Equal(k, keys[i]) || b.Values()[i][0] != string(k) {
+			t.Fatalf(`Build after Add moved %q`, k)
+		}
+	}
+	for _, x := range append(words, `beta`) {
+		if v, ok := b.Find([]byte(x)); !ok || v[0] != x {
+			t.Fatal(x, v, ok)
+		}
+	}
+	f := new(KeyValBytesOf[float64])
+	f.Add([]byte(`pi`), 3.14)
+	f.Add([]byte(`e`), 2.71)
+	f2 := new(KeyValBytesOf[float64])
+	roundTrip(t, f.Write, f2.Read)
+	if v, ok := f2.Find([]byte(`pi`)); !ok || v != 3.14 {
+		t.Fatal(v, ok)
+	}
+}
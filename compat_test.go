@@ -0,0 +1,220 @@
+package binsearch
+
+import (
+ "github.com/AlasdairF/Sort/IntUint64"
+ "github.com/AlasdairF/Sort/IntUint32"
+ "github.com/AlasdairF/Sort/IntUint16"
+ "github.com/AlasdairF/Sort/IntUint8"
+ "github.com/AlasdairF/Sort/IntInt"
+ "github.com/AlasdairF/Custom"
+)
+
+// The signatures of the types from before the generic types, so that code written for them keeps compiling.
+var (
+	_ func(*KeyUint64) int = (*KeyUint64).Len
+	_ func(*KeyUint64, uint64) (int, bool) = (*KeyUint64).Find
+	_ func(*KeyUint64, uint64) (int, bool) = (*KeyUint64).Add
+	_ func(*KeyUint64, uint64) = (*KeyUint64).AddUnsorted
+	_ func(*KeyUint64, uint64, int) = (*KeyUint64).AddAt
+	_ func(*KeyUint64) []int = (*KeyUint64).Build
+	_ func(*KeyUint64) = (*KeyUint64).Optimize
+	_ func(*KeyUint64) bool = (*KeyUint64).Reset
+	_ func(*KeyUint64) (uint64, bool) = (*KeyUint64).Next
+	_ func(*KeyUint64) []uint64 = (*KeyUint64).Keys
+	_ func(*KeyValUint64) int = (*KeyValUint64).Len
+	_ func(*KeyValUint64, uint64) (int, bool) = (*KeyValUint64).Find
+	_ func(*KeyValUint64, uint64, func(int) int) bool = (*KeyValUint64).Update
+	_ func(*KeyValUint64, func(int) int) = (*KeyValUint64).UpdateAll
+	_ func(*KeyValUint64, uint64, int) bool = (*KeyValUint64).Add
+	_ func(*KeyValUint64, uint64, int) = (*KeyValUint64).AddUnsorted
+	_ func(*KeyValUint64) = (*KeyValUint64).Build
+	_ func(*KeyValUint64) = (*KeyValUint64).Optimize
+	_ func(*KeyValUint64) bool = (*KeyValUint64).Reset
+	_ func(*KeyValUint64) (uint64, int, bool) = (*KeyValUint64).Next
+	_ func(*KeyValUint64) []uint64 = (*KeyValUint64).Keys
+	_ func([]sortIntUint64.KeyVal) *CounterUint64 = NewCounterUint64
+	_ func(*CounterUint64) []sortIntUint64.KeyVal = (*CounterUint64).RawKey
+	_ func(*CounterUint64) *KeyUint64 = (*CounterUint64).KeyUint64
+	_ func(*CounterUint64) *KeyValUint64 = (*CounterUint64).KeyValUint64
+	_ func(*CounterUint64) int = (*CounterUint64).Len
+	_ func(*CounterUint64, uint64) (int, bool) = (*CounterUint64).Find
+	_ func(*CounterUint64, uint64, func(int) int) bool = (*CounterUint64).Update
+	_ func(*CounterUint64, func(int) int) = (*CounterUint64).UpdateAll
+	_ func(*CounterUint64, uint64, int) = (*CounterUint64).Add
+	_ func(*CounterUint64) = (*CounterUint64).Build
+	_ func(*CounterUint64) = (*CounterUint64).Optimize
+	_ func(*CounterUint64) bool = (*CounterUint64).Reset
+	_ func(*CounterUint64) (uint64, int, bool) = (*CounterUint64).Next
+	_ func(*CounterUint64) []uint64 = (*CounterUint64).Keys
+	_ func(*KeyUint64, custom.Interface) = (*KeyUint64).Write
+	_ func(*KeyUint64, *custom.Reader) = (*KeyUint64).Read
+	_ func(*KeyValUint64, custom.Interface) = (*KeyValUint64).Write
+	_ func(*KeyValUint64, *custom.Reader) = (*KeyValUint64).Read
+	_ func(*CounterUint64, custom.Interface) = (*CounterUint64).Write
+	_ func(*CounterUint64, *custom.Reader) = (*CounterUint64).Read
+	_ func(*KeyUint32) int = (*KeyUint32).Len
+	_ func(int) *KeyUint32 = NewKeyUint32
+	_ func(*KeyUint32, uint32) (int, bool) = (*KeyUint32).Find
+	_ func(*KeyUint32, uint32) (int, bool) = (*KeyUint32).Add
+	_ func(*KeyUint32, uint32) = (*KeyUint32).AddUnsorted
+	_ func(*KeyUint32, uint32, int) = (*KeyUint32).AddAt
+	_ func(*KeyUint32) []int = (*KeyUint32).Build
+	_ func(*KeyUint32) = (*KeyUint32).Optimize
+	_ func(*KeyUint32) bool = (*KeyUint32).Reset
+	_ func(*KeyUint32) (uint32, bool) = (*KeyUint32).Next
+	_ func(*KeyUint32) []uint32 = (*KeyUint32).Keys
+	_ func(*KeyValUint32) int = (*KeyValUint32).Len
+	_ func(*KeyValUint32, uint32) (int, bool) = (*KeyValUint32).Find
+	_ func(*KeyValUint32, uint32, func(int) int) bool = (*KeyValUint32).Update
+	_ func(*KeyValUint32, func(int) int) = (*KeyValUint32).UpdateAll
+	_ func(*KeyValUint32, uint32, int) bool = (*KeyValUint32).Add
+	_ func(*KeyValUint32, uint32, int) = (*KeyValUint32).AddUnsorted
+	_ func(*KeyValUint32) = (*KeyValUint32).Build
+	_ func(*KeyValUint32) = (*KeyValUint32).Optimize
+	_ func(*KeyValUint32) bool = (*KeyValUint32).Reset
+	_ func(*KeyValUint32) (uint32, int, bool) = (*KeyValUint32).Next
+	_ func(*KeyValUint32) []uint32 = (*KeyValUint32).Keys
+	_ func([]sortIntUint32.KeyVal) *CounterUint32 = NewCounterUint32
+	_ func(*CounterUint32) []sortIntUint32.KeyVal = (*CounterUint32).RawKey
+	_ func(*CounterUint32) *KeyUint32 = (*CounterUint32).KeyUint32
+	_ func(*CounterUint32) *KeyValUint32 = (*CounterUint32).KeyValUint32
+	_ func(*CounterUint32) int = (*CounterUint32).Len
+	_ func(*CounterUint32, uint32) (int, bool) = (*CounterUint32).Find
+	_ func(*CounterUint32, uint32, func(int) int) bool = (*CounterUint32).Update
+	_ func(*CounterUint32, func(int) int) = (*CounterUint32).UpdateAll
+	_ func(*CounterUint32, uint32, int) = (*CounterUint32).Add
+	_ func(*CounterUint32) = (*CounterUint32).Build
+	_ func(*CounterUint32) = (*CounterUint32).Optimize
+	_ func(*CounterUint32) bool = (*CounterUint32).Reset
+	_ func(*CounterUint32) (uint32, int, bool) = (*CounterUint32).Next
+	_ func(*CounterUint32) []uint32 = (*CounterUint32).Keys
+	_ func(*KeyUint32, custom.Interface) = (*KeyUint32).Write
+	_ func(*KeyUint32, *custom.Reader) = (*KeyUint32).Read
+	_ func(*KeyValUint32, custom.Interface) = (*KeyValUint32).Write
+	_ func(*KeyValUint32, *custom.Reader) = (*KeyValUint32).Read
+	_ func(*CounterUint32, custom.Interface) = (*CounterUint32).Write
+	_ func(*CounterUint32, *custom.Reader) = (*CounterUint32).Read
+	_ func(*KeyUint16) int = (*KeyUint16).Len
+	_ func(*KeyUint16, uint16) (int, bool) = (*KeyUint16).Find
+	_ func(*KeyUint16, uint16) (int, bool) = (*KeyUint16).Add
+	_ func(*KeyUint16, uint16) = (*KeyUint16).AddUnsorted
+	_ func(*KeyUint16, uint16, int) = (*KeyUint16).AddAt
+	_ func(*KeyUint16) []int = (*KeyUint16).Build
+	_ func(*KeyUint16) = (*KeyUint16).Optimize
+	_ func(*KeyUint16) bool = (*KeyUint16).Reset
+	_ func(*KeyUint16) (uint16, bool) = (*KeyUint16).Next
+	_ func(*KeyUint16) []uint16 = (*KeyUint16).Keys
+	_ func(*KeyValUint16) int = (*KeyValUint16).Len
+	_ func(*KeyValUint16, uint16) (int, bool) = (*KeyValUint16).Find
+	_ func(*KeyValUint16, uint16, func(int) int) bool = (*KeyValUint16).Update
+	_ func(*KeyValUint16, func(int) int) = (*KeyValUint16).UpdateAll
+	_ func(*KeyValUint16, uint16, int) bool = (*KeyValUint16).Add
+	_ func(*KeyValUint16, uint16, int) = (*KeyValUint16).AddUnsorted
+	_ func(*KeyValUint16) = (*KeyValUint16).Build
+	_ func(*KeyValUint16) = (*KeyValUint16).Optimize
+	_ func(*KeyValUint16) bool = (*KeyValUint16).Reset
+	_ func(*KeyValUint16) (uint16, int, bool) = (*KeyValUint16).Next
+	_ func(*KeyValUint16) []uint16 = (*KeyValUint16).Keys
+	_ func([]sortIntUint16.KeyVal) *CounterUint16 = NewCounterUint16
+	_ func(*CounterUint16) []sortIntUint16.KeyVal = (*CounterUint16).RawKey
+	_ func(*CounterUint16) *KeyUint16 = (*CounterUint16).KeyUint16
+	_ func(*CounterUint16) *KeyValUint16 = (*CounterUint16).KeyValUint16
+	_ func(*CounterUint16) int = (*CounterUint16).Len
+	_ func(*CounterUint16, uint16) (int, bool) = (*CounterUint16).Find
+	_ func(*CounterUint16, uint16, func(int) int) bool = (*CounterUint16).Update
+	_ func(*CounterUint16, func(int) int) = (*CounterUint16).UpdateAll
+	_ func(*CounterUint16, uint16, int) = (*CounterUint16).Add
+	_ func(*CounterUint16) = (*CounterUint16).Build
+	_ func(*CounterUint16) = (*CounterUint16).Optimize
+	_ func(*CounterUint16) bool = (*CounterUint16).Reset
+	_ func(*CounterUint16) (uint16, int, bool) = (*CounterUint16).Next
+	_ func(*CounterUint16) []uint16 = (*CounterUint16).Keys
+	_ func(*KeyUint16, custom.Interface) = (*KeyUint16).Write
+	_ func(*KeyUint16, *custom.Reader) = (*KeyUint16).Read
+	_ func(*KeyValUint16, custom.Interface) = (*KeyValUint16).Write
+	_ func(*KeyValUint16, *custom.Reader) = (*KeyValUint16).Read
+	_ func(*CounterUint16, custom.Interface) = (*CounterUint16).Write
+	_ func(*CounterUint16, *custom.Reader) = (*CounterUint16).Read
+	_ func(*KeyUint8) int = (*KeyUint8).Len
+	_ func(*KeyUint8, uint8) (int, bool) = (*KeyUint8).Find
+	_ func(*KeyUint8, uint8) (int, bool) = (*KeyUint8).Add
+	_ func(*KeyUint8, uint8) = (*KeyUint8).AddUnsorted
+	_ func(*KeyUint8, uint8, int) = (*KeyUint8).AddAt
+	_ func(*KeyUint8) []int = (*KeyUint8).Build
+	_ func(*KeyUint8) = (*KeyUint8).Optimize
+	_ func(*KeyUint8) bool = (*KeyUint8).Reset
+	_ func(*KeyUint8) (uint8, bool) = (*KeyUint8).Next
+	_ func(*KeyUint8) []uint8 = (*KeyUint8).Keys
+	_ func(*KeyValUint8) int = (*KeyValUint8).Len
+	_ func(*KeyValUint8, uint8) (int, bool) = (*KeyValUint8).Find
+	_ func(*KeyValUint8, uint8, func(int) int) bool = (*KeyValUint8).Update
+	_ func(*KeyValUint8, func(int) int) = (*KeyValUint8).UpdateAll
+	_ func(*KeyValUint8, uint8, int) bool = (*KeyValUint8).Add
+	_ func(*KeyValUint8, uint8, int) = (*KeyValUint8).AddUnsorted
+	_ func(*KeyValUint8) = (*KeyValUint8).Build
+	_ func(*KeyValUint8) = (*KeyValUint8).Optimize
+	_ func(*KeyValUint8) bool = (*KeyValUint8).Reset
+	_ func(*KeyValUint8) (uint8, int, bool) = (*KeyValUint8).Next
+	_ func(*KeyValUint8) []uint8 = (*KeyValUint8).Keys
+	_ func([]sortIntUint8.KeyVal) *CounterUint8 = NewCounterUint8
+	_ func(*CounterUint8) []sortIntUint8.KeyVal = (*CounterUint8).RawKey
+	_ func(*CounterUint8) *KeyUint8 = (*CounterUint8).KeyUint8
+	_ func(*CounterUint8) *KeyValUint8 = (*CounterUint8).KeyValUint8
+	_ func(*CounterUint8) int = (*CounterUint8).Len
+	_ func(*CounterUint8, uint8) (int, bool) = (*CounterUint8).Find
+	_ func(*CounterUint8, uint8, func(int) int) bool = (*CounterUint8).Update
+	_ func(*CounterUint8, func(int) int) = (*CounterUint8).UpdateAll
+	_ func(*CounterUint8, uint8, int) = (*CounterUint8).Add
+	_ func(*CounterUint8) = (*CounterUint8).Build
+	_ func(*CounterUint8) = (*CounterUint8).Optimize
+	_ func(*CounterUint8) bool = (*CounterUint8).Reset
+	_ func(*CounterUint8) (uint8, int, bool) = (*CounterUint8).Next
+	_ func(*CounterUint8) []uint8 = (*CounterUint8).Keys
+	_ func(*KeyUint8, custom.Interface) = (*KeyUint8).Write
+	_ func(*KeyUint8, *custom.Reader) = (*KeyUint8).Read
+	_ func(*KeyValUint8, custom.Interface) = (*KeyValUint8).Write
+	_ func(*KeyValUint8, *custom.Reader) = (*KeyValUint8).Read
+	_ func(*CounterUint8, custom.Interface) = (*CounterUint8).Write
+	_ func(*CounterUint8, *custom.Reader) = (*CounterUint8).Read
+	_ func(*KeyInt) int = (*KeyInt).Len
+	_ func(*KeyInt, int) (int, bool) = (*KeyInt).Find
+	_ func(*KeyInt, int) (int, bool) = (*KeyInt).Add
+	_ func(*KeyInt, int) = (*KeyInt).AddUnsorted
+	_ func(*KeyInt, int, int) = (*KeyInt).AddAt
+	_ func(*KeyInt) []int = (*KeyInt).Build
+	_ func(*KeyInt) = (*KeyInt).Optimize
+	_ func(*KeyInt) bool = (*KeyInt).Reset
+	_ func(*KeyInt) (int, bool) = (*KeyInt).Next
+	_ func(*KeyInt) []int = (*KeyInt).Keys
+	_ func(*KeyValInt) int = (*KeyValInt).Len
+	_ func(*KeyValInt, int) (int, bool) = (*KeyValInt).Find
+	_ func(*KeyValInt, int, func(int) int) bool = (*KeyValInt).Update
+	_ func(*KeyValInt, func(int) int) = (*KeyValInt).UpdateAll
+	_ func(*KeyValInt, int, int) bool = (*KeyValInt).Add
+	_ func(*KeyValInt, int, int) = (*KeyValInt).AddUnsorted
+	_ func(*KeyValInt) = (*KeyValInt).Build
+	_ func(*KeyValInt) = (*KeyValInt).Optimize
+	_ func(*KeyValInt) bool = (*KeyValInt).Reset
+	_ func(*KeyValInt) (int, int, bool) = (*KeyValInt).Next
+	_ func(*KeyValInt) []int = (*KeyValInt).Keys
+	_ func([]sortIntInt.KeyVal) *CounterInt = NewCounterInt
+	_ func(*CounterInt) []sortIntInt.KeyVal = (*CounterInt).RawKey
+	_ func(*CounterInt) *KeyInt = (*CounterInt).KeyInt
+	_ func(*CounterInt) *KeyValInt = (*CounterInt).KeyValInt
+	_ func(*CounterInt) int = (*CounterInt).Len
+	_ func(*CounterInt, int) (int, bool) = (*CounterInt).Find
+	_ func(*CounterInt, int, func(int) int) bool = (*CounterInt).Update
+	_ func(*CounterInt, func(int) int) = (*CounterInt).UpdateAll
+	_ func(*CounterInt, int, int) = (*CounterInt).Add
+	_ func(*CounterInt) = (*CounterInt).Build
+	_ func(*CounterInt) = (*CounterInt).Optimize
+	_ func(*CounterInt) bool = (*CounterInt).Reset
+	_ func(*CounterInt) (int, int, bool) = (*CounterInt).Next
+	_ func(*CounterInt) []int = (*CounterInt).Keys
+	_ func(*KeyInt, custom.Interface) = (*KeyInt).Write
+	_ func(*KeyInt, *custom.Reader) = (*KeyInt).Read
+	_ func(*KeyValInt, custom.Interface) = (*KeyValInt).Write
+	_ func(*KeyValInt, *custom.Reader) = (*KeyValInt).Read
+	_ func(*CounterInt, custom.Interface) = (*CounterInt).Write
+	_ func(*CounterInt, *custom.Reader) = (*CounterInt).Read
+)
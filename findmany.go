@@ -0,0 +1,650 @@
+package binsearch
+
+import (
+ "bytes"
+ "math"
+ "slices"
+)
+
+/*
+	FindMany looks up many keys at once into slices given by the caller, so it doesn't allocate.
+	If the keys are sorted each search starts where the last one ended and gallops forward (1, 2, 4, 8... keys ahead) before the binary search,
+	so a run of keys that are close together costs a few comparisons each instead of a full binary search.
+	For the bytes types there is one such position for each length class, since keys of the same length are in order within their class,
+	and a key gallops from it whenever it is greater than the key before it, so the keys only need to be sorted within each class.
+	The runes types encode each key into the same buffer on the stack, and the string types use the bytes of the strings as they are.
+*/
+
+// search returns the first position from lo to hi where compare(at) <= 0, or hi if there isn't one.
+func search(lo, hi int, compare func(int) int) int {
+	var at int
+	for lo < hi {
+		at = lo + ((hi - lo) / 2)
+		if compare(at) > 0 {
+			lo = at + 1
+		} else {
+			hi = at
+		}
+	}
+	return lo
+}
+
+// gallop is search from lo to l, but it checks close to lo first.
+func gallop(lo, l int, compare func(int) int) int {
+	hi, step := lo, 1
+	for hi < l && compare(hi) > 0 {
+		lo = hi + 1
+		hi += step
+		step <<= 1
+	}
+	if hi > l {
+		hi = l
+	}
+	return search(lo, hi, compare)
+}
+
+// findMany looks up n probes in l sorted keys, compare(p, at) compares probe p with the key at position at.
+// fn is called with each probe, the position of the first key >= it, and whether that is equal.
+func findMany(n, l int, sorted bool, compare func(int, int) int, fn func(int, int, bool)) {
+	var lo int
+	for p:=0; p<n; p++ {
+		cmp := func(at int) int {
+			return compare(p, at)
+		}
+		if sorted {
+			lo = gallop(lo, l, cmp)
+		} else {
+			lo = search(0, l, cmp)
+		}
+		fn(p, lo, lo < l && cmp(lo) == 0)
+	}
+}
+
+// manyBytes is findMany for the bytes types, find is called with each key in turn.
+// The keys of a class are in order, so a key gallops from where the last key in its class was found if it is greater than the key before that position.
+type manyBytes struct {
+ src prefixSource
+ from [65]int
+}
+
+// find returns the length class of the key, the position of the first key >= it in that class, and whether that is equal.
+// Nothing it uses escapes, so the key can be in a buffer on the caller's stack.
+func (m *manyBytes) find(key []byte) (int, int, bool) {
+	var words [8]uint64
+	on := 64
+	if len(key) <= 64 {
+		var l int
+		words, l = splitKey(key)
+		on = (len(key) - 1) / 8 * 8 + l
+	}
+	src := m.src
+	cmp := func(at int) int {
+		if on == 64 {
+			stored, _ := src.classEntry(64, at)
+			return bytes.Compare(key, stored)
+		}
+		return -src.classCompare(on, at, words)
+	}
+	size := src.classLen(on)
+	var at int
+	if lo := m.from[on]; lo > 0 && lo <= size && cmp(lo - 1) > 0 {
+		at = gallop(lo, size, cmp)
+	} else {
+		at = search(0, size, cmp)
+	}
+	m.from[on] = at
+	return on, at, at < size && cmp(at) == 0
+}
+
+// appendRunes appends the encoding of word to dst (see runes2bytes), so that FindMany can reuse one buffer for all the keys.
+func appendRunes(dst []byte, word []rune, legacy bool) []byte {
+	for _, r := range word {
+		switch {
+			case r >= 65536:
+				dst = append(dst, 3, byte(r), byte(r >> 8), byte(r >> 16))
+			case r >= 256:
+				dst = append(dst, 2, byte(r), byte(r >> 8))
+			case r > 0 && r < 4 && !legacy:
+				dst = append(dst, 1, byte(r))
+			default:
+				dst = append(dst, byte(r))
+		}
+	}
+	return dst
+}
+
+// ---------- KeyBytes ----------
+
+// FindMany looks up all the keys, setting dstIndex[i] to the index keys[i] has or would have and dstFound[i] to whether it exists.
+// dstIndex and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare) it walks through the structure instead of searching for each.
+func (t *KeyBytes) FindMany(keys [][]byte, dstIndex []int, dstFound []bool) {
+	tiered := t.total - len(t.overflow)
+	m := manyBytes{src: t}
+	for p, key := range keys {
+		on, at, ok := m.find(key)
+		if on == 64 {
+			dstIndex[p] = tiered + at
+		} else {
+			dstIndex[p] = t.count[on] + at
+		}
+		dstFound[p] = ok
+	}
+}
+
+// ---------- KeyValBytes ----------
+
+// classValue returns the value at position at in length class on.
+func (t *KeyValBytes) classValue(on, at int) int {
+	if on == 64 {
+		return t.overflow[at].K
+	}
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			return int(t.limit8[run][at][1])
+		case 1:
+			return int(t.limit16[run][at][2])
+		case 2:
+			return int(t.limit24[run][at][3])
+		case 3:
+			return int(t.limit32[run][at][4])
+		case 4:
+			return int(t.limit40[run][at][5])
+		case 5:
+			return int(t.limit48[run][at][6])
+		case 6:
+			return int(t.limit56[run][at][7])
+		default:
+			return int(t.limit64[run][at][8])
+	}
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the value of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare) it walks through the structure instead of searching for each.
+func (t *KeyValBytes) FindMany(keys [][]byte, dstVals []int, dstFound []bool) {
+	m := manyBytes{src: t}
+	for p, key := range keys {
+		on, at, ok := m.find(key)
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.classValue(on, at)
+		} else {
+			dstVals[p] = 0
+		}
+	}
+}
+
+// ---------- CounterBytes ----------
+
+// classValue returns the value at position at in length class on.
+func (t *CounterBytes) classValue(on, at int) int {
+	if on == 64 {
+		return t.overflow[at].K
+	}
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			return int(t.limit8[run][at][1])
+		case 1:
+			return int(t.limit16[run][at][2])
+		case 2:
+			return int(t.limit24[run][at][3])
+		case 3:
+			return int(t.limit32[run][at][4])
+		case 4:
+			return int(t.limit40[run][at][5])
+		case 5:
+			return int(t.limit48[run][at][6])
+		case 6:
+			return int(t.limit56[run][at][7])
+		default:
+			return int(t.limit64[run][at][8])
+	}
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the frequency of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare) it walks through the structure instead of searching for each.
+func (t *CounterBytes) FindMany(keys [][]byte, dstVals []int, dstFound []bool) {
+	m := manyBytes{src: t}
+	for p, key := range keys {
+		on, at, ok := m.find(key)
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.classValue(on, at)
+		} else {
+			dstVals[p] = 0
+		}
+	}
+}
+
+// ---------- CounterBytesFloat64 ----------
+
+// FindMany looks up all the keys, setting dstVals[i] to the total of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare) it walks through the structure instead of searching for each.
+func (t *CounterBytesFloat64) FindMany(keys [][]byte, dstVals []float64, dstFound []bool) {
+	m := manyBytes{src: &t.child}
+	for p, key := range keys {
+		on, at, ok := m.find(key)
+		if dstFound[p] = ok; ok {
+			dstVals[p] = math.Float64frombits(uint64(t.child.classValue(on, at)))
+		} else {
+			dstVals[p] = 0
+		}
+	}
+}
+
+// ---------- CounterBytesInt64 ----------
+
+// FindMany looks up all the keys, setting dstVals[i] to the total of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare) it walks through the structure instead of searching for each.
+func (t *CounterBytesInt64) FindMany(keys [][]byte, dstVals []int64, dstFound []bool) {
+	m := manyBytes{src: &t.child}
+	for p, key := range keys {
+		on, at, ok := m.find(key)
+		if dstFound[p] = ok; ok {
+			dstVals[p] = int64(t.child.classValue(on, at))
+		} else {
+			dstVals[p] = 0
+		}
+	}
+}
+
+// ---------- KeyValBytesOf ----------
+
+// FindMany looks up all the keys, setting dstVals[i] to the value of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare) it walks through the structure instead of searching for each.
+func (t *KeyValBytesOf[V]) FindMany(keys [][]byte, dstVals []V, dstFound []bool) {
+	tiered := t.child.total - len(t.child.overflow)
+	m := manyBytes{src: &t.child}
+	for p, key := range keys {
+		on, at, ok := m.find(key)
+		if dstFound[p] = ok; !ok {
+			dstVals[p] = *new(V)
+		} else if on == 64 {
+			dstVals[p] = t.val[tiered + at]
+		} else {
+			dstVals[p] = t.val[t.child.count[on] + at]
+		}
+	}
+}
+
+// ---------- Runes ----------
+
+// FindMany looks up all the keys, setting dstIndex[i] to the index keys[i] has or would have and dstFound[i] to whether it exists.
+// dstIndex and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare of their encoding) it walks through the structure instead of searching for each.
+func (t *KeyRunes) FindMany(keys [][]rune, dstIndex []int, dstFound []bool) {
+	tiered := t.child.total - len(t.child.overflow)
+	m := manyBytes{src: &t.child}
+	var arr [256]byte // only a key that encodes to more than this allocates
+	buf := arr[:0]
+	for p, key := range keys {
+		buf = appendRunes(buf[:0], key, t.legacy)
+		on, at, ok := m.find(buf)
+		if on == 64 {
+			dstIndex[p] = tiered + at
+		} else {
+			dstIndex[p] = t.child.count[on] + at
+		}
+		dstFound[p] = ok
+	}
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the value of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare of their encoding) it walks through the structure instead of searching for each.
+func (t *KeyValRunes) FindMany(keys [][]rune, dstVals []int, dstFound []bool) {
+	m := manyBytes{src: &t.child}
+	var arr [256]byte // only a key that encodes to more than this allocates
+	buf := arr[:0]
+	for p, key := range keys {
+		buf = appendRunes(buf[:0], key, t.legacy)
+		on, at, ok := m.find(buf)
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.child.classValue(on, at)
+		} else {
+			dstVals[p] = 0
+		}
+	}
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the frequency of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare of their encoding) it walks through the structure instead of searching for each.
+func (t *CounterRunes) FindMany(keys [][]rune, dstVals []int, dstFound []bool) {
+	m := manyBytes{src: &t.child}
+	var arr [256]byte // only a key that encodes to more than this allocates
+	buf := arr[:0]
+	for p, key := range keys {
+		buf = appendRunes(buf[:0], key, t.legacy)
+		on, at, ok := m.find(buf)
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.child.classValue(on, at)
+		} else {
+			dstVals[p] = 0
+		}
+	}
+}
+
+// ---------- String ----------
+
+// FindMany looks up all the keys, setting dstIndex[i] to the index keys[i] has or would have and dstFound[i] to whether it exists.
+// dstIndex and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare) it walks through the structure instead of searching for each.
+func (t *KeyString) FindMany(keys []string, dstIndex []int, dstFound []bool) {
+	tiered := t.child.total - len(t.child.overflow)
+	m := manyBytes{src: &t.child}
+	for p, key := range keys {
+		on, at, ok := m.find(stringBytes(key))
+		if on == 64 {
+			dstIndex[p] = tiered + at
+		} else {
+			dstIndex[p] = t.child.count[on] + at
+		}
+		dstFound[p] = ok
+	}
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the value of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare) it walks through the structure instead of searching for each.
+func (t *KeyValString) FindMany(keys []string, dstVals []int, dstFound []bool) {
+	m := manyBytes{src: &t.child}
+	for p, key := range keys {
+		on, at, ok := m.find(stringBytes(key))
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.child.classValue(on, at)
+		} else {
+			dstVals[p] = 0
+		}
+	}
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the frequency of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted (bytes.Compare) it walks through the structure instead of searching for each.
+func (t *CounterString) FindMany(keys []string, dstVals []int, dstFound []bool) {
+	m := manyBytes{src: &t.child}
+	for p, key := range keys {
+		on, at, ok := m.find(stringBytes(key))
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.child.classValue(on, at)
+		} else {
+			dstVals[p] = 0
+		}
+	}
+}
+
+// ---------- Key ----------
+
+// FindMany looks up all the keys, setting dstIndex[i] to the index keys[i] has or would have and dstFound[i] to whether it exists.
+// dstIndex and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *Key[K]) FindMany(keys []K, dstIndex []int, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSorted(keys), func(p, at int) int {
+		return compareOrdered(&keys[p], &t.key[at])
+	}, func(p, at int, ok bool) {
+		dstIndex[p], dstFound[p] = at, ok
+	})
+}
+
+// ---------- KeyVal ----------
+
+// FindMany looks up all the keys, setting dstVals[i] to the value of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyVal[K, V]) FindMany(keys []K, dstVals []V, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSorted(keys), func(p, at int) int {
+		return compareOrdered(&keys[p], &t.key[at].V)
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.key[at].K
+		} else {
+			dstVals[p] = *new(V)
+		}
+	})
+}
+
+// ---------- Counter ----------
+
+// FindMany looks up all the keys, setting dstVals[i] to the frequency of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *Counter[K, N]) FindMany(keys []K, dstVals []N, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSorted(keys), func(p, at int) int {
+		return compareOrdered(&keys[p], &t.key[at].V)
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.key[at].K
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
+
+// ---------- Float ----------
+
+// FindMany looks up all the keys, setting dstIndex[i] to the index keys[i] has or would have and dstFound[i] to whether it exists.
+// dstIndex and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyFloat64) FindMany(keys []float64, dstIndex []int, dstFound []bool) {
+	findMany(len(keys), len(t.child.key), isSortedFloat64(keys), func(p, at int) int {
+		key := float642key(keys[p])
+		return compareOrdered(&key, &t.child.key[at])
+	}, func(p, at int, ok bool) {
+		dstIndex[p], dstFound[p] = at, ok
+	})
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the value of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyValFloat64) FindMany(keys []float64, dstVals []int, dstFound []bool) {
+	findMany(len(keys), len(t.child.key), isSortedFloat64(keys), func(p, at int) int {
+		key := float642key(keys[p])
+		return compareOrdered(&key, &t.child.key[at].V)
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.child.key[at].K
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the frequency of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *CounterFloat64) FindMany(keys []float64, dstVals []int, dstFound []bool) {
+	findMany(len(keys), len(t.child.key), isSortedFloat64(keys), func(p, at int) int {
+		key := float642key(keys[p])
+		return compareOrdered(&key, &t.child.key[at].V)
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.child.key[at].K
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
+
+// isSortedFloat64 reports whether keys are in the order of the float keys, where NaN is last.
+func isSortedFloat64(keys []float64) bool {
+	for i:=1; i<len(keys); i++ {
+		if float642key(keys[i]) < float642key(keys[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindMany looks up all the keys, setting dstIndex[i] to the index keys[i] has or would have and dstFound[i] to whether it exists.
+// dstIndex and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyFloat32) FindMany(keys []float32, dstIndex []int, dstFound []bool) {
+	findMany(len(keys), len(t.child.key), isSortedFloat32(keys), func(p, at int) int {
+		key := float322key(keys[p])
+		return compareOrdered(&key, &t.child.key[at])
+	}, func(p, at int, ok bool) {
+		dstIndex[p], dstFound[p] = at, ok
+	})
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the value of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyValFloat32) FindMany(keys []float32, dstVals []int, dstFound []bool) {
+	findMany(len(keys), len(t.child.key), isSortedFloat32(keys), func(p, at int) int {
+		key := float322key(keys[p])
+		return compareOrdered(&key, &t.child.key[at].V)
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.child.key[at].K
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the frequency of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *CounterFloat32) FindMany(keys []float32, dstVals []int, dstFound []bool) {
+	findMany(len(keys), len(t.child.key), isSortedFloat32(keys), func(p, at int) int {
+		key := float322key(keys[p])
+		return compareOrdered(&key, &t.child.key[at].V)
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = t.child.key[at].K
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
+
+// isSortedFloat32 reports whether keys are in the order of the float keys, where NaN is last.
+func isSortedFloat32(keys []float32) bool {
+	for i:=1; i<len(keys); i++ {
+		if float322key(keys[i]) < float322key(keys[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ---------- Fixed size ----------
+
+// FindMany looks up all the keys, setting dstIndex[i] to the index keys[i] has or would have and dstFound[i] to whether it exists.
+// dstIndex and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyUint128) FindMany(keys [][16]byte, dstIndex []int, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSortedFunc(keys, func(a, b [16]byte) int {
+		return bytes.Compare(a[:], b[:])
+	}), func(p, at int) int {
+		return compare128(uint128(keys[p]), t.key[at][:])
+	}, func(p, at int, ok bool) {
+		dstIndex[p], dstFound[p] = at, ok
+	})
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the value of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyValUint128) FindMany(keys [][16]byte, dstVals []int, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSortedFunc(keys, func(a, b [16]byte) int {
+		return bytes.Compare(a[:], b[:])
+	}), func(p, at int) int {
+		return compare128(uint128(keys[p]), t.key[at][:])
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = int(t.key[at][2])
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the frequency of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *CounterUint128) FindMany(keys [][16]byte, dstVals []int, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSortedFunc(keys, func(a, b [16]byte) int {
+		return bytes.Compare(a[:], b[:])
+	}), func(p, at int) int {
+		return compare128(uint128(keys[p]), t.key[at][:])
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = int(t.key[at][2])
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
+
+// FindMany looks up all the keys, setting dstIndex[i] to the index keys[i] has or would have and dstFound[i] to whether it exists.
+// dstIndex and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyUint160) FindMany(keys [][20]byte, dstIndex []int, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSortedFunc(keys, func(a, b [20]byte) int {
+		return bytes.Compare(a[:], b[:])
+	}), func(p, at int) int {
+		return compare160(uint160(keys[p]), t.key[at][:])
+	}, func(p, at int, ok bool) {
+		dstIndex[p], dstFound[p] = at, ok
+	})
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the value of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyValUint160) FindMany(keys [][20]byte, dstVals []int, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSortedFunc(keys, func(a, b [20]byte) int {
+		return bytes.Compare(a[:], b[:])
+	}), func(p, at int) int {
+		return compare160(uint160(keys[p]), t.key[at][:])
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = int(t.key[at][3])
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the frequency of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *CounterUint160) FindMany(keys [][20]byte, dstVals []int, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSortedFunc(keys, func(a, b [20]byte) int {
+		return bytes.Compare(a[:], b[:])
+	}), func(p, at int) int {
+		return compare160(uint160(keys[p]), t.key[at][:])
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = int(t.key[at][3])
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
+
+// FindMany looks up all the keys, setting dstIndex[i] to the index keys[i] has or would have and dstFound[i] to whether it exists.
+// dstIndex and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyUint256) FindMany(keys [][32]byte, dstIndex []int, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSortedFunc(keys, func(a, b [32]byte) int {
+		return bytes.Compare(a[:], b[:])
+	}), func(p, at int) int {
+		return compare256(uint256(keys[p]), t.key[at][:])
+	}, func(p, at int, ok bool) {
+		dstIndex[p], dstFound[p] = at, ok
+	})
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the value of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *KeyValUint256) FindMany(keys [][32]byte, dstVals []int, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSortedFunc(keys, func(a, b [32]byte) int {
+		return bytes.Compare(a[:], b[:])
+	}), func(p, at int) int {
+		return compare256(uint256(keys[p]), t.key[at][:])
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = int(t.key[at][4])
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
+
+// FindMany looks up all the keys, setting dstVals[i] to the frequency of keys[i] and dstFound[i] to whether it exists.
+// dstVals and dstFound must be at least as long as keys. If keys are sorted it walks through the structure instead of searching for each.
+func (t *CounterUint256) FindMany(keys [][32]byte, dstVals []int, dstFound []bool) {
+	findMany(len(keys), len(t.key), slices.IsSortedFunc(keys, func(a, b [32]byte) int {
+		return bytes.Compare(a[:], b[:])
+	}), func(p, at int) int {
+		return compare256(uint256(keys[p]), t.key[at][:])
+	}, func(p, at int, ok bool) {
+		if dstFound[p] = ok; ok {
+			dstVals[p] = int(t.key[at][4])
+		} else {
+			dstVals[p] = 0
+		}
+	})
+}
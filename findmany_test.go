@@ -0,0 +1,233 @@
+package binsearch
+
+import (
+ "bytes"
+ "math/rand"
+ "slices"
+ "sort"
+ "testing"
+)
+
+func TestKeyBytesFindMany(t *testing.T) {
+	rnd := rand.New(rand.NewSource(23))
+	gen := func() []byte {
+		l := rnd.Intn(20)
+		if rnd.Intn(20) == 0 {
+			l = 60 + rnd.Intn(10)
+		}
+		x := make([]byte, l)
+		for j := range x {
+			x[j] = `abc`[rnd.Intn(3)]
+		}
+		return x
+	}
+	k := new(KeyBytes)
+	kv := new(KeyValBytes)
+	c := new(CounterBytes)
+	f := new(CounterBytesFloat64)
+	of := new(KeyValBytesOf[string])
+	seen := make(map[string]bool)
+	for i:=0; i<4000; i++ {
+		x := gen()
+		if !seen[string(x)] {
+			seen[string(x)] = true
+			k.AddUnsorted(x)
+			kv.AddUnsorted(x, i)
+			of.AddUnsorted(x, string(x))
+		}
+		c.Add(x, i % 7 + 1)
+		f.Add(x, 1.5)
+	}
+	k.Build()
+	kv.Build()
+	c.Build()
+	f.Build()
+	of.Build()
+	for _, sorted := range []bool{false, true} {
+		q := [][]byte{{}}
+		for i:=0; i<3000; i++ {
+			q = append(q, gen())
+		}
+		if sorted {
+			sort.Slice(q, func(i, j int) bool { return bytes.Compare(q[i], q[j]) < 0 })
+		}
+		idx := make([]int, len(q))
+		vals := make([]int, len(q))
+		fvals := make([]float64, len(q))
+		svals := make([]string, len(q))
+		found := make([]bool, len(q))
+		k.FindMany(q, idx, found)
+		for i, x := range q {
+			if j, ok := k.Find(x); ok != found[i] || j != idx[i] {
+				t.Fatalf(`sorted %v: FindMany gave %d, %v for %q, Find gives %d, %v`, sorted, idx[i], found[i], x, j, ok)
+			}
+		}
+		kv.FindMany(q, vals, found)
+		for i, x := range q {
+			if v, ok := kv.Find(x); ok != found[i] || v != vals[i] {
+				t.Fatalf(`sorted %v: KeyValBytes.FindMany gave %d, %v for %q`, sorted, vals[i], found[i], x)
+			}
+		}
+		c.FindMany(q, vals, found)
+		for i, x := range q {
+			if v, ok := c.Find(x); ok != found[i] || v != vals[i] {
+				t.Fatalf(`sorted %v: CounterBytes.FindMany gave %d, %v for %q`, sorted, vals[i], found[i], x)
+			}
+		}
+		f.FindMany(q, fvals, found)
+		for i, x := range q {
+			if v, ok := f.Find(x); ok != found[i] || v != fvals[i] {
+				t.Fatalf(`sorted %v: CounterBytesFloat64.FindMany gave %v, %v for %q`, sorted, fvals[i], found[i], x)
+			}
+		}
+		of.FindMany(q, svals, found)
+		for i, x := range q {
+			if v, ok := of.Find(x); ok != found[i] || v != svals[i] {
+				t.Fatalf(`sorted %v: KeyValBytesOf.FindMany gave %q, %v for %q`, sorted, svals[i], found[i], x)
+			}
+		}
+		if n := testing.AllocsPerRun(10, func() { k.FindMany(q, idx, found); c.FindMany(q, vals, found) }); n != 0 {
+			t.Fatalf(`FindMany allocates %v times`, n)
+		}
+	}
+}
+
+func TestKeyRunesFindMany(t *testing.T) {
+	rnd := rand.New(rand.NewSource(24))
+	alphabet := []rune("ab\x02é€𝄞")
+	gen := func() []rune {
+		l := rnd.Intn(12)
+		if rnd.Intn(20) == 0 {
+			l = 30
+		}
+		x := make([]rune, l)
+		for j := range x {
+			x[j] = alphabet[rnd.Intn(len(alphabet))]
+		}
+		return x
+	}
+	ks := new(KeyString)
+	cs := new(CounterString)
+	kr := new(KeyRunes)
+	cr := new(CounterRunes)
+	for i:=0; i<3000; i++ {
+		x := gen()
+		ks.Add(string(x))
+		cs.Add(string(x), 1)
+		kr.Add(x)
+		cr.Add(x, 2)
+	}
+	cs.Build()
+	cr.Build()
+	for _, sorted := range []bool{false, true} {
+		var qs []string
+		var qr [][]rune
+		for i:=0; i<2000; i++ {
+			x := gen()
+			qs = append(qs, string(x))
+			qr = append(qr, x)
+		}
+		if sorted {
+			slices.Sort(qs)
+		}
+		idx := make([]int, len(qs))
+		found := make([]bool, len(qs))
+		ks.FindMany(qs, idx, found)
+		for i, x := range qs {
+			if j, ok := ks.Find(x); ok != found[i] || j != idx[i] {
+				t.Fatalf(`sorted %v: KeyString.FindMany gave %d, %v for %q`, sorted, idx[i], found[i], x)
+			}
+		}
+		cs.FindMany(qs, idx, found)
+		for i, x := range qs {
+			if v, ok := cs.Find(x); ok != found[i] || v != idx[i] {
+				t.Fatalf(`sorted %v: CounterString.FindMany gave %d, %v for %q`, sorted, idx[i], found[i], x)
+			}
+		}
+		kr.FindMany(qr, idx, found)
+		for i, x := range qr {
+			if j, ok := kr.Find(x); ok != found[i] || j != idx[i] {
+				t.Fatalf(`sorted %v: KeyRunes.FindMany gave %d, %v for %q`, sorted, idx[i], found[i], string(x))
+			}
+		}
+		cr.FindMany(qr, idx, found)
+		for i, x := range qr {
+			if v, ok := cr.Find(x); ok != found[i] || v != idx[i] {
+				t.Fatalf(`sorted %v: CounterRunes.FindMany gave %d, %v for %q`, sorted, idx[i], found[i], string(x))
+			}
+		}
+		if n := testing.AllocsPerRun(10, func() { ks.FindMany(qs, idx, found); cs.FindMany(qs, idx, found) }); n != 0 {
+			t.Fatalf(`the string FindMany allocates %v times`, n)
+		}
+		if n := testing.AllocsPerRun(10, func() { kr.FindMany(qr, idx, found); cr.FindMany(qr, idx, found) }); n != 0 {
+			t.Fatalf(`the runes FindMany allocates %v times`, n)
+		}
+	}
+}
+
+func TestKeyFindMany(t *testing.T) {
+	rnd := rand.New(rand.NewSource(25))
+	g := NewKey[uint64](0)
+	gc := new(Counter[int, int])
+	u := new(CounterUint128)
+	fk := new(KeyFloat64)
+	for i:=0; i<2000; i++ {
+		g.Add(uint64(rnd.Intn(5000)))
+		gc.Add(rnd.Intn(5000), 1)
+		var b [16]byte
+		b[3] = byte(rnd.Intn(3))
+		b[15] = byte(rnd.Intn(200))
+		u.Add(b, 2)
+		fk.Add(float64(rnd.Intn(5000)) - 2500.5)
+	}
+	gc.Build()
+	u.Build()
+	for _, sorted := range []bool{false, true} {
+		q := make([]uint64, 3000)
+		qi := make([]int, 3000)
+		qb := make([][16]byte, 3000)
+		qf := make([]float64, 3000)
+		for i := range q {
+			q[i] = uint64(rnd.Intn(5100))
+			qi[i] = int(q[i])
+			qb[i][3] = byte(rnd.Intn(3))
+			qb[i][15] = byte(rnd.Intn(256))
+			qf[i] = float64(q[i]) - 2500.5
+		}
+		if sorted {
+			slices.Sort(q)
+			slices.Sort(qi)
+			slices.Sort(qf)
+			sort.Slice(qb, func(i, j int) bool { return bytes.Compare(qb[i][:], qb[j][:]) < 0 })
+		}
+		idx := make([]int, 3000)
+		found := make([]bool, 3000)
+		g.FindMany(q, idx, found)
+		for i := range q {
+			if j, ok := g.Find(q[i]); ok != found[i] || j != idx[i] {
+				t.Fatal(`Key`, sorted, q[i], idx[i], found[i])
+			}
+		}
+		if n := testing.AllocsPerRun(10, func() { g.FindMany(q, idx, found) }); n != 0 {
+			t.Fatalf(`Key.FindMany allocates %v times`, n)
+		}
+		gc.FindMany(qi, idx, found)
+		for i := range qi {
+			if v, ok := gc.Find(qi[i]); ok != found[i] || v != idx[i] {
+				t.Fatal(`Counter`, sorted, qi[i], idx[i], found[i])
+			}
+		}
+		u.FindMany(qb, idx, found)
+		for i := range qb {
+			if v, ok := u.Find(qb[i]); ok != found[i] || v != idx[i] {
+				t.Fatal(`CounterUint128`, sorted, qb[i], idx[i], found[i])
+			}
+		}
+		fk.FindMany(qf, idx, found)
+		for i := range qf {
+			if j, ok := fk.Find(qf[i]); ok != found[i] || j != idx[i] {
+				t.Fatal(`KeyFloat64`, sorted, qf[i], idx[i], found[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,599 @@
+package binsearch
+
+import (
+// Customized sorting algorithm for the fixed size keys
+ "github.com/AlasdairF/BinSearch/Fixed"
+// Read/write custom file format
+ "github.com/AlasdairF/Custom"
+)
+
+/*
+	KeyUint128, KeyUint160 and KeyUint256 are for fixed size [16]byte, [20]byte and [32]byte keys, e.g. UUIDs, SHA-1 and SHA-256 hashes.
+	The keys are stored as flat arrays of 2, 3 or 4 big-endian uint64s, so they sort in the same order as the bytes.
+	As every key is the same length they don't need the length classes of KeyBytes.
+	The keys are written to file as they are, not as varints.
+	All three sizes share one generic implementation (keyFixed, keyValFixed & counterFixed) which the exported types embed.
+	Build sorts with the sortFixed package, in the same way as the length classes of KeyBytes are sorted with sortLimit16, etc.
+	It compares every word, so KeyUint128 sorts each key with its old index after it and equal keys keep their order.
+*/
+
+// fixedSize is the set of fixed size keys.
+type fixedSize interface {
+	[16]byte | [20]byte | [32]byte
+}
+
+// fixedKey is the set of stored keys, 8 bytes to a word.
+type fixedKey interface {
+	[2]uint64 | [3]uint64 | [4]uint64
+}
+
+// fixedRow is the set of stored keys followed by a value.
+type fixedRow interface {
+	[3]uint64 | [4]uint64 | [5]uint64
+}
+
+type fixedWords interface {
+	fixedKey | fixedRow
+}
+
+// toWords converts a key to big-endian words, the last word holds whatever bytes are left (4 for [20]byte).
+func toWords[B fixedSize, W fixedWords](b B) W {
+	var w W
+	for i:=0; i<len(b); i++ {
+		w[i >> 3] = (w[i >> 3] << 8) | uint64(b[i])
+	}
+	return w
+}
+
+// fromWords is the reverse of toWords.
+func fromWords[B fixedSize, W fixedWords](w *W) B {
+	var b B
+	for i:=0; i<len(b); i++ {
+		n := min(8, len(b) - (i &^ 7)) // the number of bytes in this word
+		b[i] = byte((*w)[i >> 3] >> (8 * (n - 1 - (i & 7))))
+	}
+	return b
+}
+
+// compareFixed compares the first n words of a and b.
+func compareFixed[A, B fixedWords](a *A, b *B, n int) int {
+	for i:=0; i<n; i++ {
+		if x, y := (*a)[i], (*b)[i]; x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// findFixed returns the position of key in rows, or where it would be, and whether it exists.
+func findFixed[W fixedKey, R fixedWords](rows []R, key *W) (int, bool) {
+	var at, min int
+	n := len(*key)
+	max := len(rows) - 1
+	for min <= max {
+		at = min + ((max - min) / 2)
+		if c := compareFixed(key, &rows[at], n); c < 0 {
+			max = at - 1
+			continue
+		} else if c > 0 {
+			min = at + 1
+			continue
+		}
+		return at, true // found
+	}
+	return min, false // doesn't exist
+}
+
+// insertAt inserts v into cur at position i.
+func insertAt[T any](cur []T, i int, v T) []T {
+	lc := len(cur)
+	if lc == cap(cur) {
+		tmp := make([]T, lc + 1, (lc * 2) + 1)
+		copy(tmp, cur[0:i])
+		copy(tmp[i+1:], cur[i:])
+		cur = tmp
+	} else {
+		cur = cur[0:lc+1]
+		copy(cur[i+1:], cur[i:])
+	}
+	cur[i] = v
+	return cur
+}
+
+func writeFixed[W fixedWords](w custom.Interface, v *W, n int) {
+	for i:=0; i<n; i++ {
+		w.WriteUint64((*v)[i])
+	}
+}
+
+func readFixed[W fixedWords](r *custom.Reader, v *W, n int) {
+	for i:=0; i<n; i++ {
+		(*v)[i] = r.ReadUint64()
+	}
+}
+
+// ---------- keyFixed ----------
+
+type keyFixed[B fixedSize, W fixedKey, R fixedRow] struct { // R is only used by Build
+ key []W
+ cursor int
+}
+
+func (t *keyFixed[B, W, R]) Len() int {
+	return len(t.key)
+}
+
+// Find returns the index based on the key.
+func (t *keyFixed[B, W, R]) Find(thekey B) (int, bool) {
+	key := toWords[B, W](thekey)
+	return findFixed(t.key, &key)
+}
+
+// Add adds this key to the index in the correct position, if it does not already exist.
+func (t *keyFixed[B, W, R]) Add(thekey B) (int, bool) {
+	i, ok := t.Find(thekey)
+	if !ok {
+		t.AddAt(thekey, i)
+	}
+	return i, ok
+}
+
+// AddUnsorted adds this key to the end of the index for later building with Build.
+func (t *keyFixed[B, W, R]) AddUnsorted(thekey B) {
+	t.key = append(t.key, toWords[B, W](thekey))
+}
+
+// AddAt adds this key to the index in this exact position, so it does not require later rebuilding.
+func (t *keyFixed[B, W, R]) AddAt(thekey B, i int) {
+	t.key = insertAt(t.key, i, toWords[B, W](thekey))
+}
+
+// Build sorts the keys and returns a slice mapping the new indexes to the old indexes.
+func (t *keyFixed[B, W, R]) Build() []int {
+	cur := t.key
+	var w W
+	n := len(w)
+	// Each key is sorted with its old index after it, so equal keys keep their order
+	temp := make(sortFixed.Slice[R], len(cur))
+	for i := range cur {
+		for j:=0; j<n; j++ {
+			temp[i][j] = cur[i][j]
+		}
+		temp[i][n] = uint64(i)
+	}
+	sortFixed.Asc(temp)
+	imap := make([]int, len(cur))
+	for i := range temp {
+		for j:=0; j<n; j++ {
+			cur[i][j] = temp[i][j]
+		}
+		imap[i] = int(temp[i][n])
+	}
+	return imap
+}
+
+func (t *keyFixed[B, W, R]) Optimize() {
+	temp := make([]W, len(t.key))
+	copy(temp, t.key)
+	t.key = temp
+}
+
+func (t *keyFixed[B, W, R]) Reset() bool {
+	t.cursor = 0
+	if len(t.key) == 0 {
+		return false
+	}
+	return true
+}
+
+func (t *keyFixed[B, W, R]) Next() (B, bool) {
+	v := &t.key[t.cursor]
+	if t.cursor++; t.cursor == len(t.key) {
+		t.cursor = 0
+		return fromWords[B](v), true
+	}
+	return fromWords[B](v), false
+}
+
+func (t *keyFixed[B, W, R]) Keys() []B {
+	keys := make([]B, len(t.key))
+	for i := range t.key {
+		keys[i] = fromWords[B](&t.key[i])
+	}
+	return keys
+}
+
+func (t *keyFixed[B, W, R]) Write(w custom.Interface) {
+	var v W
+	w.WriteUint64Variable(uint64(len(t.key)))
+	for i := range t.key {
+		writeFixed(w, &t.key[i], len(v))
+	}
+}
+
+func (t *keyFixed[B, W, R]) Read(r *custom.Reader) {
+	var v W
+	l := int(r.ReadUint64Variable())
+	tmp := make([]W, l)
+	for i:=0; i<l; i++ {
+		readFixed(r, &tmp[i], len(v))
+	}
+	t.key = tmp
+}
+
+// ---------- keyValFixed ----------
+
+type keyValFixed[B fixedSize, W fixedKey, R fixedRow] struct {
+ key []R // the value is the last
+ cursor int
+ byvalue valueOrder // used by ResetByValue & NextByValue
+}
+
+// row returns the key and value as they are stored.
+func (t *keyValFixed[B, W, R]) row(thekey B, theval int) R {
+	v := toWords[B, R](thekey)
+	v[len(v) - 1] = uint64(theval)
+	return v
+}
+
+func (t *keyValFixed[B, W, R]) Len() int {
+	return len(t.key)
+}
+
+// Find returns the value based on the key.
+func (t *keyValFixed[B, W, R]) Find(thekey B) (int, bool) {
+	key := toWords[B, W](thekey)
+	if at, ok := findFixed(t.key, &key); ok {
+		v := &t.key[at]
+		return int((*v)[len(*v) - 1]), true // found
+	}
+	return 0, false // doesn't exist
+}
+
+func (t *keyValFixed[B, W, R]) Update(thekey B, fn func(int) int) bool {
+	key := toWords[B, W](thekey)
+	if at, ok := findFixed(t.key, &key); ok {
+		v := &t.key[at]
+		(*v)[len(*v) - 1] = uint64(fn(int((*v)[len(*v) - 1])))
+		return true // found
+	}
+	return false // doesn't exist
+}
+
+func (t *keyValFixed[B, W, R]) UpdateAll(fn func(int) int) {
+	for i := range t.key {
+		v := &t.key[i]
+		(*v)[len(*v) - 1] = uint64(fn(int((*v)[len(*v) - 1])))
+	}
+}
+
+// Add adds this key and value to the index in the correct position, or replaces the value if the key already exists.
+func (t *keyValFixed[B, W, R]) Add(thekey B, theval int) bool {
+	key := toWords[B, W](thekey)
+	at, ok := findFixed(t.key, &key)
+	if ok {
+		v := &t.key[at]
+		(*v)[len(*v) - 1] = uint64(theval)
+		return true // found
+	}
+	t.key = insertAt(t.key, at, t.row(thekey, theval))
+	return false
+}
+
+// AddUnsorted adds this key to the end of the index for later building with Build.
+func (t *keyValFixed[B, W, R]) AddUnsorted(thekey B, theval int) {
+	t.key = append(t.key, t.row(thekey, theval))
+}
+
+// Build is only required after AddUnsorted.
+func (t *keyValFixed[B, W, R]) Build() {
+	sortFixed.Asc(sortFixed.Slice[R](t.key))
+}
+
+func (t *keyValFixed[B, W, R]) Optimize() {
+	temp := make([]R, len(t.key))
+	copy(temp, t.key)
+	t.key = temp
+}
+
+func (t *keyValFixed[B, W, R]) Reset() bool {
+	t.cursor = 0
+	if len(t.key) == 0 {
+		return false
+	}
+	return true
+}
+
+func (t *keyValFixed[B, W, R]) Next() (B, int, bool) {
+	v := &t.key[t.cursor]
+	if t.cursor++; t.cursor == len(t.key) {
+		t.cursor = 0
+		return fromWords[B](v), int((*v)[len(*v) - 1]), true
+	}
+	return fromWords[B](v), int((*v)[len(*v) - 1]), false
+}
+
+func (t *keyValFixed[B, W, R]) Keys() []B {
+	keys := make([]B, len(t.key))
+	for i := range t.key {
+		keys[i] = fromWords[B](&t.key[i])
+	}
+	return keys
+}
+
+func (t *keyValFixed[B, W, R]) Write(w custom.Interface) {
+	writeRows(w, t.key)
+}
+
+func (t *keyValFixed[B, W, R]) Read(r *custom.Reader) {
+	t.key = readRows[R](r)
+}
+
+// writeRows writes each value as a varint followed by the words of the key.
+func writeRows[R fixedRow](w custom.Interface, rows []R) {
+	w.WriteUint64Variable(uint64(len(rows)))
+	for i := range rows {
+		v := &rows[i]
+		w.WriteUint64Variable((*v)[len(*v) - 1])
+		writeFixed(w, v, len(*v) - 1)
+	}
+}
+
+func readRows[R fixedRow](r *custom.Reader) []R {
+	l := int(r.ReadUint64Variable())
+	tmp := make([]R, l)
+	for i:=0; i<l; i++ {
+		v := &tmp[i]
+		(*v)[len(*v) - 1] = r.ReadUint64Variable()
+		readFixed(r, v, len(*v) - 1)
+	}
+	return tmp
+}
+
+// ---------- counterFixed ----------
+
+type counterFixed[B fixedSize, W fixedKey, R fixedRow] struct {
+ key []R // the frequency is the last
+ cursor int
+ byvalue valueOrder // used by ResetByValue & NextByValue
+}
+
+// keys copies the keys.
+func (t *counterFixed[B, W, R]) keys() keyFixed[B, W, R] {
+	var obj keyFixed[B, W, R]
+	var w W
+	obj.key = make([]W, len(t.key))
+	for i := range t.key {
+		for j:=0; j<len(w); j++ {
+			obj.key[i][j] = t.key[i][j]
+		}
+	}
+	return obj
+}
+
+// keyVals copies the keys and values.
+func (t *counterFixed[B, W, R]) keyVals() keyValFixed[B, W, R] {
+	var obj keyValFixed[B, W, R]
+	obj.key = make([]R, len(t.key))
+	copy(obj.key, t.key)
+	return obj
+}
+
+// Len is only accurate after Build.
+func (t *counterFixed[B, W, R]) Len() int {
+	return len(t.key)
+}
+
+// Find returns the frequency based on the key.
+func (t *counterFixed[B, W, R]) Find(thekey B) (int, bool) {
+	key := toWords[B, W](thekey)
+	if at, ok := findFixed(t.key, &key); ok {
+		v := &t.key[at]
+		return int((*v)[len(*v) - 1]), true // found
+	}
+	return 0, false // doesn't exist
+}
+
+func (t *counterFixed[B, W, R]) Update(thekey B, fn func(int) int) bool {
+	key := toWords[B, W](thekey)
+	if at, ok := findFixed(t.key, &key); ok {
+		v := &t.key[at]
+		(*v)[len(*v) - 1] = uint64(fn(int((*v)[len(*v) - 1])))
+		return true // found
+	}
+	return false // doesn't exist
+}
+
+func (t *counterFixed[B, W, R]) UpdateAll(fn func(int) int) {
+	for i := range t.key {
+		v := &t.key[i]
+		(*v)[len(*v) - 1] = uint64(fn(int((*v)[len(*v) - 1])))
+	}
+}
+
+// Add adds this key to the end of the index for later building with Build.
+func (t *counterFixed[B, W, R]) Add(thekey B, theval int) {
+	v := toWords[B, R](thekey)
+	v[len(v) - 1] = uint64(theval)
+	t.key = append(t.key, v)
+}
+
+// Build sorts the keys and adds up the values of identical keys. It is always required before Find.
+func (t *counterFixed[B, W, R]) Build() {
+	temp := t.key
+	if len(temp) == 0 {
+		return
+	}
+	var w W
+	n := len(w)
+	sortFixed.Asc(sortFixed.Slice[R](temp))
+	this := temp[0]
+	var on int
+	for _, k := range temp[1:] {
+		if compareFixed(&k, &this, n) == 0 {
+			this[n] += k[n]
+		} else {
+			temp[on] = this
+			on++
+			this = k
+		}
+	}
+	temp[on] = this
+	t.key = temp[0:on+1]
+}
+
+func (t *counterFixed[B, W, R]) Optimize() {
+	temp := make([]R, len(t.key))
+	copy(temp, t.key)
+	t.key = temp
+}
+
+func (t *counterFixed[B, W, R]) Reset() bool {
+	t.cursor = 0
+	if len(t.key) == 0 {
+		return false
+	}
+	return true
+}
+
+func (t *counterFixed[B, W, R]) Next() (B, int, bool) {
+	v := &t.key[t.cursor]
+	if t.cursor++; t.cursor == len(t.key) {
+		t.cursor = 0
+		return fromWords[B](v), int((*v)[len(*v) - 1]), true
+	}
+	return fromWords[B](v), int((*v)[len(*v) - 1]), false
+}
+
+func (t *counterFixed[B, W, R]) Keys() []B {
+	keys := make([]B, len(t.key))
+	for i := range t.key {
+		keys[i] = fromWords[B](&t.key[i])
+	}
+	return keys
+}
+
+func (t *counterFixed[B, W, R]) Write(w custom.Interface) {
+	writeRows(w, t.key)
+}
+
+func (t *counterFixed[B, W, R]) Read(r *custom.Reader) {
+	t.key = readRows[R](r)
+}
+
+// ---------- KeyUint128 ----------
+
+// Add this to any struct to make it binary searchable.
+type KeyUint128 struct {
+ keyFixed[[16]byte, [2]uint64, [3]uint64]
+}
+
+// Add this to any struct to make it binary searchable.
+type KeyValUint128 struct {
+ keyValFixed[[16]byte, [2]uint64, [3]uint64]
+}
+
+// Add this to any struct to make it binary searchable.
+type CounterUint128 struct {
+ counterFixed[[16]byte, [2]uint64, [3]uint64]
+}
+
+func (t *CounterUint128) KeyUint128() *KeyUint128 {
+	return &KeyUint128{t.keys()}
+}
+
+func (t *CounterUint128) KeyValUint128() *KeyValUint128 {
+	return &KeyValUint128{t.keyVals()}
+}
+
+func uint128(b [16]byte) [2]uint64 {
+	return toWords[[16]byte, [2]uint64](b)
+}
+
+func bytes128(v []uint64) [16]byte {
+	return fromWords[[16]byte]((*[2]uint64)(v))
+}
+
+// compare128 compares the key with the first 2 words of a stored key.
+func compare128(a [2]uint64, b []uint64) int {
+	return compareFixed(&a, (*[2]uint64)(b), 2)
+}
+
+// ---------- KeyUint160 ----------
+
+// Add this to any struct to make it binary searchable.
+type KeyUint160 struct {
+ keyFixed[[20]byte, [3]uint64, [4]uint64]
+}
+
+// Add this to any struct to make it binary searchable.
+type KeyValUint160 struct {
+ keyValFixed[[20]byte, [3]uint64, [4]uint64]
+}
+
+// Add this to any struct to make it binary searchable.
+type CounterUint160 struct {
+ counterFixed[[20]byte, [3]uint64, [4]uint64]
+}
+
+func (t *CounterUint160) KeyUint160() *KeyUint160 {
+	return &KeyUint160{t.keys()}
+}
+
+func (t *CounterUint160) KeyValUint160() *KeyValUint160 {
+	return &KeyValUint160{t.keyVals()}
+}
+
+func uint160(b [20]byte) [3]uint64 {
+	return toWords[[20]byte, [3]uint64](b)
+}
+
+func bytes160(v []uint64) [20]byte {
+	return fromWords[[20]byte]((*[3]uint64)(v))
+}
+
+// compare160 compares the key with the first 3 words of a stored key.
+func compare160(a [3]uint64, b []uint64) int {
+	return compareFixed(&a, (*[3]uint64)(b), 3)
+}
+
+// ---------- KeyUint256 ----------
+
+// Add this to any struct to make it binary searchable.
+type KeyUint256 struct {
+ keyFixed[[32]byte, [4]uint64, [5]uint64]
+}
+
+// Add this to any struct to make it binary searchable.
+type KeyValUint256 struct {
+ keyValFixed[[32]byte, [4]uint64, [5]uint64]
+}
+
+// Add this to any struct to make it binary searchable.
+type CounterUint256 struct {
+ counterFixed[[32]byte, [4]uint64, [5]uint64]
+}
+
+func (t *CounterUint256) KeyUint256() *KeyUint256 {
+	return &KeyUint256{t.keys()}
+}
+
+func (t *CounterUint256) KeyValUint256() *KeyValUint256 {
+	return &KeyValUint256{t.keyVals()}
+}
+
+func uint256(b [32]byte) [4]uint64 {
+	return toWords[[32]byte, [4]uint64](b)
+}
+
+func bytes256(v []uint64) [32]byte {
+	return fromWords[[32]byte]((*[4]uint64)(v))
+}
+
+// compare256 compares the key with the first 4 words of a stored key.
+func compare256(a [4]uint64, b []uint64) int {
+	return compareFixed(&a, (*[4]uint64)(b), 4)
+}
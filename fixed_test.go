@@ -0,0 +1,185 @@
+package binsearch
+
+import (
+ "bytes"
+ "crypto/sha1"
+ "crypto/sha256"
+ "math/rand"
+ "slices"
+ "sort"
+ "testing"
+)
+
+func TestKeyUint160(t *testing.T) {
+	k := new(KeyUint160)
+	var hs [][20]byte
+	for i:=0; i<200; i++ {
+		h := sha1.Sum([]byte{byte(i)})
+		hs = append(hs, h)
+		k.AddUnsorted(h)
+	}
+	imap := k.Build()
+	keys := k.Keys()
+	if !sort.SliceIsSorted(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 }) {
+		t.Fatal(`keys are not in byte order`)
+	}
+	for i, h := range hs {
+		if j, ok := k.Find(h); !ok || imap[j] != i {
+			t.Fatal(i, j, ok)
+		}
+	}
+	if _, ok := k.Find(sha1.Sum([]byte(`missing`))); ok {
+		t.Fatal(`found a key that was not added`)
+	}
+	k2 := new(KeyUint160)
+	roundTrip(t, k.Write, k2.Read)
+	k3 := new(KeyUint160)
+	for _, h := range hs {
+		if _, ok := k3.Add(h); ok {
+			t.Fatal(`reported as already present`)
+		}
+	}
+	for _, h := range hs {
+		a, _ := k.Find(h)
+		if b, ok := k2.Find(h); !ok || a != b {
+			t.Fatal(`after Read`, a, b)
+		}
+		if b, ok := k3.Find(h); !ok || a != b {
+			t.Fatal(`after Add`, a, b)
+		}
+	}
+}
+
+func TestKeyValUint128(t *testing.T) {
+	kv := new(KeyValUint128)
+	var us [][16]byte
+	for i:=0; i<200; i++ {
+		h := sha1.Sum([]byte{byte(i)})
+		var u [16]byte
+		copy(u[:], h[:])
+		us = append(us, u)
+		if i % 2 == 0 {
+			kv.Add(u, i)
+		} else {
+			kv.AddUnsorted(u, i)
+		}
+	}
+	kv.Build()
+	kv2 := new(KeyValUint128)
+	roundTrip(t, kv.Write, kv2.Read)
+	for i, u := range us {
+		if v, ok := kv2.Find(u); !ok || v != i {
+			t.Fatal(i, v, ok)
+		}
+	}
+	if kv2.Len() != len(us) {
+		t.Fatal(kv2.Len())
+	}
+}
+
+func TestCounterUint256(t *testing.T) {
+	c := new(CounterUint256)
+	for i:=0; i<200; i++ {
+		c.Add(sha256.Sum256([]byte{byte(i % 50)}), i)
+	}
+	c.Build()
+	if c.Len() != 50 {
+		t.Fatal(c.Len())
+	}
+	c2 := new(CounterUint256)
+	roundTrip(t, c.Write, c2.Read)
+	for i:=0; i<50; i++ {
+		want := i * 4 + 300 // i + (i+50) + (i+100) + (i+150)
+		if n, ok := c2.Find(sha256.Sum256([]byte{byte(i)})); !ok || n != want {
+			t.Fatal(i, n, ok)
+		}
+	}
+	kv := c2.KeyValUint256()
+	k := c2.KeyUint256()
+	if kv.Len() != 50 || k.Len() != 50 {
+		t.Fatal(kv.Len(), k.Len())
+	}
+	n := 0
+	if k.Reset() {
+		for {
+			x, eof := k.Next()
+			a, _ := c2.Find(x)
+			if b, ok := kv.Find(x); !ok || a != b {
+				t.Fatal(`Next`, a, b)
+			}
+			n++
+			if eof {
+				break
+			}
+		}
+	}
+	if n != 50 {
+		t.Fatal(n)
+	}
+}
+
+func TestFixedBuild(t *testing.T) {
+	rnd := rand.New(rand.NewSource(8))
+	// Keys that differ only in a few bytes spread over all the words, with duplicates, in random, sorted and reversed order
+	gen := func() [32]byte {
+		var b [32]byte
+		for _, at := range []int{0, 7, 8, 17, 31} {
+			b[at] = byte(rnd.Intn(3))
+		}
+		return b
+	}
+	compare := func(a, b [32]byte) int { return bytes.Compare(a[:], b[:]) }
+	for order:=0; order<3; order++ {
+		hs := make([][32]byte, 5000)
+		for i := range hs {
+			hs[i] = gen()
+		}
+		if order > 0 {
+			slices.SortFunc(hs, compare)
+		}
+		if order == 2 {
+			slices.Reverse(hs)
+		}
+		k := new(KeyUint256)
+		kv := new(KeyValUint256)
+		c := new(CounterUint256)
+		want := make(map[[32]byte]int)
+		for i, h := range hs {
+			k.AddUnsorted(h)
+			kv.AddUnsorted(h, i)
+			c.Add(h, i)
+			want[h] += i
+		}
+		imap := k.Build()
+		kv.Build()
+		c.Build()
+		keys := k.Keys()
+		for i := range keys {
+			if keys[i] != hs[imap[i]] {
+				t.Fatalf(`order %d: key %d is not the key it was added as`, order, i)
+			}
+			if i > 0 {
+				if d := compare(keys[i-1], keys[i]); d > 0 || (d == 0 && imap[i-1] > imap[i]) {
+					t.Fatalf(`order %d: keys %d and %d are out of order`, order, i - 1, i)
+				}
+			}
+		}
+		if !slices.IsSortedFunc(kv.Keys(), compare) || kv.Len() != len(hs) {
+			t.Fatalf(`order %d: KeyValUint256 is not sorted`, order)
+		}
+		if c.Len() != len(want) {
+			t.Fatalf(`order %d: CounterUint256 has %d keys, want %d`, order, c.Len(), len(want))
+		}
+		ck := c.KeyUint256().Keys()
+		for i:=1; i<len(ck); i++ {
+			if compare(ck[i-1], ck[i]) >= 0 {
+				t.Fatalf(`order %d: CounterUint256 keys %d and %d are out of order`, order, i - 1, i)
+			}
+		}
+		for h, n := range want {
+			if v, ok := c.Find(h); !ok || v != n {
+				t.Fatalf(`order %d: CounterUint256 gives %d, want %d`, order, v, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,803 @@
+package binsearch
+
+import (
+ "github.com/AlasdairF/BinSearch/Ordered"
+ "github.com/AlasdairF/Custom"
+ "iter"
+ "math"
+)
+
+/*
+	KeyFloat64, KeyValFloat64, CounterFloat64 and the Float32 equivalents wrap Key, KeyVal & Counter.
+	The floats are stored as unsigned integers that sort in this total order:
+		-Inf < negative numbers < -0 < +0 < positive numbers < +Inf < NaN
+	-0 and +0 are different keys. All NaNs are the same key, and they are returned as math.NaN().
+	Keys are written to file as their fixed size bits, not as varints, as the top bit is nearly always set.
+*/
+
+// float642key maps the float to a uint64 that sorts in the same order.
+// Positive numbers have the sign bit set, and negative numbers have all their bits flipped so that they sort in reverse.
+func float642key(f float64) uint64 {
+	if f != f {
+		f = math.NaN() // all NaNs are the same key
+	}
+	b := math.Float64bits(f)
+	if b >> 63 == 1 {
+		return ^b
+	}
+	return b | (1 << 63)
+}
+
+func key2float64(b uint64) float64 {
+	if b >> 63 == 1 {
+		return math.Float64frombits(b &^ (1 << 63))
+	}
+	return math.Float64frombits(^b)
+}
+
+func float322key(f float32) uint32 {
+	if f != f {
+		f = float32(math.NaN())
+	}
+	b := math.Float32bits(f)
+	if b >> 31 == 1 {
+		return ^b
+	}
+	return b | (1 << 31)
+}
+
+func key2float32(b uint32) float32 {
+	if b >> 31 == 1 {
+		return math.Float32frombits(b &^ (1 << 31))
+	}
+	return math.Float32frombits(^b)
+}
+
+// ---------- KeyFloat64 ----------
+
+// Add this to any struct to make it binary searchable.
+type KeyFloat64 struct {
+ child Key[uint64]
+}
+
+func (t *KeyFloat64) Len() int {
+	return t.child.Len()
+}
+
+// Find returns the index based on the key.
+func (t *KeyFloat64) Find(thekey float64) (int, bool) {
+	return t.child.Find(float642key(thekey))
+}
+
+// Add adds this key to the index in the correct position, if it does not already exist.
+func (t *KeyFloat64) Add(thekey float64) (int, bool) {
+	return t.child.Add(float642key(thekey))
+}
+
+// AddUnsorted adds this key to the end of the index for later building with Build.
+func (t *KeyFloat64) AddUnsorted(thekey float64) {
+	t.child.AddUnsorted(float642key(thekey))
+}
+
+// AddAt adds this key to the index in this exact position, so it does not require later rebuilding.
+func (t *KeyFloat64) AddAt(thekey float64, i int) {
+	t.child.AddAt(float642key(thekey), i)
+}
+
+func (t *KeyFloat64) Build() []int {
+	return t.child.Build()
+}
+
+func (t *KeyFloat64) Optimize() {
+	t.child.Optimize()
+}
+
+func (t *KeyFloat64) Reset() bool {
+	return t.child.Reset()
+}
+
+func (t *KeyFloat64) Next() (float64, bool) {
+	a, b := t.child.Next()
+	return key2float64(a), b
+}
+
+func (t *KeyFloat64) Keys() []float64 {
+	return keys2float64(t.child.key)
+}
+
+// LowerBound returns the index of the first key >= thekey, or Len() if there isn't one.
+func (t *KeyFloat64) LowerBound(thekey float64) int {
+	return lowerBound(t.child.key, float642key(thekey))
+}
+
+// UpperBound returns the index of the first key > thekey, or Len() if there isn't one.
+func (t *KeyFloat64) UpperBound(thekey float64) int {
+	return upperBound(t.child.key, float642key(thekey))
+}
+
+// Floor returns the greatest key <= thekey and its index.
+func (t *KeyFloat64) Floor(thekey float64) (float64, int, bool) {
+	i := upperBound(t.child.key, float642key(thekey)) - 1
+	if i < 0 {
+		return 0, 0, false
+	}
+	return key2float64(t.child.key[i]), i, true
+}
+
+// Ceiling returns the smallest key >= thekey and its index.
+func (t *KeyFloat64) Ceiling(thekey float64) (float64, int, bool) {
+	i := lowerBound(t.child.key, float642key(thekey))
+	if i == len(t.child.key) {
+		return 0, 0, false
+	}
+	return key2float64(t.child.key[i]), i, true
+}
+
+// Between returns the span of indexes [from, to) of the keys >= lo and < hi.
+func (t *KeyFloat64) Between(lo, hi float64) (int, int) {
+	return t.child.Between(float642key(lo), float642key(hi))
+}
+
+// Range iterates over the keys >= lo and < hi, and their indexes.
+func (t *KeyFloat64) Range(lo, hi float64) iter.Seq2[float64, int] {
+	return rangeFloat(t.child.Range(float642key(lo), float642key(hi)), key2float64)
+}
+
+// Seek moves Next to the first key >= thekey. Returns false if there isn't one.
+func (t *KeyFloat64) Seek(thekey float64) bool {
+	return t.child.Seek(float642key(thekey))
+}
+
+func (t *KeyFloat64) Write(w custom.Interface) {
+	w.WriteUint64Variable(uint64(len(t.child.key)))
+	for _, v := range t.child.key {
+		w.WriteUint64(v)
+	}
+}
+
+func (t *KeyFloat64) Read(r *custom.Reader) {
+	l := int(r.ReadUint64Variable())
+	tmp := make([]uint64, l)
+	for i:=0; i<l; i++ {
+		tmp[i] = r.ReadUint64()
+	}
+	t.child.key = tmp
+}
+
+// ---------- KeyValFloat64 ----------
+
+// Add this to any struct to make it binary searchable.
+type KeyValFloat64 struct {
+ child KeyVal[uint64, int]
+}
+
+func (t *KeyValFloat64) Len() int {
+	return t.child.Len()
+}
+
+// Find returns the value based on the key.
+func (t *KeyValFloat64) Find(thekey float64) (int, bool) {
+	return t.child.Find(float642key(thekey))
+}
+
+func (t *KeyValFloat64) Update(thekey float64, fn func(int) int) bool {
+	return t.child.Update(float642key(thekey), fn)
+}
+
+func (t *KeyValFloat64) UpdateAll(fn func(int) int) {
+	t.child.UpdateAll(fn)
+}
+
+// Add adds this key and value to the index in the correct position, or replaces the value if the key already exists.
+func (t *KeyValFloat64) Add(thekey float64, theval int) bool {
+	return t.child.Add(float642key(thekey), theval)
+}
+
+// AddUnsorted adds this key to the end of the index for later building with Build.
+func (t *KeyValFloat64) AddUnsorted(thekey float64, theval int) {
+	t.child.AddUnsorted(float642key(thekey), theval)
+}
+
+func (t *KeyValFloat64) Build() {
+	t.child.Build()
+}
+
+func (t *KeyValFloat64) Optimize() {
+	t.child.Optimize()
+}
+
+func (t *KeyValFloat64) Reset() bool {
+	return t.child.Reset()
+}
+
+func (t *KeyValFloat64) Next() (float64, int, bool) {
+	a, b, c := t.child.Next()
+	return key2float64(a), b, c
+}
+
+func (t *KeyValFloat64) Keys() []float64 {
+	return keyvals2float64(t.child.key)
+}
+
+// LowerBound returns the index of the first key >= thekey, or Len() if there isn't one.
+func (t *KeyValFloat64) LowerBound(thekey float64) int {
+	return lowerBoundKeyVal(t.child.key, float642key(thekey))
+}
+
+// UpperBound returns the index of the first key > thekey, or Len() if there isn't one.
+func (t *KeyValFloat64) UpperBound(thekey float64) int {
+	return upperBoundKeyVal(t.child.key, float642key(thekey))
+}
+
+// Floor returns the greatest key <= thekey, its value and its index.
+func (t *KeyValFloat64) Floor(thekey float64) (float64, int, int, bool) {
+	i := upperBoundKeyVal(t.child.key, float642key(thekey)) - 1
+	if i < 0 {
+		return 0, 0, 0, false
+	}
+	return key2float64(t.child.key[i].V), t.child.key[i].K, i, true
+}
+
+// Ceiling returns the smallest key >= thekey, its value and its index.
+func (t *KeyValFloat64) Ceiling(thekey float64) (float64, int, int, bool) {
+	i := lowerBoundKeyVal(t.child.key, float642key(thekey))
+	if i == len(t.child.key) {
+		return 0, 0, 0, false
+	}
+	return key2float64(t.child.key[i].V), t.child.key[i].K, i, true
+}
+
+// Between returns the span of indexes [from, to) of the keys >= lo and < hi.
+func (t *KeyValFloat64) Between(lo, hi float64) (int, int) {
+	return t.child.Between(float642key(lo), float642key(hi))
+}
+
+// Range iterates over the keys >= lo and < hi, and their values.
+func (t *KeyValFloat64) Range(lo, hi float64) iter.Seq2[float64, int] {
+	return rangeFloat(t.child.Range(float642key(lo), float642key(hi)), key2float64)
+}
+
+// Seek moves Next to the first key >= thekey. Returns false if there isn't one.
+func (t *KeyValFloat64) Seek(thekey float64) bool {
+	return t.child.Seek(float642key(thekey))
+}
+
+func (t *KeyValFloat64) Write(w custom.Interface) {
+	writeKeyValFloat64(w, t.child.key)
+}
+
+func (t *KeyValFloat64) Read(r *custom.Reader) {
+	t.child.key = readKeyValFloat64(r)
+}
+
+// ---------- CounterFloat64 ----------
+
+// Add this to any struct to make it binary searchable.
+type CounterFloat64 struct {
+ child Counter[uint64, int]
+}
+
+func (t *CounterFloat64) KeyFloat64() *KeyFloat64 {
+	obj := new(KeyFloat64)
+	obj.child = *t.child.Key()
+	return obj
+}
+
+func (t *CounterFloat64) KeyValFloat64() *KeyValFloat64 {
+	obj := new(KeyValFloat64)
+	obj.child = *t.child.KeyVal()
+	return obj
+}
+
+// Len is only accurate after Build.
+func (t *CounterFloat64) Len() int {
+	return t.child.Len()
+}
+
+// Find returns the frequency based on the key.
+func (t *CounterFloat64) Find(thekey float64) (int, bool) {
+	return t.child.Find(float642key(thekey))
+}
+
+func (t *CounterFloat64) Update(thekey float64, fn func(int) int) bool {
+	return t.child.Update(float642key(thekey), fn)
+}
+
+func (t *CounterFloat64) UpdateAll(fn func(int) int) {
+	t.child.UpdateAll(fn)
+}
+
+// Add adds this key to the end of the index for later building with Build.
+func (t *CounterFloat64) Add(thekey float64, theval int) {
+	t.child.Add(float642key(thekey), theval)
+}
+
+func (t *CounterFloat64) Build() {
+	t.child.Build()
+}
+
+func (t *CounterFloat64) Optimize() {
+	t.child.Optimize()
+}
+
+func (t *CounterFloat64) Reset() bool {
+	return t.child.Reset()
+}
+
+func (t *CounterFloat64) Next() (float64, int, bool) {
+	a, b, c := t.child.Next()
+	return key2float64(a), b, c
+}
+
+func (t *CounterFloat64) Keys() []float64 {
+	return keyvals2float64(t.child.key)
+}
+
+// LowerBound returns the index of the first key >= thekey, or Len() if there isn't one.
+func (t *CounterFloat64) LowerBound(thekey float64) int {
+	return lowerBoundKeyVal(t.child.key, float642key(thekey))
+}
+
+// UpperBound returns the index of the first key > thekey, or Len() if there isn't one.
+func (t *CounterFloat64) UpperBound(thekey float64) int {
+	return upperBoundKeyVal(t.child.key, float642key(thekey))
+}
+
+// Floor returns the greatest key <= thekey, its frequency and its index.
+func (t *CounterFloat64) Floor(thekey float64) (float64, int, int, bool) {
+	i := upperBoundKeyVal(t.child.key, float642key(thekey)) - 1
+	if i < 0 {
+		return 0, 0, 0, false
+	}
+	return key2float64(t.child.key[i].V), t.child.key[i].K, i, true
+}
+
+// Ceiling returns the smallest key >= thekey, its frequency and its index.
+func (t *CounterFloat64) Ceiling(thekey float64) (float64, int, int, bool) {
+	i := lowerBoundKeyVal(t.child.key, float642key(thekey))
+	if i == len(t.child.key) {
+		return 0, 0, 0, false
+	}
+	return key2float64(t.child.key[i].V), t.child.key[i].K, i, true
+}
+
+// Between returns the span of indexes [from, to) of the keys >= lo and < hi.
+func (t *CounterFloat64) Between(lo, hi float64) (int, int) {
+	return t.child.Between(float642key(lo), float642key(hi))
+}
+
+// Range iterates over the keys >= lo and < hi, and their frequencies.
+func (t *CounterFloat64) Range(lo, hi float64) iter.Seq2[float64, int] {
+	return rangeFloat(t.child.Range(float642key(lo), float642key(hi)), key2float64)
+}
+
+// Seek moves Next to the first key >= thekey. Returns false if there isn't one.
+func (t *CounterFloat64) Seek(thekey float64) bool {
+	return t.child.Seek(float642key(thekey))
+}
+
+func (t *CounterFloat64) Write(w custom.Interface) {
+	writeKeyValFloat64(w, t.child.key)
+}
+
+func (t *CounterFloat64) Read(r *custom.Reader) {
+	t.child.key = readKeyValFloat64(r)
+}
+
+// ---------- float64 helpers ----------
+
+// rangeFloat converts the keys of a Range on the child back to floats.
+func rangeFloat[F float32 | float64, U uint32 | uint64, V any](seq iter.Seq2[U, V], conv func(U) F) iter.Seq2[F, V] {
+	return func(yield func(F, V) bool) {
+		for k, v := range seq {
+			if !yield(conv(k), v) {
+				return
+			}
+		}
+	}
+}
+
+func keys2float64(key []uint64) []float64 {
+	keys := make([]float64, len(key))
+	for i, v := range key {
+		keys[i] = key2float64(v)
+	}
+	return keys
+}
+
+func keyvals2float64(key []sortOrdered.KeyVal[uint64, int]) []float64 {
+	keys := make([]float64, len(key))
+	for i, v := range key {
+		keys[i] = key2float64(v.V)
+	}
+	return keys
+}
+
+func writeKeyValFloat64(w custom.Interface, key []sortOrdered.KeyVal[uint64, int]) {
+	w.WriteUint64Variable(uint64(len(key)))
+	for _, kv := range key {
+		w.WriteUint64Variable(uint64(kv.K))
+		v := kv.V
+		w.WriteUint64(v)
+	}
+}
+
+func readKeyValFloat64(r *custom.Reader) []sortOrdered.KeyVal[uint64, int] {
+	l := int(r.ReadUint64Variable())
+	tmp := make([]sortOrdered.KeyVal[uint64, int], l)
+	for i:=0; i<l; i++ {
+		k := int(r.ReadUint64Variable())
+		tmp[i] = sortOrdered.KeyVal[uint64, int]{k, r.ReadUint64()}
+	}
+	return tmp
+}
+
+// ---------- KeyFloat32 ----------
+
+// Add this to any struct to make it binary searchable.
+type KeyFloat32 struct {
+ child Key[uint32]
+}
+
+func (t *KeyFloat32) Len() int {
+	return t.child.Len()
+}
+
+// Find returns the index based on the key.
+func (t *KeyFloat32) Find(thekey float32) (int, bool) {
+	return t.child.Find(float322key(thekey))
+}
+
+// Add adds this key to the index in the correct position, if it does not already exist.
+func (t *KeyFloat32) Add(thekey float32) (int, bool) {
+	return t.child.Add(float322key(thekey))
+}
+
+// AddUnsorted adds this key to the end of the index for later building with Build.
+func (t *KeyFloat32) AddUnsorted(thekey float32) {
+	t.child.AddUnsorted(float322key(thekey))
+}
+
+// AddAt adds this key to the index in this exact position, so it does not require later rebuilding.
+func (t *KeyFloat32) AddAt(thekey float32, i int) {
+	t.child.AddAt(float322key(thekey), i)
+}
+
+func (t *KeyFloat32) Build() []int {
+	return t.child.Build()
+}
+
+func (t *KeyFloat32) Optimize() {
+	t.child.Optimize()
+}
+
+func (t *KeyFloat32) Reset() bool {
+	return t.child.Reset()
+}
+
+func (t *KeyFloat32) Next() (float32, bool) {
+	a, b := t.child.Next()
+	return key2float32(a), b
+}
+
+func (t *KeyFloat32) Keys() []float32 {
+	return keys2float32(t.child.key)
+}
+
+// LowerBound returns the index of the first key >= thekey, or Len() if there isn't one.
+func (t *KeyFloat32) LowerBound(thekey float32) int {
+	return lowerBound(t.child.key, float322key(thekey))
+}
+
+// UpperBound returns the index of the first key > thekey, or Len() if there isn't one.
+func (t *KeyFloat32) UpperBound(thekey float32) int {
+	return upperBound(t.child.key, float322key(thekey))
+}
+
+// Floor returns the greatest key <= thekey and its index.
+func (t *KeyFloat32) Floor(thekey float32) (float32, int, bool) {
+	i := upperBound(t.child.key, float322key(thekey)) - 1
+	if i < 0 {
+		return 0, 0, false
+	}
+	return key2float32(t.child.key[i]), i, true
+}
+
+// Ceiling returns the smallest key >= thekey and its index.
+func (t *KeyFloat32) Ceiling(thekey float32) (float32, int, bool) {
+	i := lowerBound(t.child.key, float322key(thekey))
+	if i == len(t.child.key) {
+		return 0, 0, false
+	}
+	return key2float32(t.child.key[i]), i, true
+}
+
+// Between returns the span of indexes [from, to) of the keys >= lo and < hi.
+func (t *KeyFloat32) Between(lo, hi float32) (int, int) {
+	return t.child.Between(float322key(lo), float322key(hi))
+}
+
+// Range iterates over the keys >= lo and < hi, and their indexes.
+func (t *KeyFloat32) Range(lo, hi float32) iter.Seq2[float32, int] {
+	return rangeFloat(t.child.Range(float322key(lo), float322key(hi)), key2float32)
+}
+
+// Seek moves Next to the first key >= thekey. Returns false if there isn't one.
+func (t *KeyFloat32) Seek(thekey float32) bool {
+	return t.child.Seek(float322key(thekey))
+}
+
+func (t *KeyFloat32) Write(w custom.Interface) {
+	w.WriteUint64Variable(uint64(len(t.child.key)))
+	for _, v := range t.child.key {
+		w.WriteUint16(uint16(v >> 16))
+		w.WriteUint16(uint16(v))
+	}
+}
+
+func (t *KeyFloat32) Read(r *custom.Reader) {
+	l := int(r.ReadUint64Variable())
+	tmp := make([]uint32, l)
+	for i:=0; i<l; i++ {
+		tmp[i] = (uint32(r.ReadUint16()) << 16) | uint32(r.ReadUint16())
+	}
+	t.child.key = tmp
+}
+
+// ---------- KeyValFloat32 ----------
+
+// Add this to any struct to make it binary searchable.
+type KeyValFloat32 struct {
+ child KeyVal[uint32, int]
+}
+
+func (t *KeyValFloat32) Len() int {
+	return t.child.Len()
+}
+
+// Find returns the value based on the key.
+func (t *KeyValFloat32) Find(thekey float32) (int, bool) {
+	return t.child.Find(float322key(thekey))
+}
+
+func (t *KeyValFloat32) Update(thekey float32, fn func(int) int) bool {
+	return t.child.Update(float322key(thekey), fn)
+}
+
+func (t *KeyValFloat32) UpdateAll(fn func(int) int) {
+	t.child.UpdateAll(fn)
+}
+
+// Add adds this key and value to the index in the correct position, or replaces the value if the key already exists.
+func (t *KeyValFloat32) Add(thekey float32, theval int) bool {
+	return t.child.Add(float322key(thekey), theval)
+}
+
+// AddUnsorted adds this key to the end of the index for later building with Build.
+func (t *KeyValFloat32) AddUnsorted(thekey float32, theval int) {
+	t.child.AddUnsorted(float322key(thekey), theval)
+}
+
+func (t *KeyValFloat32) Build() {
+	t.child.Build()
+}
+
+func (t *KeyValFloat32) Optimize() {
+	t.child.Optimize()
+}
+
+func (t *KeyValFloat32) Reset() bool {
+	return t.child.Reset()
+}
+
+func (t *KeyValFloat32) Next() (float32, int, bool) {
+	a, b, c := t.child.Next()
+	return key2float32(a), b, c
+}
+
+func (t *KeyValFloat32) Keys() []float32 {
+	return keyvals2float32(t.child.key)
+}
+
+// LowerBound returns the index of the first key >= thekey, or Len() if there isn't one.
+func (t *KeyValFloat32) LowerBound(thekey float32) int {
+	return lowerBoundKeyVal(t.child.key, float322key(thekey))
+}
+
+// UpperBound returns the index of the first key > thekey, or Len() if there isn't one.
+func (t *KeyValFloat32) UpperBound(thekey float32) int {
+	return upperBoundKeyVal(t.child.key, float322key(thekey))
+}
+
+// Floor returns the greatest key <= thekey, its value and its index.
+func (t *KeyValFloat32) Floor(thekey float32) (float32, int, int, bool) {
+	i := upperBoundKeyVal(t.child.key, float322key(thekey)) - 1
+	if i < 0 {
+		return 0, 0, 0, false
+	}
+	return key2float32(t.child.key[i].V), t.child.key[i].K, i, true
+}
+
+// Ceiling returns the smallest key >= thekey, its value and its index.
+func (t *KeyValFloat32) Ceiling(thekey float32) (float32, int, int, bool) {
+	i := lowerBoundKeyVal(t.child.key, float322key(thekey))
+	if i == len(t.child.key) {
+		return 0, 0, 0, false
+	}
+	return key2float32(t.child.key[i].V), t.child.key[i].K, i, true
+}
+
+// Between returns the span of indexes [from, to) of the keys >= lo and < hi.
+func (t *KeyValFloat32) Between(lo, hi float32) (int, int) {
+	return t.child.Between(float322key(lo), float322key(hi))
+}
+
+// Range iterates over the keys >= lo and < hi, and their values.
+func (t *KeyValFloat32) Range(lo, hi float32) iter.Seq2[float32, int] {
+	return rangeFloat(t.child.Range(float322key(lo), float322key(hi)), key2float32)
+}
+
+// Seek moves Next to the first key >= thekey. Returns false if there isn't one.
+func (t *KeyValFloat32) Seek(thekey float32) bool {
+	return t.child.Seek(float322key(thekey))
+}
+
+func (t *KeyValFloat32) Write(w custom.Interface) {
+	writeKeyValFloat32(w, t.child.key)
+}
+
+func (t *KeyValFloat32) Read(r *custom.Reader) {
+	t.child.key = readKeyValFloat32(r)
+}
+
+// ---------- CounterFloat32 ----------
+
+// Add this to any struct to make it binary searchable.
+type CounterFloat32 struct {
+ child Counter[uint32, int]
+}
+
+func (t *CounterFloat32) KeyFloat32() *KeyFloat32 {
+	obj := new(KeyFloat32)
+	obj.child = *t.child.Key()
+	return obj
+}
+
+func (t *CounterFloat32) KeyValFloat32() *KeyValFloat32 {
+	obj := new(KeyValFloat32)
+	obj.child = *t.child.KeyVal()
+	return obj
+}
+
+// Len is only accurate after Build.
+func (t *CounterFloat32) Len() int {
+	return t.child.Len()
+}
+
+// Find returns the frequency based on the key.
+func (t *CounterFloat32) Find(thekey float32) (int, bool) {
+	return t.child.Find(float322key(thekey))
+}
+
+func (t *CounterFloat32) Update(thekey float32, fn func(int) int) bool {
+	return t.child.Update(float322key(thekey), fn)
+}
+
+func (t *CounterFloat32) UpdateAll(fn func(int) int) {
+	t.child.UpdateAll(fn)
+}
+
+// Add adds this key to the end of the index for later building with Build.
+func (t *CounterFloat32) Add(thekey float32, theval int) {
+	t.child.Add(float322key(thekey), theval)
+}
+
+func (t *CounterFloat32) Build() {
+	t.child.Build()
+}
+
+func (t *CounterFloat32) Optimize() {
+	t.child.Optimize()
+}
+
+func (t *CounterFloat32) Reset() bool {
+	return t.child.Reset()
+}
+
+func (t *CounterFloat32) Next() (float32, int, bool) {
+	a, b, c := t.child.Next()
+	return key2float32(a), b, c
+}
+
+func (t *CounterFloat32) Keys() []float32 {
+	return keyvals2float32(t.child.key)
+}
+
+// LowerBound returns the index of the first key >= thekey, or Len() if there isn't one.
+func (t *CounterFloat32) LowerBound(thekey float32) int {
+	return lowerBoundKeyVal(t.child.key, float322key(thekey))
+}
+
+// UpperBound returns the index of the first key > thekey, or Len() if there isn't one.
+func (t *CounterFloat32) UpperBound(thekey float32) int {
+	return upperBoundKeyVal(t.child.key, float322key(thekey))
+}
+
+// Floor returns the greatest key <= thekey, its frequency and its index.
+func (t *CounterFloat32) Floor(thekey float32) (float32, int, int, bool) {
+	i := upperBoundKeyVal(t.child.key, float322key(thekey)) - 1
+	if i < 0 {
+		return 0, 0, 0, false
+	}
+	return key2float32(t.child.key[i].V), t.child.key[i].K, i, true
+}
+
+// Ceiling returns the smallest key >= thekey, its frequency and its index.
+func (t *CounterFloat32) Ceiling(thekey float32) (float32, int, int, bool) {
+	i := lowerBoundKeyVal(t.child.key, float322key(thekey))
+	if i == len(t.child.key) {
+		return 0, 0, 0, false
+	}
+	return key2float32(t.child.key[i].V), t.child.key[i].K, i, true
+}
+
+// Between returns the span of indexes [from, to) of the keys >= lo and < hi.
+func (t *CounterFloat32) Between(lo, hi float32) (int, int) {
+	return t.child.Between(float322key(lo), float322key(hi))
+}
+
+// Range iterates over the keys >= lo and < hi, and their frequencies.
+func (t *CounterFloat32) Range(lo, hi float32) iter.Seq2[float32, int] {
+	return rangeFloat(t.child.Range(float322key(lo), float322key(hi)), key2float32)
+}
+
+// Seek moves Next to the first key >= thekey. Returns false if there isn't one.
+func (t *CounterFloat32) Seek(thekey float32) bool {
+	return t.child.Seek(float322key(thekey))
+}
+
+func (t *CounterFloat32) Write(w custom.Interface) {
+	writeKeyValFloat32(w, t.child.key)
+}
+
+func (t *CounterFloat32) Read(r *custom.Reader) {
+	t.child.key = readKeyValFloat32(r)
+}
+
+// ---------- float32 helpers ----------
+
+func keys2float32(key []uint32) []float32 {
+	keys := make([]float32, len(key))
+	for i, v := range key {
+		keys[i] = key2float32(v)
+	}
+	return keys
+}
+
+func keyvals2float32(key []sortOrdered.KeyVal[uint32, int]) []float32 {
+	keys := make([]float32, len(key))
+	for i, v := range key {
+		keys[i] = key2float32(v.V)
+	}
+	return keys
+}
+
+func writeKeyValFloat32(w custom.Interface, key []sortOrdered.KeyVal[uint32, int]) {
+	w.WriteUint64Variable(uint64(len(key)))
+	for _, kv := range key {
+		w.WriteUint64Variable(uint64(kv.K))
+		v := kv.V
+		w.WriteUint16(uint16(v >> 16))
+		w.WriteUint16(uint16(v))
+	}
+}
+
+func readKeyValFloat32(r *custom.Reader) []sortOrdered.KeyVal[uint32, int] {
+	l := int(r.ReadUint64Variable())
+	tmp := make([]sortOrdered.KeyVal[uint32, int], l)
+	for i:=0; i<l; i++ {
+		k := int(r.ReadUint64Variable())
+		tmp[i] = sortOrdered.KeyVal[uint32, int]{k, (uint32(r.ReadUint16()) << 16) | uint32(r.ReadUint16())}
+	}
+	return tmp
+}
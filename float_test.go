@@ -0,0 +1,104 @@
+package binsearch
+
+import (
+ "math"
+ "testing"
+)
+
+// sameFloat reports whether a and b are the same key in the total order, where every NaN is the same key.
+func sameFloat(a, b float64) bool {
+	if a != a || b != b {
+		return a != a && b != b
+	}
+	return math.Float64bits(a) == math.Float64bits(b)
+}
+
+func TestFloatOrder(t *testing.T) {
+	negz := math.Copysign(0, -1)
+	vals := []float64{math.NaN(), math.Inf(1), 2.5, 0, negz, -1e-30, -3, math.Inf(-1), 1e30}
+	want := []float64{math.Inf(-1), -3, -1e-30, negz, 0, 2.5, 1e30, math.Inf(1), math.NaN()}
+	k := new(KeyFloat64)
+	k32 := new(KeyFloat32)
+	for _, v := range vals {
+		k.AddUnsorted(v)
+		k32.AddUnsorted(float32(v))
+	}
+	k.Build()
+	k32.Build()
+	keys := k.Keys()
+	keys32 := k32.Keys()
+	if len(keys) != len(want) || len(keys32) != len(want) {
+		t.Fatal(keys, keys32)
+	}
+	for i := range want {
+		if !sameFloat(keys[i], want[i]) {
+			t.Fatalf(`KeyFloat64 keys %v, want %v`, keys, want)
+		}
+		if !sameFloat(float64(keys32[i]), float64(float32(want[i]))) {
+			t.Fatalf(`KeyFloat32 keys %v, want %v`, keys32, want)
+		}
+	}
+	if i, ok := k.Find(negz); !ok || i != 3 {
+		t.Fatal(`-0`, i, ok)
+	}
+	if i, ok := k.Find(0); !ok || i != 4 {
+		t.Fatal(`+0`, i, ok)
+	}
+	if i, ok := k.Find(-math.NaN()); !ok || i != 8 {
+		t.Fatal(`NaN`, i, ok)
+	}
+	if k.LowerBound(math.NaN()) != 8 || k.UpperBound(math.NaN()) != 9 || k.LowerBound(-1) != 2 || k.UpperBound(math.Inf(-1)) != 1 {
+		t.Fatal(`bounds`)
+	}
+	if f, i, ok := k.Floor(1); !ok || f != 0 || i != 4 || math.Signbit(f) {
+		t.Fatal(`Floor`, f, i, ok)
+	}
+	if f, i, ok := k.Ceiling(1); !ok || f != 2.5 || i != 5 {
+		t.Fatal(`Ceiling`, f, i, ok)
+	}
+}
+
+func TestFloatWrite(t *testing.T) {
+	vals := []float64{math.NaN(), math.Inf(1), 2.5, 0, math.Copysign(0, -1), -3, math.Inf(-1), 1e300, math.SmallestNonzeroFloat64}
+	k := new(KeyFloat64)
+	k32 := new(KeyFloat32)
+	c := new(CounterFloat64)
+	for _, v := range vals {
+		k.AddUnsorted(v)
+		k32.AddUnsorted(float32(v))
+		c.Add(v, 1)
+	}
+	// NaNs with a different sign or payload are the same key.
+	c.Add(-math.NaN(), 1)
+	c.Add(math.Float64frombits(0xFFF8000000000000), 1)
+	k.Build()
+	k32.Build()
+	c.Build()
+	if n, ok := c.Find(math.NaN()); !ok || n != 3 {
+		t.Fatal(`NaN`, n, ok)
+	}
+	k2 := new(KeyFloat64)
+	roundTrip(t, k.Write, k2.Read)
+	k322 := new(KeyFloat32)
+	roundTrip(t, k32.Write, k322.Read)
+	c2 := new(CounterFloat64)
+	roundTrip(t, c.Write, c2.Read)
+	kv := c2.KeyValFloat64()
+	kv2 := new(KeyValFloat64)
+	roundTrip(t, kv.Write, kv2.Read)
+	for _, v := range vals {
+		a, _ := k.Find(v)
+		if b, ok := k2.Find(v); !ok || a != b {
+			t.Fatal(`KeyFloat64`, v)
+		}
+		if _, ok := k322.Find(float32(v)); !ok {
+			t.Fatal(`KeyFloat32`, v)
+		}
+		if n, ok := kv2.Find(v); !ok || (n != 1 && v == v) {
+			t.Fatal(`KeyValFloat64`, v, n)
+		}
+	}
+	if i, _ := k2.Find(math.Copysign(0, -1)); i != 2 {
+		t.Fatal(`-0 after Read`, i)
+	}
+}
@@ -0,0 +1,188 @@
+package binsearch
+
+import (
+ "bytes"
+ "sort"
+)
+
+/*
+	FindFuzzy returns all the keys within a Levenshtein distance of a key, e.g. for spell checking.
+	Only the length classes within maxDist of the length of the key are searched. Within a class the keys are sorted,
+	so each key reuses the rows of the edit distance table for the prefix it shares with the key before it, and as soon as
+	a prefix is more than maxDist from the key every key starting with that prefix is skipped with a binary search.
+*/
+
+// eachFuzzy calls fn with the index, stored key, value and distance of every key within maxDist of query.
+// Only classes with keys from minLen to maxLen bytes long are searched. decode gives the units that the distance is counted in from a stored key,
+// and stored gives the stored bytes for a prefix of them.
+func eachFuzzy[E byte | rune](src prefixSource, query []E, maxDist, minLen, maxLen int, decode func([]byte) []E, stored func([]E) []byte, fn func(int, []byte, int, int)) {
+	width := len(query) + 1
+	rows := [][]int{make([]int, width)}
+	for i := range rows[0] {
+		rows[0][i] = i
+	}
+	var start int
+	for on:=0; on<65; on++ {
+		n := src.classLen(on)
+		l := (on >> 3) * 8 + (on & 7) + 1 // the length of the keys in this class
+		if n == 0 || (on < 64 && (l < minLen || (l > maxLen && on > 0))) || (on == 64 && maxLen <= 64) { // class 0 also has the empty key
+			start += n
+			continue
+		}
+		var prev []E
+		var valid int // the rows that are filled for prev
+		for i:=0; i<n; {
+			key, val := src.classEntry(on, i)
+			word := decode(key)
+			if d := len(word) - len(query); d > maxDist || d < -maxDist {
+				i++
+				continue
+			}
+			j := 0
+			for j < valid && j < len(word) && word[j] == prev[j] {
+				j++
+			}
+			pruned := false
+			for ; j<len(word); j++ {
+				if j + 1 == len(rows) {
+					rows = append(rows, make([]int, width))
+				}
+				last, row := rows[j], rows[j+1]
+				row[0] = j + 1
+				best := row[0]
+				for q:=1; q<width; q++ {
+					cost := last[q-1]
+					if query[q-1] != word[j] {
+						cost++
+					}
+					if v := last[q] + 1; v < cost {
+						cost = v
+					}
+					if v := row[q-1] + 1; v < cost {
+						cost = v
+					}
+					row[q] = cost
+					if cost < best {
+						best = cost
+					}
+				}
+				if best > maxDist {
+					pruned = true
+					break
+				}
+			}
+			prev = word
+			if !pruned {
+				valid = len(word)
+				if d := rows[len(word)][width-1]; d <= maxDist {
+					fn(start + i, key, val, d)
+				}
+				i++
+				continue
+			}
+			// Nothing starting with word[0:j+1] can be within maxDist
+			valid = j + 1
+			prefix := stored(word[0:j+1])
+			i++
+			if on == 64 {
+				i += sort.Search(n - i, func(x int) bool {
+					key, _ := src.classEntry(64, i + x)
+					return !bytes.HasPrefix(key, prefix)
+				})
+			} else {
+				var hi [64]byte
+				copy(hi[:], prefix)
+				for x:=len(prefix); x<l; x++ {
+					hi[x] = 255
+				}
+				high, _ := splitKey(hi[0:l])
+				i += sort.Search(n - i, func(x int) bool {
+					return src.classCompare(on, i + x, high) > 0
+				})
+			}
+		}
+		start += n
+	}
+}
+
+func sameBytes(b []byte) []byte {
+	return b
+}
+
+// fuzzyBytes is FindFuzzy for the bytes types, returning the keys, the index or value of each and their distances.
+func fuzzyBytes(src prefixSource, thekey []byte, maxDist int, index bool) ([][]byte, []int, []int) {
+	var keys [][]byte
+	var vals, dists []int
+	eachFuzzy(src, thekey, maxDist, len(thekey) - maxDist, len(thekey) + maxDist, sameBytes, sameBytes, func(i int, key []byte, val int, d int) {
+		if index {
+			val = i
+		}
+		keys = append(keys, key)
+		vals = append(vals, val)
+		dists = append(dists, d)
+	})
+	return keys, vals, dists
+}
+
+// fuzzyRunes is FindFuzzy for the runes types, counting the distance in runes.
+func fuzzyRunes(src prefixSource, thekey []rune, maxDist int, legacy bool, index bool) ([][]rune, []int, []int) {
+	var keys [][]rune
+	var vals, dists []int
+	decode := func(b []byte) []rune {
+		return decodeRunes(b, legacy)
+	}
+	stored := func(r []rune) []byte {
+		return encodeRunes(r, legacy)
+	}
+	// Each rune is stored in 1 to 4 bytes
+	eachFuzzy(src, thekey, maxDist, len(thekey) - maxDist, (len(thekey) + maxDist) * 4, decode, stored, func(i int, key []byte, val int, d int) {
+		if index {
+			val = i
+		}
+		keys = append(keys, decode(key))
+		vals = append(vals, val)
+		dists = append(dists, d)
+	})
+	return keys, vals, dists
+}
+
+// ---------- KeyBytes ----------
+
+// FindFuzzy returns the keys within Levenshtein distance maxDist of thekey, their indexes and their distances, in the order of Next.
+func (t *KeyBytes) FindFuzzy(thekey []byte, maxDist int) ([][]byte, []int, []int) {
+	return fuzzyBytes(t, thekey, maxDist, true)
+}
+
+// ---------- KeyValBytes ----------
+
+// FindFuzzy returns the keys within Levenshtein distance maxDist of thekey, their values and their distances, in the order of Next.
+func (t *KeyValBytes) FindFuzzy(thekey []byte, maxDist int) ([][]byte, []int, []int) {
+	return fuzzyBytes(t, thekey, maxDist, false)
+}
+
+// ---------- CounterBytes ----------
+
+// FindFuzzy returns the keys within Levenshtein distance maxDist of thekey, their frequencies and their distances, in the order of Next.
+func (t *CounterBytes) FindFuzzy(thekey []byte, maxDist int) ([][]byte, []int, []int) {
+	return fuzzyBytes(t, thekey, maxDist, false)
+}
+
+// ---------- Runes ----------
+
+// FindFuzzy returns the keys within Levenshtein distance maxDist of thekey, their indexes and their distances, in the order of Next.
+// The distance is counted in runes.
+func (t *KeyRunes) FindFuzzy(thekey []rune, maxDist int) ([][]rune, []int, []int) {
+	return fuzzyRunes(&t.child, thekey, maxDist, t.legacy, true)
+}
+
+// FindFuzzy returns the keys within Levenshtein distance maxDist of thekey, their values and their distances, in the order of Next.
+// The distance is counted in runes.
+func (t *KeyValRunes) FindFuzzy(thekey []rune, maxDist int) ([][]rune, []int, []int) {
+	return fuzzyRunes(&t.child, thekey, maxDist, t.legacy, false)
+}
+
+// FindFuzzy returns the keys within Levenshtein distance maxDist of thekey, their frequencies and their distances, in the order of Next.
+// The distance is counted in runes.
+func (t *CounterRunes) FindFuzzy(thekey []rune, maxDist int) ([][]rune, []int, []int) {
+	return fuzzyRunes(&t.child, thekey, maxDist, t.legacy, false)
+}
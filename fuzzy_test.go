@@ -0,0 +1,145 @@
+package binsearch
+
+import (
+ "fmt"
+ "math/rand"
+ "testing"
+)
+
+// levenshtein is the textbook distance, the brute force for FindFuzzy.
+func levenshtein[E comparable](a, b []E) int {
+	row := make([]int, len(b) + 1)
+	for j := range row {
+		row[j] = j
+	}
+	for i:=1; i<=len(a); i++ {
+		prev := row[0]
+		row[0] = i
+		for j:=1; j<=len(b); j++ {
+			cur := row[j]
+			d := prev
+			if a[i-1] != b[j-1] {
+				d++
+			}
+			d = min(d, row[j] + 1, row[j-1] + 1)
+			row[j] = d
+			prev = cur
+		}
+	}
+	return row[len(b)]
+}
+
+// fuzzyWords returns distinct random words over alphabet, mostly short with some past the 64 byte overflow, and the empty word.
+func fuzzyWords[E byte | rune](rnd *rand.Rand, alphabet []E, n int) [][]E {
+	seen := map[string]bool{fmt.Sprint([]E{}): true}
+	words := [][]E{{}}
+	for len(words) < n {
+		w := fuzzyWord(rnd, alphabet)
+		if !seen[fmt.Sprint(w)] {
+			seen[fmt.Sprint(w)] = true
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+func fuzzyWord[E byte | rune](rnd *rand.Rand, alphabet []E) []E {
+	l := rnd.Intn(12)
+	if rnd.Intn(20) == 0 {
+		l = 60 + rnd.Intn(10)
+	}
+	w := make([]E, l)
+	for j := range w {
+		w[j] = alphabet[rnd.Intn(len(alphabet))]
+	}
+	return w
+}
+
+func TestKeyBytesFuzzy(t *testing.T) {
+	rnd := rand.New(rand.NewSource(22))
+	words := fuzzyWords(rnd, []byte(`abcd`), 3000)
+	k := new(KeyBytes)
+	kv := new(KeyValBytes)
+	c := new(CounterBytes)
+	for i, w := range words {
+		k.AddUnsorted(w)
+		kv.AddUnsorted(w, i)
+		c.Add(w, 1)
+	}
+	k.Build()
+	kv.Build()
+	c.Build()
+	for trial:=0; trial<200; trial++ {
+		q := fuzzyWord(rnd, []byte(`abcd`))
+		if trial % 10 == 0 {
+			q = words[rnd.Intn(len(words))]
+		}
+		d := rnd.Intn(4)
+		want := make(map[string]int)
+		for _, w := range words {
+			if n := levenshtein(w, q); n <= d {
+				want[string(w)] = n
+			}
+		}
+		got, idx, dist := k.FindFuzzy(q, d)
+		if len(got) != len(want) {
+			t.Fatalf(`FindFuzzy(%q, %d) found %d keys, want %d`, q, d, len(got), len(want))
+		}
+		for i, w := range got {
+			if n, ok := want[string(w)]; !ok || n != dist[i] {
+				t.Fatalf(`FindFuzzy(%q, %d) gave %q at distance %d`, q, d, w, dist[i])
+			}
+			if j, _ := k.Find(w); j != idx[i] || (i > 0 && idx[i-1] >= idx[i]) {
+				t.Fatalf(`FindFuzzy(%q, %d) gave index %d for %q`, q, d, idx[i], w)
+			}
+		}
+		got, vals, _ := kv.FindFuzzy(q, d)
+		for i, w := range got {
+			if string(words[vals[i]]) != string(w) {
+				t.Fatalf(`KeyValBytes.FindFuzzy gave %q with value %d`, w, vals[i])
+			}
+		}
+		if got, _, _ := c.FindFuzzy(q, d); len(got) != len(want) {
+			t.Fatalf(`CounterBytes.FindFuzzy(%q, %d) found %d keys, want %d`, q, d, len(got), len(want))
+		}
+	}
+	if got, _, _ := k.FindFuzzy(nil, 0); len(got) != 1 || len(got[0]) != 0 {
+		t.Fatal(`the empty key`, got)
+	}
+}
+
+func TestKeyRunesFuzzy(t *testing.T) {
+	rnd := rand.New(rand.NewSource(23))
+	alphabet := []rune(`aé中😀`)
+	words := fuzzyWords(rnd, alphabet, 2000)
+	k := new(KeyRunes)
+	c := new(CounterRunes)
+	for _, w := range words {
+		k.AddUnsorted(w)
+		c.Add(w, 1)
+	}
+	k.Build()
+	c.Build()
+	for trial:=0; trial<100; trial++ {
+		q := fuzzyWord(rnd, alphabet)
+		d := rnd.Intn(3)
+		want := make(map[string]int)
+		for _, w := range words {
+			if n := levenshtein(w, q); n <= d {
+				want[string(w)] = n
+			}
+		}
+		got, _, dist := k.FindFuzzy(q, d)
+		if len(got) != len(want) {
+			t.Fatalf(`FindFuzzy(%q, %d) found %d keys, want %d`, string(q), d, len(got), len(want))
+		}
+		for i, w := range got {
+			if n, ok := want[string(w)]; !ok || n != dist[i] {
+				t.Fatalf(`FindFuzzy(%q, %d) gave %q at distance %d`, string(q), d, string(w), dist[i])
+			}
+		}
+		if got, _, _ := c.FindFuzzy(q, d); len(got) != len(want) {
+			t.Fatalf(`CounterRunes.FindFuzzy(%q, %d) found %d keys, want %d`, string(q), d, len(got), len(want))
+		}
+	}
+}
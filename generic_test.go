@@ -0,0 +1,134 @@
+package binsearch
+
+import (
+ "bytes"
+ "testing"
+ "github.com/AlasdairF/Sort/IntUint64"
+ "github.com/AlasdairF/Custom"
+)
+
+// roundTrip writes with write, reads it back with read and checks that everything written was read.
+func roundTrip(t *testing.T, write func(custom.Interface), read func(*custom.Reader)) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := custom.NewWriter(&buf)
+	write(w)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r := custom.NewReader(&buf, 20480)
+	read(r)
+	if err := r.EOF(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type testID uint32
+
+func TestKey(t *testing.T) {
+	k := new(KeyUint64)
+	for _, v := range []uint64{5, 3, 9, 1} {
+		k.AddUnsorted(v)
+	}
+	imap := k.Build()
+	if keys := k.Keys(); len(keys) != 4 || keys[0] != 1 || keys[3] != 9 {
+		t.Fatal(keys)
+	}
+	if imap[0] != 3 || imap[3] != 2 {
+		t.Fatal(imap)
+	}
+	if i, ok := k.Find(9); !ok || i != 3 {
+		t.Fatal(i, ok)
+	}
+	if i, ok := k.Find(4); ok || i != 2 {
+		t.Fatal(i, ok)
+	}
+	if i, ok := k.Add(4); ok || i != 2 {
+		t.Fatal(i, ok)
+	}
+	var got []uint64
+	if k.Reset() {
+		for {
+			v, eof := k.Next()
+			got = append(got, v)
+			if eof {
+				break
+			}
+		}
+	}
+	if len(got) != 5 || got[2] != 4 {
+		t.Fatal(got)
+	}
+	k2 := new(KeyUint64)
+	roundTrip(t, k.Write, k2.Read)
+	if keys := k2.Keys(); len(keys) != 5 || keys[4] != 9 {
+		t.Fatal(keys)
+	}
+}
+
+func TestKeyValNamed(t *testing.T) {
+	kv := new(KeyVal[testID, int16])
+	kv.AddUnsorted(7, -3)
+	kv.AddUnsorted(2, 4)
+	kv.Build()
+	kv2 := new(KeyVal[testID, int16])
+	roundTrip(t, kv.Write, kv2.Read)
+	if v, ok := kv2.Find(7); !ok || v != -3 {
+		t.Fatal(v, ok)
+	}
+	if v, ok := kv2.Find(2); !ok || v != 4 {
+		t.Fatal(v, ok)
+	}
+	s := new(Key[string])
+	s.AddUnsorted("b")
+	s.AddUnsorted("a")
+	s.AddUnsorted("")
+	s.Build()
+	s2 := new(Key[string])
+	roundTrip(t, s.Write, s2.Read)
+	if keys := s2.Keys(); len(keys) != 3 || keys[0] != "" || keys[1] != "a" {
+		t.Fatal(keys)
+	}
+}
+
+func TestCounter(t *testing.T) {
+	c := new(CounterUint8)
+	c.Add(3, 1)
+	c.Add(3, 2)
+	c.Add(1, 1)
+	c.Build()
+	if v, ok := c.Find(3); !ok || v != 3 {
+		t.Fatal(v, ok)
+	}
+	if v, ok := c.KeyValUint8().Find(1); !ok || v != 1 {
+		t.Fatal(v, ok)
+	}
+	if i, ok := c.KeyUint8().Find(3); !ok || i != 1 {
+		t.Fatal(i, ok)
+	}
+	c2 := new(CounterUint8)
+	roundTrip(t, c.Write, c2.Read)
+	if v, ok := c2.Find(3); !ok || v != 3 || c2.Len() != 2 {
+		t.Fatal(v, ok)
+	}
+}
+
+func TestCounterRawKey(t *testing.T) {
+	ar := make([]sortIntUint64.KeyVal, 0, 10)
+	c := NewCounterUint64(ar)
+	c.Add(5, 1)
+	c.Add(2, 1)
+	c.Add(5, 1)
+	c.Build()
+	raw := c.RawKey()
+	if len(raw) != 2 || raw[0].V != 2 || raw[1].V != 5 || raw[1].K != 2 {
+		t.Fatal(raw)
+	}
+	if &ar[0:1][0] != &raw[0] {
+		t.Fatal(`NewCounterUint64 did not reuse the memory of ar`)
+	}
+	raw[1].K = 7 // RawKey is not a copy
+	if v, _ := c.Find(5); v != 7 {
+		t.Fatal(v)
+	}
+}
@@ -0,0 +1,192 @@
+package binsearch
+
+import (
+ "bytes"
+)
+
+/*
+	Next and Find's index go through the keys one length class at a time, so a shorter key always comes before a longer one.
+	ResetLex and NextLex go through them in the same order as bytes.Compare instead, by merging the length classes on the fly.
+	Within a length class the compacted keys are already in that order, except for the single byte "\x00" which is stored after the other single bytes.
+*/
+
+// lexSource is a structure with 64 length classes and the overflow, KeyBytes, KeyValBytes or CounterBytes.
+type lexSource interface {
+	classLen(on int) int // on is 8 * tier + run, or 64 for the overflow
+	classEntry(on, at int) ([]byte, int)
+}
+
+type lexHead struct {
+ key []byte
+ val int
+ on int // length class
+ step int // how many have been taken from this class
+}
+
+// lexOrder is the state of ResetLex & NextLex.
+type lexOrder struct {
+ heads []lexHead // a heap with the smallest key on top
+ offs [66]int // index of the first key of each class
+ nul int // the position "\x00" goes to in class 0, or -1 if it doesn't exist
+}
+
+// at returns the position in the class of the step'th key in lexicographic order.
+func (o *lexOrder) at(on, step int) int {
+	if on == 0 && o.nul >= 0 && step >= o.nul { // "\x00" is last but goes first, after the empty key
+		if step == o.nul {
+			return o.offs[1] - 1
+		}
+		return step - 1
+	}
+	return step
+}
+
+func (o *lexOrder) down(i int) {
+	heads := o.heads
+	for {
+		min := i
+		if c := 2*i + 1; c < len(heads) && bytes.Compare(heads[c].key, heads[min].key) < 0 {
+			min = c
+		}
+		if c := 2*i + 2; c < len(heads) && bytes.Compare(heads[c].key, heads[min].key) < 0 {
+			min = c
+		}
+		if min == i {
+			return
+		}
+		heads[i], heads[min] = heads[min], heads[i]
+		i = min
+	}
+}
+
+func (o *lexOrder) reset(src lexSource) bool {
+	o.heads = o.heads[0:0]
+	for on:=0; on<65; on++ {
+		o.offs[on+1] = o.offs[on] + src.classLen(on)
+	}
+	o.nul = -1
+	if l := o.offs[1]; l > 0 {
+		if key, _ := src.classEntry(0, l - 1); len(key) == 1 && key[0] == 0 {
+			o.nul = 0
+			if key, _ = src.classEntry(0, 0); len(key) == 0 {
+				o.nul = 1
+			}
+		}
+	}
+	for on:=0; on<65; on++ {
+		if o.offs[on+1] > o.offs[on] {
+			key, val := src.classEntry(on, o.at(on, 0))
+			o.heads = append(o.heads, lexHead{key, val, on, 0})
+		}
+	}
+	for i:=len(o.heads)/2 - 1; i>=0; i-- {
+		o.down(i)
+	}
+	return len(o.heads) > 0
+}
+
+// next returns the next key, its value, its index and whether it is the last.
+func (o *lexOrder) next(src lexSource) ([]byte, int, int, bool) {
+	h := o.heads[0]
+	index := o.offs[h.on] + o.at(h.on, h.step)
+	if step := h.step + 1; step < o.offs[h.on+1] - o.offs[h.on] {
+		key, val := src.classEntry(h.on, o.at(h.on, step))
+		o.heads[0] = lexHead{key, val, h.on, step}
+	} else {
+		o.heads[0] = o.heads[len(o.heads)-1]
+		o.heads = o.heads[0:len(o.heads)-1]
+	}
+	o.down(0)
+	return h.key, h.val, index, len(o.heads) == 0
+}
+
+// ---------- KeyBytes ----------
+
+// ResetLex must be called before NextLex. Returns whether there are any entries.
+func (t *KeyBytes) ResetLex() bool {
+	return t.lex.reset(t)
+}
+
+// NextLex returns: original slice of bytes, index, EOF (true = EOF), in the same order as bytes.Compare.
+func (t *KeyBytes) NextLex() ([]byte, int, bool) {
+	key, _, index, eof := t.lex.next(t)
+	return key, index, eof
+}
+
+// LexOrder returns the indexes of all the keys in the same order as bytes.Compare.
+func (t *KeyBytes) LexOrder() []int {
+	var o lexOrder
+	order := make([]int, 0, t.total)
+	for ok := o.reset(t); ok; {
+		_, _, index, eof := o.next(t)
+		order = append(order, index)
+		ok = !eof
+	}
+	return order
+}
+
+// ---------- KeyValBytes ----------
+
+// ResetLex must be called before NextLex. Returns whether there are any entries.
+func (t *KeyValBytes) ResetLex() bool {
+	return t.lex.reset(t)
+}
+
+// NextLex returns: original slice of bytes, value, EOF (true = EOF), in the same order as bytes.Compare.
+func (t *KeyValBytes) NextLex() ([]byte, int, bool) {
+	key, val, _, eof := t.lex.next(t)
+	return key, val, eof
+}
+
+// ---------- CounterBytes ----------
+
+// ResetLex must be called before NextLex. Returns whether there are any entries.
+func (t *CounterBytes) ResetLex() bool {
+	return t.lex.reset(t)
+}
+
+// NextLex returns: original slice of bytes, value, EOF (true = EOF), in the same order as bytes.Compare.
+func (t *CounterBytes) NextLex() ([]byte, int, bool) {
+	key, val, _, eof := t.lex.next(t)
+	return key, val, eof
+}
+
+// ---------- String ----------
+
+// ResetLex must be called before NextLex. Returns whether there are any entries.
+func (t *KeyString) ResetLex() bool {
+	return t.child.ResetLex()
+}
+
+// NextLex returns: key, index, EOF (true = EOF), in the same order as strings.Compare.
+func (t *KeyString) NextLex() (string, int, bool) {
+	key, index, eof := t.child.NextLex()
+	return string(key), index, eof
+}
+
+// LexOrder returns the indexes of all the keys in the same order as strings.Compare.
+func (t *KeyString) LexOrder() []int {
+	return t.child.LexOrder()
+}
+
+// ResetLex must be called before NextLex. Returns whether there are any entries.
+func (t *KeyValString) ResetLex() bool {
+	return t.child.ResetLex()
+}
+
+// NextLex returns: key, value, EOF (true = EOF), in the same order as strings.Compare.
+func (t *KeyValString) NextLex() (string, int, bool) {
+	key, val, eof := t.child.NextLex()
+	return string(key), val, eof
+}
+
+// ResetLex must be called before NextLex. Returns whether there are any entries.
+func (t *CounterString) ResetLex() bool {
+	return t.child.ResetLex()
+}
+
+// NextLex returns: key, value, EOF (true = EOF), in the same order as strings.Compare.
+func (t *CounterString) NextLex() (string, int, bool) {
+	key, val, eof := t.child.NextLex()
+	return string(key), val, eof
+}
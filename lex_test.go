@@ -0,0 +1,108 @@
+package binsearch
+
+import (
+ "bytes"
+ "math/rand"
+ "sort"
+ "testing"
+)
+
+// lexKeys returns distinct random keys of every length class, including the empty key, and the same keys sorted by bytes.Compare.
+func lexKeys(seed int64) ([][]byte, [][]byte) {
+	rnd := rand.New(rand.NewSource(seed))
+	seen := make(map[string]bool)
+	keys := [][]byte{{}, {0}, {255}, bytes.Repeat([]byte{0}, 70)}
+	for _, x := range keys {
+		seen[string(x)] = true
+	}
+	for i:=0; i<2000; i++ {
+		x := make([]byte, rnd.Intn(90))
+		for j := range x {
+			x[j] = byte(rnd.Intn(4)) * 80
+		}
+		if !seen[string(x)] {
+			seen[string(x)] = true
+			keys = append(keys, x)
+		}
+	}
+	sorted := append([][]byte(nil), keys...)
+	sort.Slice(sorted, func(a, b int) bool { return bytes.Compare(sorted[a], sorted[b]) < 0 })
+	return keys, sorted
+}
+
+func TestKeyBytesLex(t *testing.T) {
+	keys, sorted := lexKeys(18)
+	k := new(KeyBytes)
+	c := new(CounterBytes)
+	for i, x := range keys {
+		k.AddUnsorted(x)
+		c.Add(x, i)
+	}
+	k.Build()
+	c.Build()
+	if !k.ResetLex() {
+		t.Fatal(`ResetLex`)
+	}
+	for i:=0; ; i++ {
+		x, idx, eof := k.NextLex()
+		if !bytes.Equal(x, sorted[i]) {
+			t.Fatalf(`NextLex %d = %q, want %q`, i, x, sorted[i])
+		}
+		if j, _ := k.Find(x); j != idx {
+			t.Fatalf(`NextLex %d gave index %d, Find gives %d`, i, idx, j)
+		}
+		if eof {
+			if i != len(sorted) - 1 {
+				t.Fatal(`eof at`, i)
+			}
+			break
+		}
+	}
+	all := k.Keys()
+	for i, o := range k.LexOrder() {
+		if !bytes.Equal(all[o], sorted[i]) {
+			t.Fatalf(`LexOrder %d = %q, want %q`, i, all[o], sorted[i])
+		}
+	}
+	c.ResetLex()
+	for i:=0; ; i++ {
+		x, v, eof := c.NextLex()
+		if w, _ := c.Find(x); !bytes.Equal(x, sorted[i]) || v != w {
+			t.Fatalf(`CounterBytes.NextLex %d = %q, %d`, i, x, v)
+		}
+		if eof {
+			break
+		}
+	}
+	if new(KeyBytes).ResetLex() {
+		t.Fatal(`ResetLex on an empty structure`)
+	}
+}
+
+func TestKeyStringLex(t *testing.T) {
+	keys, sorted := lexKeys(19)
+	k := new(KeyString)
+	kv := new(KeyValString)
+	for i, x := range keys {
+		k.AddUnsorted(string(x))
+		kv.AddUnsorted(string(x), i)
+	}
+	k.Build()
+	kv.Build()
+	all := k.Keys()
+	for i, o := range k.LexOrder() {
+		if all[o] != string(sorted[i]) {
+			t.Fatalf(`LexOrder %d = %q, want %q`, i, all[o], sorted[i])
+		}
+	}
+	kv.ResetLex()
+	for i:=0; ; i++ {
+		x, v, eof := kv.NextLex()
+		if x != string(sorted[i]) || string(keys[v]) != x {
+			t.Fatalf(`KeyValString.NextLex %d = %q, %d`, i, x, v)
+		}
+		if eof {
+			break
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package binsearch
+
+import (
+ "errors"
+ "iter"
+ "regexp"
+ "regexp/syntax"
+ "strings"
+ "unicode/utf8"
+)
+
+/*
+	MatchRegexp and MatchGlob iterate over the keys that match a pattern, in the order of Next.
+	If the pattern can only match at the start of the key (a regexp starting with ^, or any glob) then the literal text it starts with
+	is used as a prefix, so only the keys with that prefix are decoded and tested, the same as PrefixRange.
+	A glob is converted to a regexp, matching as path.Match does: * is any sequence of characters but /, ? is any one character but /,
+	[abc], [a-z] and [!abc] (or [^abc]) are character classes, and \ escapes.
+*/
+
+var errGlob = errors.New(`Invalid glob pattern.`)
+
+// regexpPrefix returns the literal text that all keys matching re must start with, if re is anchored to the start of the key.
+func regexpPrefix(re *regexp.Regexp) string {
+	tree, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return ``
+	}
+	sub := []*syntax.Regexp{tree}
+	if tree.Op == syntax.OpConcat {
+		sub = tree.Sub
+	}
+	if len(sub) < 2 || sub[0].Op != syntax.OpBeginText {
+		return ``
+	}
+	var prefix strings.Builder
+	for _, s := range sub[1:] {
+		if s.Op != syntax.OpLiteral || s.Flags & syntax.FoldCase != 0 {
+			break
+		}
+		for _, r := range s.Rune {
+			if r == utf8.RuneError { // also matches invalid UTF-8
+				return prefix.String()
+			}
+			prefix.WriteRune(r)
+		}
+	}
+	return prefix.String()
+}
+
+// globRegexp converts a glob pattern to a regexp matching the whole key.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteByte('^')
+	glob := []rune(pattern)
+	for i:=0; i<len(glob); i++ {
+		switch glob[i] {
+			case '*':
+				b.WriteString(`[^/]*`)
+			case '?':
+				b.WriteString(`[^/]`)
+			case '\\':
+				if i++; i == len(glob) {
+					return nil, errGlob
+				}
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			case '[':
+				b.WriteByte('[')
+				j := i + 1
+				if j < len(glob) && (glob[j] == '!' || glob[j] == '^') {
+					b.WriteByte('^')
+					j++
+				}
+				for first := j; j < len(glob) && (glob[j] != ']' || j == first); j++ { // a ] straight after the [ is part of the class
+					switch glob[j] {
+						case '-':
+							b.WriteByte('-')
+						case '\\':
+							if j++; j == len(glob) {
+								return nil, errGlob
+							}
+							fallthrough
+						default:
+							b.WriteString(regexp.QuoteMeta(string(glob[j])))
+					}
+				}
+				if j == len(glob) {
+					return nil, errGlob
+				}
+				b.WriteByte(']')
+				i = j
+			default:
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		}
+	}
+	b.WriteByte('$')
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, errGlob
+	}
+	return re, nil
+}
+
+// eachPrefixEntry calls fn with the index, key and value of each key that starts with prefix, in the order of Next, until fn returns false.
+func eachPrefixEntry(src prefixSource, prefix []byte, fn func(int, []byte, int) bool) {
+	eachPrefixClass(src, prefix, func(on, start, from, to int) bool {
+		for at:=from; at<to; at++ {
+			key, val := src.classEntry(on, at)
+			if !fn(start + at, key, val) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// matchBytes is MatchRegexp for the bytes types, yielding the index with each key if index is true, otherwise the value.
+func matchBytes(src prefixSource, re *regexp.Regexp, index bool) iter.Seq2[[]byte, int] {
+	prefix := []byte(regexpPrefix(re))
+	return func(yield func([]byte, int) bool) {
+		eachPrefixEntry(src, prefix, func(i int, key []byte, val int) bool {
+			if !re.Match(key) {
+				return true
+			}
+			if index {
+				return yield(key, i)
+			}
+			return yield(key, val)
+		})
+	}
+}
+
+// matchRunes is MatchRegexp for the runes types, the regexp is matched against the key as a string.
+func matchRunes(src prefixSource, re *regexp.Regexp, legacy bool, index bool) iter.Seq2[[]rune, int] {
+	prefix := encodeRunes([]rune(regexpPrefix(re)), legacy)
+	return func(yield func([]rune, int) bool) {
+		eachPrefixEntry(src, prefix, func(i int, b []byte, val int) bool {
+			key := decodeRunes(b, legacy)
+			if !re.MatchString(string(key)) {
+				return true
+			}
+			if index {
+				return yield(key, i)
+			}
+			return yield(key, val)
+		})
+	}
+}
+
+// ---------- KeyBytes ----------
+
+// MatchRegexp iterates over the keys matching re and their indexes, in the order of Next.
+func (t *KeyBytes) MatchRegexp(re *regexp.Regexp) iter.Seq2[[]byte, int] {
+	return matchBytes(t, re, true)
+}
+
+// MatchGlob iterates over the keys matching the glob pattern and their indexes, in the order of Next.
+func (t *KeyBytes) MatchGlob(pattern string) (iter.Seq2[[]byte, int], error) {
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return t.MatchRegexp(re), nil
+}
+
+// ---------- KeyValBytes ----------
+
+// MatchRegexp iterates over the keys matching re and their values, in the order of Next.
+func (t *KeyValBytes) MatchRegexp(re *regexp.Regexp) iter.Seq2[[]byte, int] {
+	return matchBytes(t, re, false)
+}
+
+// MatchGlob iterates over the keys matching the glob pattern and their values, in the order of Next.
+func (t *KeyValBytes) MatchGlob(pattern string) (iter.Seq2[[]byte, int], error) {
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return t.MatchRegexp(re), nil
+}
+
+// ---------- CounterBytes ----------
+
+// MatchRegexp iterates over the keys matching re and their frequencies, in the order of Next.
+func (t *CounterBytes) MatchRegexp(re *regexp.Regexp) iter.Seq2[[]byte, int] {
+	return matchBytes(t, re, false)
+}
+
+// MatchGlob iterates over the keys matching the glob pattern and their frequencies, in the order of Next.
+func (t *CounterBytes) MatchGlob(pattern string) (iter.Seq2[[]byte, int], error) {
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return t.MatchRegexp(re), nil
+}
+
+// ---------- Runes ----------
+
+// MatchRegexp iterates over the keys matching re and their indexes, in the order of Next.
+func (t *KeyRunes) MatchRegexp(re *regexp.Regexp) iter.Seq2[[]rune, int] {
+	return matchRunes(&t.child, re, t.legacy, true)
+}
+
+// MatchGlob iterates over the keys matching the glob pattern and their indexes, in the order of Next.
+func (t *KeyRunes) MatchGlob(pattern string) (iter.Seq2[[]rune, int], error) {
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return t.MatchRegexp(re), nil
+}
+
+// MatchRegexp iterates over the keys matching re and their values, in the order of Next.
+func (t *KeyValRunes) MatchRegexp(re *regexp.Regexp) iter.Seq2[[]rune, int] {
+	return matchRunes(&t.child, re, t.legacy, false)
+}
+
+// MatchGlob iterates over the keys matching the glob pattern and their values, in the order of Next.
+func (t *KeyValRunes) MatchGlob(pattern string) (iter.Seq2[[]rune, int], error) {
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return t.MatchRegexp(re), nil
+}
+
+// MatchRegexp iterates over the keys matching re and their frequencies, in the order of Next.
+func (t *CounterRunes) MatchRegexp(re *regexp.Regexp) iter.Seq2[[]rune, int] {
+	return matchRunes(&t.child, re, t.legacy, false)
+}
+
+// MatchGlob iterates over the keys matching the glob pattern and their frequencies, in the order of Next.
+func (t *CounterRunes) MatchGlob(pattern string) (iter.Seq2[[]rune, int], error) {
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return t.MatchRegexp(re), nil
+}
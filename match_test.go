@@ -0,0 +1,156 @@
+package binsearch
+
+import (
+ "math/rand"
+ "path"
+ "regexp"
+ "strings"
+ "testing"
+)
+
+func TestMatch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(24))
+	alphabet := []string{`a`, `b`, `_`, `é`, `c`, `/`}
+	gen := func() string {
+		n := rnd.Intn(14)
+		if rnd.Intn(20) == 0 {
+			n = 70
+		}
+		var b strings.Builder
+		for j:=0; j<n; j++ {
+			b.WriteString(alphabet[rnd.Intn(len(alphabet))])
+		}
+		return b.String()
+	}
+	k := new(KeyBytes)
+	kv := new(KeyValBytes)
+	c := new(CounterBytes)
+	r := new(KeyRunes)
+	cr := new(CounterRunes)
+	seen := map[string]bool{``: true}
+	keys := []string{``}
+	for i:=0; i<3000; i++ {
+		x := gen()
+		if !seen[x] {
+			seen[x] = true
+			keys = append(keys, x)
+		}
+	}
+	for i, x := range keys {
+		k.AddUnsorted([]byte(x))
+		kv.AddUnsorted([]byte(x), i)
+		c.Add([]byte(x), 2)
+		r.AddUnsorted([]rune(x))
+		cr.Add([]rune(x), 2)
+	}
+	k.Build()
+	kv.Build()
+	c.Build()
+	r.Build()
+	cr.Build()
+	// path.Match is the brute force for the globs, it spells a negated class [^a] where ours also takes [!a]. Neither * nor ? match /.
+	for _, g := range []string{`a*`, `ab?_*`, `*`, ``, `[ab]_*c`, `[^a]*`, `[!a]*`, `a\*`, `é*b`, `ab*`, `aaaa*`, `?`, `[a-c]?é*`, `a/*`, `*/b*`, `?/?`, `*/*/*`, `[/]*`, `[^/]?`} {
+		want := 0
+		for _, x := range keys {
+			if m, _ := path.Match(strings.Replace(g, `[!`, `[^`, 1), x); m {
+				want++
+			}
+		}
+		seq, err := k.MatchGlob(g)
+		if err != nil {
+			t.Fatal(g, err)
+		}
+		got := 0
+		for key, i := range seq {
+			if m, _ := path.Match(strings.Replace(g, `[!`, `[^`, 1), string(key)); !m {
+				t.Fatalf(`MatchGlob(%q) gave %q`, g, key)
+			}
+			if j, _ := k.Find(key); j != i {
+				t.Fatalf(`MatchGlob(%q) gave index %d for %q, want %d`, g, i, key, j)
+			}
+			got++
+		}
+		if got != want {
+			t.Fatalf(`MatchGlob(%q) gave %d keys, want %d`, g, got, want)
+		}
+		seq, _ = kv.MatchGlob(g)
+		got = 0
+		for key, v := range seq {
+			if keys[v] != string(key) {
+				t.Fatalf(`KeyValBytes.MatchGlob(%q) gave %q with value %d`, g, key, v)
+			}
+			got++
+		}
+		if got != want {
+			t.Fatalf(`KeyValBytes.MatchGlob(%q) gave %d keys, want %d`, g, got, want)
+		}
+		seq, _ = c.MatchGlob(g)
+		got = 0
+		for _, v := range seq {
+			if v != 2 {
+				t.Fatalf(`CounterBytes.MatchGlob(%q) gave frequency %d`, g, v)
+			}
+			got++
+		}
+		if got != want {
+			t.Fatalf(`CounterBytes.MatchGlob(%q) gave %d keys, want %d`, g, got, want)
+		}
+		rs, err := r.MatchGlob(g)
+		if err != nil {
+			t.Fatal(g, err)
+		}
+		got = 0
+		for key, i := range rs {
+			if m, _ := path.Match(strings.Replace(g, `[!`, `[^`, 1), string(key)); !m {
+				t.Fatalf(`KeyRunes.MatchGlob(%q) gave %q`, g, string(key))
+			}
+			if j, _ := r.Find(key); j != i {
+				t.Fatalf(`KeyRunes.MatchGlob(%q) gave index %d for %q, want %d`, g, i, string(key), j)
+			}
+			got++
+		}
+		if got != want {
+			t.Fatalf(`KeyRunes.MatchGlob(%q) gave %d keys, want %d`, g, got, want)
+		}
+	}
+	for _, e := range []string{`^ab`, `b_`, `^é+a$`, `^(ab|ac)`, `(?i)^AB`, `^a.*c$`, `^$`, `^é`, `c$`} {
+		re := regexp.MustCompile(e)
+		want := 0
+		for _, x := range keys {
+			if re.MatchString(x) {
+				want++
+			}
+		}
+		got := 0
+		for key := range k.MatchRegexp(re) {
+			if !re.Match(key) {
+				t.Fatalf(`MatchRegexp(%q) gave %q`, e, key)
+			}
+			got++
+		}
+		if got != want {
+			t.Fatalf(`MatchRegexp(%q) gave %d keys, want %d`, e, got, want)
+		}
+		got = 0
+		for key, v := range cr.MatchRegexp(re) {
+			if !re.MatchString(string(key)) || v != 2 {
+				t.Fatalf(`CounterRunes.MatchRegexp(%q) gave %q, %d`, e, string(key), v)
+			}
+			got++
+		}
+		if got != want {
+			t.Fatalf(`CounterRunes.MatchRegexp(%q) gave %d keys, want %d`, e, got, want)
+		}
+	}
+	n := 0
+	for range k.MatchRegexp(regexp.MustCompile(`a`)) {
+		if n++; n == 3 {
+			break // stopping early must not panic
+		}
+	}
+	for _, g := range []string{`[ab`, `a\`, `[a\`} {
+		if _, err := k.MatchGlob(g); err == nil {
+			t.Fatalf(`MatchGlob(%q) accepted a bad pattern`, g)
+		}
+	}
+}
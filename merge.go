@@ -0,0 +1,363 @@
+package binsearch
+
+import (
+ "bytes"
+ "cmp"
+ "github.com/AlasdairF/BinSearch/Ordered"
+ "github.com/AlasdairF/BinSearch/Overflow"
+)
+
+/*
+	Merge combines two built KeyVal or Counter structures in linear time and returns a new built structure.
+	Keys in only one of them keep their value, for keys in both the value is resolve(t's value, other's value).
+*/
+
+// row is a compacted key with the value in the last column, as used by KeyValBytes and CounterBytes.
+type row interface {
+	[2]uint64 | [3]uint64 | [4]uint64 | [5]uint64 | [6]uint64 | [7]uint64 | [8]uint64 | [9]uint64
+}
+
+// compareRow compares the keys of two rows, ignoring the value.
+func compareRow[T row](a, b *T) int {
+	l := len(*a) - 1
+	for i:=0; i<l; i++ {
+		if (*a)[i] < (*b)[i] {
+			return -1
+		}
+		if (*a)[i] > (*b)[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+func resolveRow[T row](resolve func(int, int) int) func(*T, *T) T {
+	return func(a, b *T) T {
+		r := *a
+		l := len(r) - 1
+		r[l] = uint64(resolve(int((*a)[l]), int((*b)[l])))
+		return r
+	}
+}
+
+func compareOverflowVal(a, b *sortOverflow.KeyVal) int {
+	return bytes.Compare(a.V, b.V)
+}
+
+func resolveOverflowVal(resolve func(int, int) int) func(*sortOverflow.KeyVal, *sortOverflow.KeyVal) sortOverflow.KeyVal {
+	return func(a, b *sortOverflow.KeyVal) sortOverflow.KeyVal {
+		return sortOverflow.KeyVal{resolve(a.K, b.K), a.V}
+	}
+}
+
+func compareOrderedVal[K cmp.Ordered, V any](a, b *sortOrdered.KeyVal[K, V]) int {
+	return compareOrdered(&a.V, &b.V)
+}
+
+func resolveOrderedVal[K cmp.Ordered, V any](resolve func(V, V) V) func(*sortOrdered.KeyVal[K, V], *sortOrdered.KeyVal[K, V]) sortOrdered.KeyVal[K, V] {
+	return func(a, b *sortOrdered.KeyVal[K, V]) sortOrdered.KeyVal[K, V] {
+		return sortOrdered.KeyVal[K, V]{resolve(a.K, b.K), a.V}
+	}
+}
+
+// mergeResolve is mergeSorted for a union where the keys in both are combined by resolve.
+func mergeResolve[T any](a, b []T, compare func(*T, *T) int, resolve func(*T, *T) T) []T {
+	l := mergeSortedLen(a, b, compare, setA | setBoth | setB)
+	if l == 0 {
+		return nil
+	}
+	out := make([]T, 0, l)
+	var i, j int
+	for i < len(a) && j < len(b) {
+		if c := compare(&a[i], &b[j]); c < 0 {
+			out = append(out, a[i])
+			i++
+		} else if c > 0 {
+			out = append(out, b[j])
+			j++
+		} else {
+			out = append(out, resolve(&a[i], &b[j]))
+			i++
+			j++
+		}
+	}
+	out = append(out, a[i:]...)
+	return append(out, b[j:]...)
+}
+
+// ---------- KeyValBytes ----------
+
+// Merge returns a new KeyValBytes with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *KeyValBytes) Merge(other *KeyValBytes, resolve func(a, b int) int) *KeyValBytes {
+	obj := new(KeyValBytes)
+	for run:=0; run<8; run++ {
+		obj.limit8[run] = mergeResolve(t.limit8[run], other.limit8[run], compareRow[[2]uint64], resolveRow[[2]uint64](resolve))
+		obj.limit16[run] = mergeResolve(t.limit16[run], other.limit16[run], compareRow[[3]uint64], resolveRow[[3]uint64](resolve))
+		obj.limit24[run] = mergeResolve(t.limit24[run], other.limit24[run], compareRow[[4]uint64], resolveRow[[4]uint64](resolve))
+		obj.limit32[run] = mergeResolve(t.limit32[run], other.limit32[run], compareRow[[5]uint64], resolveRow[[5]uint64](resolve))
+		obj.limit40[run] = mergeResolve(t.limit40[run], other.limit40[run], compareRow[[6]uint64], resolveRow[[6]uint64](resolve))
+		obj.limit48[run] = mergeResolve(t.limit48[run], other.limit48[run], compareRow[[7]uint64], resolveRow[[7]uint64](resolve))
+		obj.limit56[run] = mergeResolve(t.limit56[run], other.limit56[run], compareRow[[8]uint64], resolveRow[[8]uint64](resolve))
+		obj.limit64[run] = mergeResolve(t.limit64[run], other.limit64[run], compareRow[[9]uint64], resolveRow[[9]uint64](resolve))
+	}
+	obj.overflow = mergeResolve(t.overflow, other.overflow, compareOverflowVal, resolveOverflowVal(resolve))
+	obj.total = obj.tiered() + len(obj.overflow)
+	return obj
+}
+
+// ---------- CounterBytes ----------
+
+// Merge returns a new CounterBytes with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *CounterBytes) Merge(other *CounterBytes, resolve func(a, b int) int) *CounterBytes {
+	obj := new(CounterBytes)
+	for run:=0; run<8; run++ {
+		obj.limit8[run] = mergeResolve(t.limit8[run], other.limit8[run], compareRow[[2]uint64], resolveRow[[2]uint64](resolve))
+		obj.limit16[run] = mergeResolve(t.limit16[run], other.limit16[run], compareRow[[3]uint64], resolveRow[[3]uint64](resolve))
+		obj.limit24[run] = mergeResolve(t.limit24[run], other.limit24[run], compareRow[[4]uint64], resolveRow[[4]uint64](resolve))
+		obj.limit32[run] = mergeResolve(t.limit32[run], other.limit32[run], compareRow[[5]uint64], resolveRow[[5]uint64](resolve))
+		obj.limit40[run] = mergeResolve(t.limit40[run], other.limit40[run], compareRow[[6]uint64], resolveRow[[6]uint64](resolve))
+		obj.limit48[run] = mergeResolve(t.limit48[run], other.limit48[run], compareRow[[7]uint64], resolveRow[[7]uint64](resolve))
+		obj.limit56[run] = mergeResolve(t.limit56[run], other.limit56[run], compareRow[[8]uint64], resolveRow[[8]uint64](resolve))
+		obj.limit64[run] = mergeResolve(t.limit64[run], other.limit64[run], compareRow[[9]uint64], resolveRow[[9]uint64](resolve))
+	}
+	obj.overflow = mergeResolve(t.overflow, other.overflow, compareOverflowVal, resolveOverflowVal(resolve))
+	obj.total = obj.tiered() + len(obj.overflow)
+	return obj
+}
+
+// ---------- KeyVal ----------
+
+// Merge returns a new KeyVal with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+// The new KeyVal orders its values in the same way as t.
+func (t *KeyVal[K, V]) Merge(other *KeyVal[K, V], resolve func(a, b V) V) *KeyVal[K, V] {
+	return &KeyVal[K, V]{key:mergeResolve(t.key, other.key, compareOrderedVal[K, V], resolveOrderedVal[K, V](resolve)), compare:t.compare}
+}
+
+// ---------- Counter ----------
+
+// Merge returns a new Counter with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *Counter[K, N]) Merge(other *Counter[K, N], resolve func(a, b N) N) *Counter[K, N] {
+	return &Counter[K, N]{key:mergeResolve(t.key, other.key, compareOrderedVal[K, N], resolveOrderedVal[K, N](resolve))}
+}
+
+// ---------- CounterUint64, etc. ----------
+
+// The Counter types embed Counter[K, int], so they each have a Merge that returns their own type instead of the Counter[K, int] it would promote.
+
+// Merge returns a new CounterUint64 with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *CounterUint64) Merge(other *CounterUint64, resolve func(a, b int) int) *CounterUint64 {
+	return &CounterUint64{*t.Counter.Merge(&other.Counter, resolve)}
+}
+
+// Merge returns a new CounterUint32 with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *CounterUint32) Merge(other *CounterUint32, resolve func(a, b int) int) *CounterUint32 {
+	return &CounterUint32{*t.Counter.Merge(&other.Counter, resolve)}
+}
+
+// Merge returns a new CounterUint16 with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *CounterUint16) Merge(other *CounterUint16, resolve func(a, b int) int) *CounterUint16 {
+	return &CounterUint16{*t.Counter.Merge(&other.Counter, resolve)}
+}
+
+// Merge returns a new CounterUint8 with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *CounterUint8) Merge(other *CounterUint8, resolve func(a, b int) int) *CounterUint8 {
+	return &CounterUint8{*t.Counter.Merge(&other.Counter, resolve)}
+}
+
+// Merge returns a new CounterInt with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *CounterInt) Merge(other *CounterInt, resolve func(a, b int) int) *CounterInt {
+	return &CounterInt{*t.Counter.Merge(&other.Counter, resolve)}
+}
+
+// Merge returns a new CounterInt64 with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *CounterInt64) Merge(other *CounterInt64, resolve func(a, b int) int) *CounterInt64 {
+	return &CounterInt64{*t.Counter.Merge(&other.Counter, resolve)}
+}
+
+// Merge returns a new CounterInt32 with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *CounterInt32) Merge(other *CounterInt32, resolve func(a, b int) int) *CounterInt32 {
+	return &CounterInt32{*t.Counter.Merge(&other.Counter, resolve)}
+}
+
+// Merge returns a new CounterInt16 with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *CounterInt16) Merge(other *CounterInt16, resolve func(a, b int) int) *CounterInt16 {
+	return &CounterInt16{*t.Counter.Merge(&other.Counter, resolve)}
+}
+
+// Merge returns a new CounterInt8 with the keys of both, resolve gives the value for keys that are in both. Both must be built.
+func (t *CounterInt8) Merge(other *CounterInt8, resolve func(a, b int) int) *CounterInt8 {
+	return &CounterInt8{*t.Counter.Merge(&other.Counter, resolve)}
+}
+
+// ---------- MergeCounters ----------
+
+/*
+	MergeCounterBytes and MergeCounters merge any number of built Counter shards in one pass, summing the values of identical keys.
+	Each tier is merged with a heap of one cursor per shard, so the only memory used beyond the result is the heap.
+*/
+
+func sumRow[T row](a, b *T) T {
+	r := *a
+	l := len(r) - 1
+	r[l] = sumInt(r[l], (*b)[l])
+	return r
+}
+
+func sumOverflowVal(a, b *sortOverflow.KeyVal) sortOverflow.KeyVal {
+	return sortOverflow.KeyVal{a.K + b.K, a.V}
+}
+
+func sumOrderedVal[K cmp.Ordered, N Number](a, b *sortOrdered.KeyVal[K, N]) sortOrdered.KeyVal[K, N] {
+	return sortOrdered.KeyVal[K, N]{a.K + b.K, a.V}
+}
+
+// mergeK merges the sorted lists, combining identical keys with sum.
+func mergeK[T any](lists [][]T, compare func(*T, *T) int, sum func(*T, *T) T) []T {
+	// The heap holds the index of each list that is not used up, ordered by the key at its cursor
+	heap := make([]int, 0, len(lists))
+	cursor := make([]int, len(lists))
+	var l int
+	for i, list := range lists {
+		if len(list) > 0 {
+			heap = append(heap, i)
+			if len(list) > l {
+				l = len(list)
+			}
+		}
+	}
+	if len(heap) == 0 {
+		return nil
+	}
+	less := func(a, b int) bool {
+		return compare(&lists[heap[a]][cursor[heap[a]]], &lists[heap[b]][cursor[heap[b]]]) < 0
+	}
+	down := func(i int) {
+		for {
+			min := i
+			if c := 2*i + 1; c < len(heap) && less(c, min) {
+				min = c
+			}
+			if c := 2*i + 2; c < len(heap) && less(c, min) {
+				min = c
+			}
+			if min == i {
+				return
+			}
+			heap[i], heap[min] = heap[min], heap[i]
+			i = min
+		}
+	}
+	for i:=len(heap)/2 - 1; i>=0; i-- {
+		down(i)
+	}
+	out := make([]T, 0, l)
+	for len(heap) > 0 {
+		on := heap[0]
+		v := lists[on][cursor[on]]
+		if last := len(out) - 1; last >= 0 && compare(&out[last], &v) == 0 {
+			out[last] = sum(&out[last], &v)
+		} else {
+			out = append(out, v)
+		}
+		if cursor[on]++; cursor[on] == len(lists[on]) {
+			heap[0] = heap[len(heap)-1]
+			heap = heap[:len(heap)-1]
+		}
+		down(0)
+	}
+	return out
+}
+
+// MergeCounterBytes returns a new built CounterBytes with the keys of all the shards and the sum of their values. The shards must be built.
+func MergeCounterBytes(shards ...*CounterBytes) *CounterBytes {
+	obj := new(CounterBytes)
+	l := len(shards)
+	for run:=0; run<8; run++ {
+		limit8, limit16, limit24, limit32 := make([][][2]uint64, l), make([][][3]uint64, l), make([][][4]uint64, l), make([][][5]uint64, l)
+		limit40, limit48, limit56, limit64 := make([][][6]uint64, l), make([][][7]uint64, l), make([][][8]uint64, l), make([][][9]uint64, l)
+		for i, shard := range shards {
+			limit8[i], limit16[i], limit24[i], limit32[i] = shard.limit8[run], shard.limit16[run], shard.limit24[run], shard.limit32[run]
+			limit40[i], limit48[i], limit56[i], limit64[i] = shard.limit40[run], shard.limit48[run], shard.limit56[run], shard.limit64[run]
+		}
+		obj.limit8[run] = mergeK(limit8, compareRow[[2]uint64], sumRow[[2]uint64])
+		obj.limit16[run] = mergeK(limit16, compareRow[[3]uint64], sumRow[[3]uint64])
+		obj.limit24[run] = mergeK(limit24, compareRow[[4]uint64], sumRow[[4]uint64])
+		obj.limit32[run] = mergeK(limit32, compareRow[[5]uint64], sumRow[[5]uint64])
+		obj.limit40[run] = mergeK(limit40, compareRow[[6]uint64], sumRow[[6]uint64])
+		obj.limit48[run] = mergeK(limit48, compareRow[[7]uint64], sumRow[[7]uint64])
+		obj.limit56[run] = mergeK(limit56, compareRow[[8]uint64], sumRow[[8]uint64])
+		obj.limit64[run] = mergeK(limit64, compareRow[[9]uint64], sumRow[[9]uint64])
+	}
+	overflow := make([][]sortOverflow.KeyVal, l)
+	for i, shard := range shards {
+		overflow[i] = shard.overflow
+	}
+	obj.overflow = mergeK(overflow, compareOverflowVal, sumOverflowVal)
+	obj.total = obj.tiered() + len(obj.overflow)
+	return obj
+}
+
+// MergeCounters returns a new built Counter with the keys of all the shards and the sum of their values. The shards must be built.
+func MergeCounters[K cmp.Ordered, N Number](shards ...*Counter[K, N]) *Counter[K, N] {
+	lists := make([][]sortOrdered.KeyVal[K, N], len(shards))
+	for i, shard := range shards {
+		lists[i] = shard.key
+	}
+	return &Counter[K, N]{key:mergeK(lists, compareOrderedVal[K, N], sumOrderedVal[K, N])}
+}
+
+// ---------- MergeCountersUint64, etc. ----------
+
+// embedded returns the Counter[K, int] that each shard embeds.
+func embedded[K cmp.Ordered, C any](shards []*C, counter func(*C) *Counter[K, int]) []*Counter[K, int] {
+	all := make([]*Counter[K, int], len(shards))
+	for i, shard := range shards {
+		all[i] = counter(shard)
+	}
+	return all
+}
+
+// MergeCountersUint64 is MergeCounters for CounterUint64.
+func MergeCountersUint64(shards ...*CounterUint64) *CounterUint64 {
+	return &CounterUint64{*MergeCounters(embedded(shards, func(c *CounterUint64) *Counter[uint64, int] { return &c.Counter })...)}
+}
+
+// MergeCountersUint32 is MergeCounters for CounterUint32.
+func MergeCountersUint32(shards ...*CounterUint32) *CounterUint32 {
+	return &CounterUint32{*MergeCounters(embedded(shards, func(c *CounterUint32) *Counter[uint32, int] { return &c.Counter })...)}
+}
+
+// MergeCountersUint16 is MergeCounters for CounterUint16.
+func MergeCountersUint16(shards ...*CounterUint16) *CounterUint16 {
+	return &CounterUint16{*MergeCounters(embedded(shards, func(c *CounterUint16) *Counter[uint16, int] { return &c.Counter })...)}
+}
+
+// MergeCountersUint8 is MergeCounters for CounterUint8.
+func MergeCountersUint8(shards ...*CounterUint8) *CounterUint8 {
+	return &CounterUint8{*MergeCounters(embedded(shards, func(c *CounterUint8) *Counter[uint8, int] { return &c.Counter })...)}
+}
+
+// MergeCountersInt is MergeCounters for CounterInt.
+func MergeCountersInt(shards ...*CounterInt) *CounterInt {
+	return &CounterInt{*MergeCounters(embedded(shards, func(c *CounterInt) *Counter[int, int] { return &c.Counter })...)}
+}
+
+// MergeCountersInt64 is MergeCounters for CounterInt64.
+func MergeCountersInt64(shards ...*CounterInt64) *CounterInt64 {
+	return &CounterInt64{*MergeCounters(embedded(shards, func(c *CounterInt64) *Counter[int64, int] { return &c.Counter })...)}
+}
+
+// MergeCountersInt32 is MergeCounters for CounterInt32.
+func MergeCountersInt32(shards ...*CounterInt32) *CounterInt32 {
+	return &CounterInt32{*MergeCounters(embedded(shards, func(c *CounterInt32) *Counter[int32, int] { return &c.Counter })...)}
+}
+
+// MergeCountersInt16 is MergeCounters for CounterInt16.
+func MergeCountersInt16(shards ...*CounterInt16) *CounterInt16 {
+	return &CounterInt16{*MergeCounters(embedded(shards, func(c *CounterInt16) *Counter[int16, int] { return &c.Counter })...)}
+}
+
+// MergeCountersInt8 is MergeCounters for CounterInt8.
+func MergeCountersInt8(shards ...*CounterInt8) *CounterInt8 {
+	return &CounterInt8{*MergeCounters(embedded(shards, func(c *CounterInt8) *Counter[int8, int] { return &c.Counter })...)}
+}
@@ -0,0 +1,212 @@
+package binsearch
+
+import (
+ "math/rand"
+ "strings"
+ "testing"
+)
+
+// randomCounts returns random counts for keys of every length class, including the empty key and the overflow.
+func randomCounts(rnd *rand.Rand, n int) map[string]int {
+	counts := make(map[string]int)
+	for i:=0; i<n; i++ {
+		x := rnd.Intn(200)
+		counts[strings.Repeat(`k`, x % 90) + string(rune('a' + x % 26))] += 1 + rnd.Intn(5)
+	}
+	counts[``] = 1 + rnd.Intn(5)
+	return counts
+}
+
+func TestCounterBytesMerge(t *testing.T) {
+	rnd := rand.New(rand.NewSource(13))
+	for round:=0; round<5; round++ {
+		a, b := randomCounts(rnd, 150), randomCounts(rnd, 150)
+		x, y := new(CounterBytes), new(CounterBytes)
+		for k, v := range a {
+			x.Add([]byte(k), v)
+		}
+		for k, v := range b {
+			y.Add([]byte(k), v)
+		}
+		x.Build()
+		y.Build()
+		m := x.Merge(y, func(p, q int) int { return p * 100 + q })
+		want := make(map[string]int)
+		for k, v := range a {
+			want[k] = v
+		}
+		for k, v := range b {
+			if w, ok := want[k]; ok {
+				want[k] = w * 100 + v
+			} else {
+				want[k] = v
+			}
+		}
+		if m.Len() != len(want) {
+			t.Fatal(m.Len(), len(want))
+		}
+		// The result is built: it's in the same order as a Counter built from scratch, and it can be written and read.
+		c := new(CounterBytes)
+		for k, v := range want {
+			c.Add([]byte(k), v)
+		}
+		c.Build()
+		m2 := new(CounterBytes)
+		roundTrip(t, m.Write, m2.Read)
+		keys := c.Keys()
+		for i, k := range m2.Keys() {
+			if string(k) != string(keys[i]) {
+				t.Fatalf(`key %d is %q, want %q`, i, k, keys[i])
+			}
+			if v, ok := m2.Find(k); !ok || v != want[string(k)] {
+				t.Fatalf(`%q = %d, want %d`, k, v, want[string(k)])
+			}
+		}
+	}
+}
+
+func TestKeyValBytesMerge(t *testing.T) {
+	x, y := new(KeyValBytes), new(KeyValBytes)
+	long := strings.Repeat(`x`, 80)
+	x.AddUnsorted([]byte(`q`), 4)
+	x.AddUnsorted([]byte(long), 2)
+	y.AddUnsorted([]byte(`q`), 7)
+	y.AddUnsorted([]byte(`r`), 1)
+	y.AddUnsorted([]byte(long), 1)
+	x.Build()
+	y.Build()
+	m := x.Merge(y, func(a, b int) int { return max(a, b) })
+	for k, want := range map[string]int{`q`: 7, `r`: 1, long: 2} {
+		if v, ok := m.Find([]byte(k)); !ok || v != want {
+			t.Fatalf(`%q = %d, want %d`, k, v, want)
+		}
+	}
+	if m.Len() != 3 {
+		t.Fatal(m.Len())
+	}
+	if v, _ := x.Find([]byte(`q`)); v != 4 {
+		t.Fatal(`Merge changed its receiver`, v)
+	}
+}
+
+func TestCounterMerge(t *testing.T) {
+	c1, c2 := NewCounter[int64, int](nil), NewCounter[int64, int](nil)
+	c1.Add(-3, 2)
+	c1.Add(5, 1)
+	c2.Add(5, 4)
+	c2.Add(9, 1)
+	c1.Build()
+	c2.Build()
+	m := c1.Merge(c2, func(a, b int) int { return a + b })
+	if keys := m.Keys(); len(keys) != 3 || keys[0] != -3 || keys[2] != 9 {
+		t.Fatal(keys)
+	}
+	for k, want := range map[int64]int{-3: 2, 5: 5, 9: 1} {
+		if v, ok := m.Find(k); !ok || v != want {
+			t.Fatal(k, v, ok)
+		}
+	}
+	// The Counter types return their own type, which keeps its own methods
+	u1, u2 := new(CounterUint32), new(CounterUint32)
+	u1.Add(7, 1)
+	u2.Add(7, 2)
+	u2.Add(1, 3)
+	u1.Build()
+	u2.Build()
+	var mu *CounterUint32 = u1.Merge(u2, func(a, b int) int { return a * 10 + b })
+	if raw := mu.RawKey(); len(raw) != 2 || raw[1].K != 12 || mu.KeyUint32().Len() != 2 {
+		t.Fatal(raw)
+	}
+	i1, i2 := new(CounterInt8), new(CounterInt8)
+	i1.Add(-1, 1)
+	i2.Add(1, 1)
+	i1.Build()
+	i2.Build()
+	if mi := i1.Merge(i2, nil); mi.KeyInt8().Len() != 2 {
+		t.Fatal(mi.Keys())
+	}
+	kv1, kv2 := new(KeyVal[string, string]), new(KeyVal[string, string])
+	kv1.Add(`a`, `x`)
+	kv2.Add(`a`, `y`)
+	kv2.Add(`b`, `z`)
+	mk := kv1.Merge(kv2, func(a, b string) string { return a + b })
+	if v, _ := mk.Find(`a`); v != `xy` || mk.Len() != 2 {
+		t.Fatal(v, mk.Len())
+	}
+}
+
+func TestMergeCounterBytes(t *testing.T) {
+	rnd := rand.New(rand.NewSource(14))
+	want := make(map[string]int)
+	var shards []*CounterBytes
+	for s:=0; s<7; s++ {
+		c := new(CounterBytes)
+		for k, v := range randomCounts(rnd, 300) {
+			c.Add([]byte(k), v)
+			want[k] += v
+		}
+		c.Build()
+		shards = append(shards, c)
+	}
+	shards = append(shards, new(CounterBytes))
+	m := MergeCounterBytes(shards...)
+	if m.Len() != len(want) {
+		t.Fatal(m.Len(), len(want))
+	}
+	m2 := new(CounterBytes)
+	roundTrip(t, m.Write, m2.Read)
+	for k, v := range want {
+		if n, ok := m2.Find([]byte(k)); !ok || n != v {
+			t.Fatalf(`%q = %d, want %d`, k, n, v)
+		}
+	}
+	if MergeCounterBytes().Len() != 0 {
+		t.Fatal(`merging nothing is not empty`)
+	}
+}
+
+func TestMergeCounters(t *testing.T) {
+	rnd := rand.New(rand.NewSource(15))
+	want := make(map[uint64]int)
+	var shards []*Counter[uint64, int]
+	for s:=0; s<7; s++ {
+		c := NewCounter[uint64, int](nil)
+		for i:=0; i<300; i++ {
+			k := uint64(rnd.Intn(200))
+			c.Add(k, 2)
+			want[k] += 2
+		}
+		c.Build()
+		shards = append(shards, c)
+	}
+	m := MergeCounters(shards...)
+	if m.Len() != len(want) {
+		t.Fatal(m.Len(), len(want))
+	}
+	keys := m.Keys()
+	for i, k := range keys {
+		if i > 0 && keys[i-1] >= k {
+			t.Fatal(`keys are not sorted`, keys)
+		}
+		if n, ok := m.Find(k); !ok || n != want[k] {
+			t.Fatal(k, n, want[k])
+		}
+	}
+	// The Counter types through their own MergeCounters
+	var us []*CounterUint64
+	for _, shard := range shards {
+		us = append(us, &CounterUint64{*shard})
+	}
+	mu := MergeCountersUint64(us...)
+	if raw := mu.RawKey(); len(raw) != len(keys) || mu.KeyUint64().Len() != len(keys) {
+		t.Fatal(len(raw), len(keys))
+	}
+	for _, k := range keys {
+		if n, _ := mu.Find(k); n != want[k] {
+			t.Fatal(k, n, want[k])
+		}
+	}
+	if MergeCountersInt16().Len() != 0 {
+		t.Fatal(`merging nothing is not empty`)
+	}
+}
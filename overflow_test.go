@@ -0,0 +1,138 @@
+package binsearch
+
+import (
+ "bytes"
+ "math/rand"
+ "testing"
+)
+
+// overflowKeys returns distinct keys either side of the 64 byte limit of the length classes.
+func overflowKeys() [][]byte {
+	keys := [][]byte{[]byte(`abc`), bytes.Repeat([]byte{'z'}, 70), bytes.Repeat([]byte{'a'}, 100), []byte(`q`), bytes.Repeat([]byte{'m'}, 65), bytes.Repeat([]byte{'m'}, 64), append(bytes.Repeat([]byte{'m'}, 64), 0)}
+	rnd := rand.New(rand.NewSource(2))
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		seen[string(k)] = true
+	}
+	for len(keys) < 300 {
+		k := make([]byte, 1+rnd.Intn(200))
+		for i := range k {
+			k[i] = byte('a' + rnd.Intn(3))
+		}
+		if !seen[string(k)] {
+			seen[string(k)] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func TestKeyBytesOverflow(t *testing.T) {
+	keys := overflowKeys()
+	k := new(KeyBytes)
+	for _, x := range keys {
+		if err := k.AddUnsorted(x); err != nil {
+			t.Fatal(err)
+		}
+	}
+	imap, err := k.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, x := range keys {
+		if j, ok := k.Find(x); !ok || imap[j] != i {
+			t.Fatal(i, j, ok)
+		}
+	}
+	if _, ok := k.Find(bytes.Repeat([]byte{'z'}, 71)); ok {
+		t.Fatal(`found a key that was not added`)
+	}
+	all := k.Keys()
+	if len(all) != len(keys) {
+		t.Fatal(len(all))
+	}
+	for i, x := range all {
+		if !bytes.Equal(x, keys[imap[i]]) {
+			t.Fatalf(`Keys()[%d] = %q`, i, x)
+		}
+	}
+	n := 0
+	if k.Reset() {
+		for {
+			x, eof := k.Next()
+			if !bytes.Equal(x, all[n]) {
+				t.Fatalf(`Next %d = %q`, n, x)
+			}
+			n++
+			if eof {
+				break
+			}
+		}
+	}
+	if n != len(keys) {
+		t.Fatal(n)
+	}
+	k2 := new(KeyBytes)
+	roundTrip(t, k.Write, k2.Read)
+	for _, x := range keys {
+		a, _ := k.Find(x)
+		if b, ok := k2.Find(x); !ok || a != b {
+			t.Fatalf(`%q not found after Read`, x)
+		}
+	}
+	k3 := new(KeyBytes)
+	for _, x := range keys {
+		if _, ok := k3.Add(x); ok {
+			t.Fatalf(`%q reported as already present`, x)
+		}
+	}
+	for i, x := range keys {
+		if j, ok := k3.Find(x); !ok || imap[j] != i {
+			t.Fatal(i, j, ok)
+		}
+	}
+}
+
+func TestCounterBytesOverflow(t *testing.T) {
+	keys := overflowKeys()
+	k := new(KeyBytes)
+	c := new(CounterBytes)
+	for i, x := range keys {
+		k.AddUnsorted(x)
+		c.Add(x, 1)
+		c.Add(x, i)
+	}
+	imap, _ := k.Build()
+	c.Build()
+	for i, x := range keys {
+		if v, ok := c.Find(x); !ok || v != i+1 {
+			t.Fatal(i, v, ok)
+		}
+	}
+	kb := c.KeyBytes()
+	for i, x := range keys {
+		if j, ok := kb.Find(x); !ok || imap[j] != i {
+			t.Fatal(i, j, ok)
+		}
+	}
+	c2 := new(CounterBytes)
+	roundTrip(t, c.Write, c2.Read)
+	kv := c2.KeyValBytes()
+	for i, x := range keys {
+		if v, ok := kv.Find(x); !ok || v != i+1 {
+			t.Fatal(i, v, ok)
+		}
+	}
+	kv2 := new(KeyValBytes)
+	roundTrip(t, kv.Write, kv2.Read)
+	for i, x := range keys {
+		if v, ok := kv2.Find(x); !ok || v != i+1 {
+			t.Fatal(i, v, ok)
+		}
+	}
+	long := bytes.Repeat([]byte{'b'}, 80)
+	kv2.Add(long, 1000)
+	if v, ok := kv2.Find(long); !ok || v != 1000 || kv2.Len() != len(keys)+1 || kv2.GreatestVal() != 1000 {
+		t.Fatal(v, ok, kv2.Len())
+	}
+}
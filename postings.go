@@ -0,0 +1,323 @@
+package binsearch
+
+import (
+ "github.com/AlasdairF/BinSearch/Ordered"
+ "github.com/AlasdairF/Custom"
+ "bytes"
+ "cmp"
+ "slices"
+)
+
+/*
+	KeyPostingsBytes, KeyPostingsUint64 and KeyPostingsUint32 map each key to a list of ints, e.g. a word to the IDs of the documents it is in.
+	Add the same key as many times as needed with AddUnsorted, then Build groups them into one sorted list of unique IDs per key.
+	The lists are written as the number of IDs, the first ID and then the difference between each ID and the one before.
+*/
+
+// ---------- KeyPostingsBytes ----------
+
+// Add this to any struct to make it binary searchable.
+type KeyPostingsBytes struct {
+ child KeyBytes
+ pending KeyBytes // the keys added with AddUnsorted, until Build
+ ids []int // the ID of each key in pending
+ postings [][]int
+ cursor int
+}
+
+func (t *KeyPostingsBytes) Len() int {
+	return t.child.Len()
+}
+
+// Find returns the list of IDs for the key. Only use after Build.
+func (t *KeyPostingsBytes) Find(thekey []byte) ([]int, bool) {
+	if i, ok := t.child.Find(thekey); ok {
+		return t.postings[i], true
+	}
+	return nil, false
+}
+
+// AddUnsorted adds this key and ID for later building with Build. The same key can be added any number of times.
+func (t *KeyPostingsBytes) AddUnsorted(thekey []byte, id int) error {
+	if err := t.pending.AddUnsorted(thekey); err != nil {
+		return err
+	}
+	t.ids = append(t.ids, id)
+	return nil
+}
+
+// Build groups the IDs of each key. It can be run again after more AddUnsorted.
+func (t *KeyPostingsBytes) Build() error {
+	if len(t.ids) == 0 {
+		return nil
+	}
+	// The keys that are already built are added again with each of their IDs, as KeyBytes can only be built once
+	if t.child.Reset() {
+		for i:=0; ; i++ {
+			key, eof := t.child.Next()
+			for _, id := range t.postings[i] {
+				if err := t.pending.AddUnsorted(key); err != nil {
+					return err
+				}
+				t.ids = append(t.ids, id)
+			}
+			if eof {
+				break
+			}
+		}
+	}
+	t.child, t.pending = t.pending, KeyBytes{}
+	imap, err := t.child.Build()
+	if err != nil {
+		return err
+	}
+	var on, run int
+	postings := make([][]int, 0, len(imap))
+	for run=0; run<8; run++ {
+		t.child.limit8[run], on, postings = groupTier(t.child.limit8[run], on, imap, t.ids, postings)
+	}
+	for run=0; run<8; run++ {
+		t.child.limit16[run], on, postings = groupTier(t.child.limit16[run], on, imap, t.ids, postings)
+	}
+	for run=0; run<8; run++ {
+		t.child.limit24[run], on, postings = groupTier(t.child.limit24[run], on, imap, t.ids, postings)
+	}
+	for run=0; run<8; run++ {
+		t.child.limit32[run], on, postings = groupTier(t.child.limit32[run], on, imap, t.ids, postings)
+	}
+	for run=0; run<8; run++ {
+		t.child.limit40[run], on, postings = groupTier(t.child.limit40[run], on, imap, t.ids, postings)
+	}
+	for run=0; run<8; run++ {
+		t.child.limit48[run], on, postings = groupTier(t.child.limit48[run], on, imap, t.ids, postings)
+	}
+	for run=0; run<8; run++ {
+		t.child.limit56[run], on, postings = groupTier(t.child.limit56[run], on, imap, t.ids, postings)
+	}
+	for run=0; run<8; run++ {
+		t.child.limit64[run], on, postings = groupTier(t.child.limit64[run], on, imap, t.ids, postings)
+	}
+	// The overflow keys are slices so they are compared with bytes.Equal
+	var n int
+	tier := t.child.overflow
+	for i, k := range tier {
+		id := t.ids[imap[on + i]]
+		if n > 0 && bytes.Equal(k, tier[n-1]) {
+			postings[len(postings)-1] = append(postings[len(postings)-1], id)
+			continue
+		}
+		tier[n] = k
+		n++
+		postings = append(postings, []int{id})
+	}
+	t.child.overflow = tier[0:n]
+	t.child.recount()
+	
+	for i, v := range postings {
+		postings[i] = sortPostings(v)
+	}
+	t.postings = postings
+	t.ids = nil
+	return nil
+}
+
+func (t *KeyPostingsBytes) Reset() bool {
+	t.cursor = 0
+	return t.child.Reset()
+}
+
+func (t *KeyPostingsBytes) Next() ([]byte, []int, bool) {
+	a, eof := t.child.Next()
+	v := t.postings[t.cursor]
+	if t.cursor++; eof {
+		t.cursor = 0
+	}
+	return a, v, eof
+}
+
+func (t *KeyPostingsBytes) Keys() [][]byte {
+	return t.child.Keys()
+}
+
+func (t *KeyPostingsBytes) Write(w custom.Interface) {
+	t.child.Write(w)
+	for _, v := range t.postings {
+		writePostings(w, v)
+	}
+}
+
+func (t *KeyPostingsBytes) Read(r *custom.Reader) {
+	t.child.Read(r)
+	l := t.child.Len()
+	tmp := make([][]int, l)
+	for i:=0; i<l; i++ {
+		tmp[i] = readPostings(r)
+	}
+	t.postings = tmp
+}
+
+// ---------- KeyPostings ----------
+
+type KeyPostingsUint64 = KeyPostings[uint64]
+type KeyPostingsUint32 = KeyPostings[uint32]
+
+// Add this to any struct to make it binary searchable.
+type KeyPostings[K cmp.Ordered] struct {
+ key []K
+ postings [][]int
+ pending []sortOrdered.KeyVal[K, int] // K is the ID, V is the key
+ cursor int
+}
+
+func (t *KeyPostings[K]) Len() int {
+	return len(t.key)
+}
+
+// Find returns the list of IDs for the key. Only use after Build.
+func (t *KeyPostings[K]) Find(thekey K) ([]int, bool) {
+	var min, at int
+	var current K
+	max := len(t.key) - 1
+	for min <= max {
+		at = min + ((max - min) / 2)
+		if current=t.key[at]; thekey < current {
+			max = at - 1
+		} else {
+		if thekey > current {
+			min = at + 1
+			} else {
+				return t.postings[at], true // found
+			}
+		}
+	}
+	return nil, false // doesn't exist
+}
+
+// AddUnsorted adds this key and ID for later building with Build. The same key can be added any number of times.
+func (t *KeyPostings[K]) AddUnsorted(thekey K, id int) {
+	t.pending = append(t.pending, sortOrdered.KeyVal[K, int]{id, thekey})
+}
+
+// Build groups the IDs of each key. It can be run again after more AddUnsorted.
+func (t *KeyPostings[K]) Build() {
+	if len(t.pending) == 0 {
+		return
+	}
+	temp := t.pending
+	for i, k := range t.key {
+		for _, id := range t.postings[i] {
+			temp = append(temp, sortOrdered.KeyVal[K, int]{id, k})
+		}
+	}
+	sortOrdered.Asc(temp)
+	key := make([]K, 0, len(t.key) + 1)
+	postings := make([][]int, 0, len(t.key) + 1)
+	for i, obj := range temp {
+		if i > 0 && obj.V == key[len(key)-1] {
+			postings[len(postings)-1] = append(postings[len(postings)-1], obj.K)
+			continue
+		}
+		key = append(key, obj.V)
+		postings = append(postings, []int{obj.K})
+	}
+	for i, v := range postings {
+		postings[i] = sortPostings(v)
+	}
+	t.key = key
+	t.postings = postings
+	t.pending = nil
+}
+
+func (t *KeyPostings[K]) Reset() bool {
+	t.cursor = 0
+	if len(t.key) == 0 {
+		return false
+	}
+	return true
+}
+
+func (t *KeyPostings[K]) Next() (K, []int, bool) {
+	k, v := t.key[t.cursor], t.postings[t.cursor]
+	if t.cursor++; t.cursor == len(t.key) {
+		t.cursor = 0
+		return k, v, true
+	}
+	return k, v, false
+}
+
+func (t *KeyPostings[K]) Keys() []K {
+	keys := make([]K, len(t.key))
+	copy(keys, t.key)
+	return keys
+}
+
+func (t *KeyPostings[K]) Write(w custom.Interface) {
+	write := orderedWriter[K]()
+	w.WriteUint64Variable(uint64(len(t.key)))
+	for i, v := range t.key {
+		write(w, v)
+		writePostings(w, t.postings[i])
+	}
+}
+
+func (t *KeyPostings[K]) Read(r *custom.Reader) {
+	read := orderedReader[K]()
+	l := int(r.ReadUint64Variable())
+	key := make([]K, l)
+	postings := make([][]int, l)
+	for i:=0; i<l; i++ {
+		key[i] = read(r)
+		postings[i] = readPostings(r)
+	}
+	t.key = key
+	t.postings = postings
+}
+
+// ---------- postings helpers ----------
+
+// groupTier removes the duplicate keys from a built KeyBytes tier, adding the ID of each one to the postings of the key that is kept.
+// on is the index the tier started at before any duplicates were removed, and the next on is returned.
+func groupTier[T comparable](tier []T, on int, imap []int, ids []int, postings [][]int) ([]T, int, [][]int) {
+	var n int
+	for i, k := range tier {
+		id := ids[imap[on + i]]
+		if n > 0 && k == tier[n-1] {
+			postings[len(postings)-1] = append(postings[len(postings)-1], id)
+			continue
+		}
+		tier[n] = k
+		n++
+		postings = append(postings, []int{id})
+	}
+	return tier[0:n], on + len(tier), postings
+}
+
+// sortPostings sorts the IDs and removes duplicates.
+func sortPostings(ids []int) []int {
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+func writePostings(w custom.Interface, ids []int) {
+	w.WriteUint64Variable(uint64(len(ids)))
+	var last int
+	for i, id := range ids {
+		if i == 0 {
+			w.WriteUint64Variable(uint64(id))
+		} else {
+			w.WriteUint64Variable(uint64(id - last))
+		}
+		last = id
+	}
+}
+
+func readPostings(r *custom.Reader) []int {
+	l := int(r.ReadUint64Variable())
+	ids := make([]int, l)
+	var last int
+	for i:=0; i<l; i++ {
+		last += int(r.ReadUint64Variable())
+		ids[i] = last
+	}
+	return ids
+}
@@ -0,0 +1,160 @@
+package binsearch
+
+import (
+ "math/rand"
+ "slices"
+ "strings"
+ "testing"
+)
+
+// wantPostings adds id to the sorted unique IDs of key.
+func wantPostings[K comparable](want map[K][]int, key K, id int) {
+	ids := want[key]
+	if i, found := slices.BinarySearch(ids, id); !found {
+		want[key] = slices.Insert(ids, i, id)
+	}
+}
+
+func TestKeyPostingsBytes(t *testing.T) {
+	words := []string{``, `a`, `the`, `somewhatlongerword`, strings.Repeat(`l`, 70), "\x00the"}
+	rnd := rand.New(rand.NewSource(11))
+	p := new(KeyPostingsBytes)
+	want := make(map[string][]int)
+	for i:=0; i<500; i++ {
+		w := words[rnd.Intn(len(words))]
+		id := rnd.Intn(100)
+		if err := p.AddUnsorted([]byte(w), id); err != nil {
+			t.Fatal(err)
+		}
+		wantPostings(want, w, id)
+	}
+	if err := p.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Len() != len(want) {
+		t.Fatal(p.Len())
+	}
+	p2 := new(KeyPostingsBytes)
+	roundTrip(t, p.Write, p2.Read)
+	for w, ids := range want {
+		if got, ok := p.Find([]byte(w)); !ok || !slices.Equal(got, ids) {
+			t.Fatalf(`%q = %v, want %v`, w, got, ids)
+		}
+		if got, ok := p2.Find([]byte(w)); !ok || !slices.Equal(got, ids) {
+			t.Fatalf(`%q = %v after Read, want %v`, w, got, ids)
+		}
+	}
+	if _, ok := p2.Find([]byte(`missing`)); ok {
+		t.Fatal(`found a key that was not added`)
+	}
+	n := 0
+	if p2.Reset() {
+		for {
+			w, ids, eof := p2.Next()
+			if !slices.Equal(ids, want[string(w)]) {
+				t.Fatalf(`Next %q = %v`, w, ids)
+			}
+			n++
+			if eof {
+				break
+			}
+		}
+	}
+	if n != len(want) {
+		t.Fatal(n)
+	}
+	// The keys are in the same order as a KeyBytes of the same keys.
+	kb := new(KeyBytes)
+	for w := range want {
+		kb.Add([]byte(w))
+	}
+	for i, w := range p2.Keys() {
+		if j, _ := kb.Find(w); i != j {
+			t.Fatalf(`%q at %d, KeyBytes has it at %d`, w, i, j)
+		}
+	}
+}
+
+func TestKeyPostings(t *testing.T) {
+	rnd := rand.New(rand.NewSource(12))
+	g := new(KeyPostingsUint32)
+	want := make(map[uint32][]int)
+	for round:=0; round<3; round++ {
+		for i:=0; i<300; i++ {
+			k := uint32(rnd.Intn(40))
+			id := rnd.Intn(200) - 100
+			g.AddUnsorted(k, id)
+			wantPostings(want, k, id)
+		}
+		g.Build() // Build again after more AddUnsorted
+		for k, ids := range want {
+			if got, ok := g.Find(k); !ok || !slices.Equal(got, ids) {
+				t.Fatal(round, k, got, ids)
+			}
+		}
+	}
+	g2 := new(KeyPostingsUint32)
+	roundTrip(t, g.Write, g2.Read)
+	for k, ids := range want {
+		if got, ok := g2.Find(k); !ok || !slices.Equal(got, ids) {
+			t.Fatal(k, got, ids)
+		}
+	}
+	if keys := g2.Keys(); len(keys) != len(want) || !slices.IsSorted(keys) {
+		t.Fatal(keys)
+	}
+	u := new(KeyPostingsUint64)
+	u.AddUnsorted(1 << 63, 7)
+	u.AddUnsorted(1 << 63, 3)
+	u.AddUnsorted(0, 7)
+	u.Build()
+	u2 := new(KeyPostingsUint64)
+	roundTrip(t, u.Write, u2.Read)
+	if got, _ := u2.Find(1 << 63); !slices.Equal(got, []int{3, 7}) {
+		t.Fatal(got)
+	}
+}
+
+func TestKeyPostingsBuildTwice(t *testing.T) {
+	// Both kinds Build again after more AddUnsorted, and a Build with nothing added changes nothing
+	words := []string{`a`, `b`, `the`, strings.Repeat(`l`, 70), ``}
+	rnd := rand.New(rand.NewSource(13))
+	p := new(KeyPostingsBytes)
+	g := new(KeyPostings[string])
+	want := make(map[string][]int)
+	for round:=0; round<3; round++ {
+		for i:=0; i<100; i++ {
+			w := words[rnd.Intn(len(words) - 2 + round)] // new keys in later rounds
+			id := rnd.Intn(300)
+			p.AddUnsorted([]byte(w), id)
+			g.AddUnsorted(w, id)
+			wantPostings(want, w, id)
+		}
+		for j:=0; j<2; j++ {
+			if err := p.Build(); err != nil {
+				t.Fatal(round, err)
+			}
+			g.Build()
+			if p.Len() != len(want) || g.Len() != len(want) {
+				t.Fatal(round, p.Len(), g.Len(), len(want))
+			}
+			for w, ids := range want {
+				if got, ok := p.Find([]byte(w)); !ok || !slices.Equal(got, ids) {
+					t.Fatalf(`round %d: %q = %v, want %v`, round, w, got, ids)
+				}
+				if got, ok := g.Find(w); !ok || !slices.Equal(got, ids) {
+					t.Fatalf(`round %d: KeyPostings %q = %v, want %v`, round, w, got, ids)
+				}
+			}
+		}
+	}
+	p2 := new(KeyPostingsBytes)
+	roundTrip(t, p.Write, p2.Read)
+	p2.AddUnsorted([]byte(`new`), 5)
+	if err := p2.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := p2.Find([]byte(`new`)); !ok || p2.Len() != len(want) + 1 || !slices.Equal(got, []int{5}) {
+		t.Fatal(`Build after Read`, got, p2.Len())
+	}
+}
@@ -0,0 +1,348 @@
+package binsearch
+
+import (
+ "bytes"
+ "cmp"
+ "slices"
+ "sort"
+)
+
+/*
+	PrefixRange and CountPrefix find the keys that start with a prefix, e.g. for autocomplete.
+	The keys are in a different length class for each length, but within each class those with the prefix are together,
+	between the prefix padded with zeros and the prefix padded with 0xFF to that length. So each class is one binary search.
+	The runes types search for the encoded prefix. In the legacy encoding U+0002 & U+0003 are written as the escape codes, so for a prefix with them
+	a key can start with the encoded prefix and not with prefix, and each of those keys is decoded to check it.
+*/
+
+// prefixSource is a structure with 64 length classes and the overflow, KeyBytes, KeyValBytes or CounterBytes.
+type prefixSource interface {
+	classLen(on int) int
+	classEntry(on, at int) ([]byte, int)
+	classCompare(on, at int, words [8]uint64) int // compares the key with words, only for on < 64
+	classWords(on, at int) [8]uint64 // only for on < 64
+}
+
+// eachPrefixSpan calls fn with each span of indexes [from, to) of keys that start with prefix, in order.
+func eachPrefixSpan(src prefixSource, total int, prefix []byte, fn func(int, int)) {
+	if len(prefix) == 0 {
+		if total > 0 {
+			fn(0, total)
+		}
+		return
+	}
+	eachPrefixClass(src, prefix, func(on, start, from, to int) bool {
+		fn(start + from, start + to)
+		return true
+	})
+}
+
+// eachPrefixClass calls fn with each length class on that has keys starting with prefix, the index of the first key in the class,
+// and the positions [from, to) of those keys in the class, in order. It stops if fn returns false.
+func eachPrefixClass(src prefixSource, prefix []byte, fn func(int, int, int, int) bool) {
+	var lo, hi [64]byte
+	copy(lo[:], prefix)
+	copy(hi[:], prefix)
+	for i:=len(prefix); i<64; i++ {
+		hi[i] = 255
+	}
+	var start, from, to int
+	for on:=0; on<65; on++ {
+		n := src.classLen(on)
+		if n == 0 {
+			continue
+		}
+		if len(prefix) == 0 {
+			from, to = 0, n
+		} else if on == 64 {
+			from = sort.Search(n, func(i int) bool {
+				key, _ := src.classEntry(64, i)
+				return bytes.Compare(key, prefix) >= 0
+			})
+			to = from + sort.Search(n - from, func(i int) bool {
+				key, _ := src.classEntry(64, from + i)
+				return !bytes.HasPrefix(key, prefix)
+			})
+		} else {
+			l := (on >> 3) * 8 + (on & 7) + 1 // the length of the keys in this class
+			if l < len(prefix) {
+				start += n
+				continue
+			}
+			low, _ := splitKey(lo[0:l])
+			high, _ := splitKey(hi[0:l])
+			from = sort.Search(n, func(i int) bool {
+				return src.classCompare(on, i, low) >= 0
+			})
+			to = sort.Search(n, func(i int) bool {
+				return src.classCompare(on, i, high) > 0
+			})
+		}
+		if to > from && !fn(on, start, from, to) {
+			return
+		}
+		start += n
+	}
+}
+
+// ---------- KeyBytes ----------
+
+func (t *KeyBytes) classCompare(on, at int, words [8]uint64) int {
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			return cmp.Compare(t.limit8[run][at], words[0])
+		case 1:
+			return compareWords(t.limit16[run][at][0:2], words[0:2])
+		case 2:
+			return compareWords(t.limit24[run][at][0:3], words[0:3])
+		case 3:
+			return compareWords(t.limit32[run][at][0:4], words[0:4])
+		case 4:
+			return compareWords(t.limit40[run][at][0:5], words[0:5])
+		case 5:
+			return compareWords(t.limit48[run][at][0:6], words[0:6])
+		case 6:
+			return compareWords(t.limit56[run][at][0:7], words[0:7])
+		default:
+			return compareWords(t.limit64[run][at][0:8], words[0:8])
+	}
+}
+
+// PrefixRange returns the spans of indexes [from, to) of the keys that start with prefix, in the order of Next.
+func (t *KeyBytes) PrefixRange(prefix []byte) [][2]int {
+	var spans [][2]int
+	eachPrefixSpan(t, t.total, prefix, func(from, to int) {
+		if l := len(spans) - 1; l >= 0 && spans[l][1] == from {
+			spans[l][1] = to
+		} else {
+			spans = append(spans, [2]int{from, to})
+		}
+	})
+	return spans
+}
+
+// CountPrefix returns the number of keys that start with prefix.
+func (t *KeyBytes) CountPrefix(prefix []byte) int {
+	var l int
+	eachPrefixSpan(t, t.total, prefix, func(from, to int) {
+		l += to - from
+	})
+	return l
+}
+
+// ---------- KeyValBytes ----------
+
+func (t *KeyValBytes) classCompare(on, at int, words [8]uint64) int {
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			return cmp.Compare(t.limit8[run][at][0], words[0])
+		case 1:
+			return compareWords(t.limit16[run][at][0:2], words[0:2])
+		case 2:
+			return compareWords(t.limit24[run][at][0:3], words[0:3])
+		case 3:
+			return compareWords(t.limit32[run][at][0:4], words[0:4])
+		case 4:
+			return compareWords(t.limit40[run][at][0:5], words[0:5])
+		case 5:
+			return compareWords(t.limit48[run][at][0:6], words[0:6])
+		case 6:
+			return compareWords(t.limit56[run][at][0:7], words[0:7])
+		default:
+			return compareWords(t.limit64[run][at][0:8], words[0:8])
+	}
+}
+
+// PrefixRange returns the spans of indexes [from, to) of the keys that start with prefix, in the order of Next.
+func (t *KeyValBytes) PrefixRange(prefix []byte) [][2]int {
+	var spans [][2]int
+	eachPrefixSpan(t, t.total, prefix, func(from, to int) {
+		if l := len(spans) - 1; l >= 0 && spans[l][1] == from {
+			spans[l][1] = to
+		} else {
+			spans = append(spans, [2]int{from, to})
+		}
+	})
+	return spans
+}
+
+// CountPrefix returns the number of keys that start with prefix.
+func (t *KeyValBytes) CountPrefix(prefix []byte) int {
+	var l int
+	eachPrefixSpan(t, t.total, prefix, func(from, to int) {
+		l += to - from
+	})
+	return l
+}
+
+// ---------- CounterBytes ----------
+
+func (t *CounterBytes) classCompare(on, at int, words [8]uint64) int {
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			return cmp.Compare(t.limit8[run][at][0], words[0])
+		case 1:
+			return compareWords(t.limit16[run][at][0:2], words[0:2])
+		case 2:
+			return compareWords(t.limit24[run][at][0:3], words[0:3])
+		case 3:
+			return compareWords(t.limit32[run][at][0:4], words[0:4])
+		case 4:
+			return compareWords(t.limit40[run][at][0:5], words[0:5])
+		case 5:
+			return compareWords(t.limit48[run][at][0:6], words[0:6])
+		case 6:
+			return compareWords(t.limit56[run][at][0:7], words[0:7])
+		default:
+			return compareWords(t.limit64[run][at][0:8], words[0:8])
+	}
+}
+
+// PrefixRange returns the spans of indexes [from, to) of the keys that start with prefix, in the order of Next.
+func (t *CounterBytes) PrefixRange(prefix []byte) [][2]int {
+	var spans [][2]int
+	eachPrefixSpan(t, t.total, prefix, func(from, to int) {
+		if l := len(spans) - 1; l >= 0 && spans[l][1] == from {
+			spans[l][1] = to
+		} else {
+			spans = append(spans, [2]int{from, to})
+		}
+	})
+	return spans
+}
+
+// CountPrefix returns the number of keys that start with prefix.
+func (t *CounterBytes) CountPrefix(prefix []byte) int {
+	var l int
+	eachPrefixSpan(t, t.total, prefix, func(from, to int) {
+		l += to - from
+	})
+	return l
+}
+
+// ---------- Runes ----------
+
+// eachRunesPrefixSpan is eachPrefixSpan for the runes types.
+func eachRunesPrefixSpan(src prefixSource, total int, prefix []rune, legacy bool, fn func(int, int)) {
+	if !legacy || !slices.ContainsFunc(prefix, func(r rune) bool { return r == 2 || r == 3 }) {
+		eachPrefixSpan(src, total, encodeRunes(prefix, legacy), fn)
+		return
+	}
+	buf := make([]rune, 0, 64)
+	eachPrefixClass(src, encodeRunes(prefix, true), func(on, start, from, to int) bool {
+		for at:=from; at<to; at++ {
+			key, _ := src.classEntry(on, at)
+			if buf = appendDecodedRunes(buf[:0], key, true); len(buf) >= len(prefix) && slices.Equal(buf[0:len(prefix)], prefix) {
+				fn(start + at, start + at + 1)
+			}
+		}
+		return true
+	})
+}
+
+// appendDecodedRunes appends the runes of word to dst (see bytes2runes & bytes2runesLegacy), so that one buffer can be reused for all the keys.
+func appendDecodedRunes(dst []rune, word []byte, legacy bool) []rune {
+	for i:=0; i<len(word); i++ {
+		switch b := word[i]; {
+			case b == 1 && !legacy:
+				dst = append(dst, rune(word[i+1]))
+				i++
+			case b == 2:
+				dst = append(dst, (rune(word[i+2]) * 256) + rune(word[i+1]))
+				i += 2
+			case b == 3:
+				dst = append(dst, (rune(word[i+3]) * 65536) + (rune(word[i+2]) * 256) + rune(word[i+1]))
+				i += 3
+			default:
+				dst = append(dst, rune(b))
+		}
+	}
+	return dst
+}
+
+// runesPrefixRange is PrefixRange for the runes types.
+func runesPrefixRange(src prefixSource, total int, prefix []rune, legacy bool) [][2]int {
+	var spans [][2]int
+	eachRunesPrefixSpan(src, total, prefix, legacy, func(from, to int) {
+		if l := len(spans) - 1; l >= 0 && spans[l][1] == from {
+			spans[l][1] = to
+		} else {
+			spans = append(spans, [2]int{from, to})
+		}
+	})
+	return spans
+}
+
+// runesCountPrefix is CountPrefix for the runes types.
+func runesCountPrefix(src prefixSource, total int, prefix []rune, legacy bool) int {
+	var l int
+	eachRunesPrefixSpan(src, total, prefix, legacy, func(from, to int) {
+		l += to - from
+	})
+	return l
+}
+
+// PrefixRange returns the spans of indexes [from, to) of the keys that start with prefix, in the order of Next.
+func (t *KeyRunes) PrefixRange(prefix []rune) [][2]int {
+	return runesPrefixRange(&t.child, t.child.total, prefix, t.legacy)
+}
+
+// CountPrefix returns the number of keys that start with prefix.
+func (t *KeyRunes) CountPrefix(prefix []rune) int {
+	return runesCountPrefix(&t.child, t.child.total, prefix, t.legacy)
+}
+
+// PrefixRange returns the spans of indexes [from, to) of the keys that start with prefix, in the order of Next.
+func (t *KeyValRunes) PrefixRange(prefix []rune) [][2]int {
+	return runesPrefixRange(&t.child, t.child.total, prefix, t.legacy)
+}
+
+// CountPrefix returns the number of keys that start with prefix.
+func (t *KeyValRunes) CountPrefix(prefix []rune) int {
+	return runesCountPrefix(&t.child, t.child.total, prefix, t.legacy)
+}
+
+// PrefixRange returns the spans of indexes [from, to) of the keys that start with prefix, in the order of Next.
+func (t *CounterRunes) PrefixRange(prefix []rune) [][2]int {
+	return runesPrefixRange(&t.child, t.child.total, prefix, t.legacy)
+}
+
+// CountPrefix returns the number of keys that start with prefix.
+func (t *CounterRunes) CountPrefix(prefix []rune) int {
+	return runesCountPrefix(&t.child, t.child.total, prefix, t.legacy)
+}
+
+// ---------- String ----------
+
+// PrefixRange returns the spans of indexes [from, to) of the keys that start with prefix, in the order of Next.
+func (t *KeyString) PrefixRange(prefix string) [][2]int {
+	return t.child.PrefixRange(stringBytes(prefix))
+}
+
+// CountPrefix returns the number of keys that start with prefix.
+func (t *KeyString) CountPrefix(prefix string) int {
+	return t.child.CountPrefix(stringBytes(prefix))
+}
+
+// PrefixRange returns the spans of indexes [from, to) of the keys that start with prefix, in the order of Next.
+func (t *KeyValString) PrefixRange(prefix string) [][2]int {
+	return t.child.PrefixRange(stringBytes(prefix))
+}
+
+// CountPrefix returns the number of keys that start with prefix.
+func (t *KeyValString) CountPrefix(prefix string) int {
+	return t.child.CountPrefix(stringBytes(prefix))
+}
+
+// PrefixRange returns the spans of indexes [from, to) of the keys that start with prefix, in the order of Next.
+func (t *CounterString) PrefixRange(prefix string) [][2]int {
+	return t.child.PrefixRange(stringBytes(prefix))
+}
+
+// CountPrefix returns the number of keys that start with prefix.
+func (t *CounterString) CountPrefix(prefix string) int {
+	return t.child.CountPrefix(stringBytes(prefix))
+}
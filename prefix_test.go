@@ -0,0 +1,177 @@
+package binsearch
+
+import (
+ "bytes"
+ "math/rand"
+ "slices"
+ "strings"
+ "testing"
+ "github.com/AlasdairF/Custom"
+)
+
+func TestKeyBytesPrefix(t *testing.T) {
+	rnd := rand.New(rand.NewSource(20))
+	k := new(KeyBytes)
+	kv := new(KeyValBytes)
+	c := new(CounterBytes)
+	seen := map[string]bool{``: true}
+	keys := [][]byte{{}}
+	for i:=0; i<3000; i++ {
+		x := make([]byte, rnd.Intn(100))
+		for j := range x {
+			x[j] = []byte{0, 'a', 'b', 255}[rnd.Intn(4)]
+		}
+		if !seen[string(x)] {
+			seen[string(x)] = true
+			keys = append(keys, x)
+		}
+	}
+	for i, x := range keys {
+		k.AddUnsorted(x)
+		kv.AddUnsorted(x, i)
+		c.Add(x, 1)
+	}
+	k.Build()
+	kv.Build()
+	c.Build()
+	type prefixer interface {
+	 Keys() [][]byte
+	 PrefixRange([]byte) [][2]int
+	 CountPrefix([]byte) int
+	}
+	for trial:=0; trial<300; trial++ {
+		var p []byte
+		if trial > 0 {
+			x := keys[rnd.Intn(len(keys))]
+			p = x[:rnd.Intn(len(x) + 1)]
+		}
+		if trial % 7 == 0 {
+			p = append(slices.Clone(p), 'a')
+		}
+		want := 0
+		for _, x := range keys {
+			if bytes.HasPrefix(x, p) {
+				want++
+			}
+		}
+		for _, st := range []prefixer{k, kv, c} {
+			if n := st.CountPrefix(p); n != want {
+				t.Fatalf(`%T.CountPrefix(%q) = %d, want %d`, st, p, n, want)
+			}
+			all := st.Keys()
+			got := 0
+			for _, span := range st.PrefixRange(p) {
+				for i := span[0]; i < span[1]; i++ {
+					if !bytes.HasPrefix(all[i], p) {
+						t.Fatalf(`%T.PrefixRange(%q) includes %q`, st, p, all[i])
+					}
+					got++
+				}
+			}
+			if got != want {
+				t.Fatalf(`%T.PrefixRange(%q) spans %d keys, want %d`, st, p, got, want)
+			}
+		}
+	}
+}
+
+func TestKeyRunesPrefix(t *testing.T) {
+	words := []string{`héllo`, `hé`, "h\x02x", `h`, `中文`, `中`, `ÿ`, ``}
+	r := new(KeyRunes)
+	c := new(CounterRunes)
+	for _, x := range words {
+		r.AddUnsorted([]rune(x))
+		c.Add([]rune(x), 1)
+	}
+	r.Build()
+	c.Build()
+	all := r.Keys()
+	for _, p := range []string{``, `h`, `hé`, "h\x02", `中`, `ÿ`, `x`} {
+		want := 0
+		for _, x := range words {
+			if strings.HasPrefix(x, p) {
+				want++
+			}
+		}
+		if n := r.CountPrefix([]rune(p)); n != want {
+			t.Fatalf(`CountPrefix(%q) = %d, want %d`, p, n, want)
+		}
+		if n := c.CountPrefix([]rune(p)); n != want {
+			t.Fatalf(`CounterRunes.CountPrefix(%q) = %d, want %d`, p, n, want)
+		}
+		got := 0
+		for _, span := range r.PrefixRange([]rune(p)) {
+			for i := span[0]; i < span[1]; i++ {
+				if !strings.HasPrefix(string(all[i]), p) {
+					t.Fatalf(`PrefixRange(%q) includes %q`, p, string(all[i]))
+				}
+				got++
+			}
+		}
+		if got != want {
+			t.Fatalf(`PrefixRange(%q) spans %d keys, want %d`, p, got, want)
+		}
+	}
+}
+
+func TestKeyRunesPrefixLegacy(t *testing.T) {
+	// In a file written with the original encoding Ā is 2, 0, 1, so it starts with the encoding of U+0002 but its runes don't
+	words := []string{`Ā`, `Āb`, `ȁ`, `a`, `ab`, ``}
+	old := new(KeyBytes)
+	oldc := new(CounterBytes)
+	for _, x := range words {
+		old.AddUnsorted(runes2bytesLegacy([]rune(x)))
+		oldc.Add(runes2bytesLegacy([]rune(x)), 1)
+	}
+	old.Build()
+	oldc.Build()
+	r := new(KeyRunes)
+	c := new(CounterRunes)
+	roundTrip(t, func(w custom.Interface) {
+		old.Write(w)
+		oldc.Write(w)
+	}, func(rd *custom.Reader) {
+		r.Read(rd)
+		c.Read(rd)
+	})
+	if !r.legacy || !c.legacy {
+		t.Fatal(`not read as legacy files`)
+	}
+	all := r.Keys()
+	for _, p := range [][]rune{{2}, {2, 0}, {2, 0, 1}, {0x100}, {'a'}, {}} {
+		want := 0
+		for _, x := range words {
+			if strings.HasPrefix(x, string(p)) {
+				want++
+			}
+		}
+		if n := r.CountPrefix(p); n != want {
+			t.Fatalf(`CountPrefix(%U) = %d, want %d`, p, n, want)
+		}
+		if n := c.CountPrefix(p); n != want {
+			t.Fatalf(`CounterRunes.CountPrefix(%U) = %d, want %d`, p, n, want)
+		}
+		got := 0
+		for _, span := range r.PrefixRange(p) {
+			for i := span[0]; i < span[1]; i++ {
+				if !strings.HasPrefix(string(all[i]), string(p)) {
+					t.Fatalf(`PrefixRange(%U) includes %q`, p, string(all[i]))
+				}
+				got++
+			}
+		}
+		if got != want {
+			t.Fatalf(`PrefixRange(%U) spans %d keys, want %d`, p, got, want)
+		}
+	}
+}
+
+func TestKeyStringPrefix(t *testing.T) {
+	k := new(KeyString)
+	for _, x := range []string{`apple`, `app`, `apricot`, `banana`, ``} {
+		k.Add(x)
+	}
+	if k.CountPrefix(`ap`) != 3 || k.CountPrefix(`app`) != 2 || k.CountPrefix(``) != 5 || k.CountPrefix(`c`) != 0 {
+		t.Fatal(k.CountPrefix(`ap`), k.CountPrefix(`app`), k.CountPrefix(``))
+	}
+}
@@ -0,0 +1,191 @@
+package binsearch
+
+import (
+ "iter"
+)
+
+/*
+	Range queries on the generic Key, KeyVal & Counter, and so on KeyUint64, KeyValInt, CounterUint32, etc.
+	Between returns the span of indexes [from, to) of the keys in the range, and Range iterates over the keys in it without copying them.
+	Seek positions Next, so for lo <= key < hi you can Seek(lo) and then call Next until the key is >= hi.
+*/
+
+// ---------- Key ----------
+
+// LowerBound returns the index of the first key >= thekey, or Len() if there isn't one.
+func (t *Key[K]) LowerBound(thekey K) int {
+	return lowerBound(t.key, thekey)
+}
+
+// UpperBound returns the index of the first key > thekey, or Len() if there isn't one.
+func (t *Key[K]) UpperBound(thekey K) int {
+	return upperBound(t.key, thekey)
+}
+
+// Floor returns the greatest key <= thekey and its index.
+func (t *Key[K]) Floor(thekey K) (K, int, bool) {
+	i := upperBound(t.key, thekey) - 1
+	if i < 0 {
+		return *new(K), 0, false
+	}
+	return t.key[i], i, true
+}
+
+// Ceiling returns the smallest key >= thekey and its index.
+func (t *Key[K]) Ceiling(thekey K) (K, int, bool) {
+	i := lowerBound(t.key, thekey)
+	if i == len(t.key) {
+		return *new(K), 0, false
+	}
+	return t.key[i], i, true
+}
+
+// Between returns the span of indexes [from, to) of the keys >= lo and < hi.
+func (t *Key[K]) Between(lo, hi K) (int, int) {
+	from, to := lowerBound(t.key, lo), lowerBound(t.key, hi)
+	if to < from {
+		to = from
+	}
+	return from, to
+}
+
+// Range iterates over the keys >= lo and < hi, and their indexes.
+func (t *Key[K]) Range(lo, hi K) iter.Seq2[K, int] {
+	return func(yield func(K, int) bool) {
+		from, to := t.Between(lo, hi)
+		for i:=from; i<to; i++ {
+			if !yield(t.key[i], i) {
+				return
+			}
+		}
+	}
+}
+
+// Seek moves Next to the first key >= thekey. Returns false if there isn't one.
+func (t *Key[K]) Seek(thekey K) bool {
+	if t.cursor = lowerBound(t.key, thekey); t.cursor == len(t.key) {
+		t.cursor = 0
+		return false
+	}
+	return true
+}
+
+// ---------- KeyVal ----------
+
+// LowerBound returns the index of the first key >= thekey, or Len() if there isn't one.
+func (t *KeyVal[K, V]) LowerBound(thekey K) int {
+	return lowerBoundKeyVal(t.key, thekey)
+}
+
+// UpperBound returns the index of the first key > thekey, or Len() if there isn't one.
+func (t *KeyVal[K, V]) UpperBound(thekey K) int {
+	return upperBoundKeyVal(t.key, thekey)
+}
+
+// Floor returns the greatest key <= thekey, its value and its index.
+func (t *KeyVal[K, V]) Floor(thekey K) (K, V, int, bool) {
+	i := upperBoundKeyVal(t.key, thekey) - 1
+	if i < 0 {
+		return *new(K), *new(V), 0, false
+	}
+	return t.key[i].V, t.key[i].K, i, true
+}
+
+// Ceiling returns the smallest key >= thekey, its value and its index.
+func (t *KeyVal[K, V]) Ceiling(thekey K) (K, V, int, bool) {
+	i := lowerBoundKeyVal(t.key, thekey)
+	if i == len(t.key) {
+		return *new(K), *new(V), 0, false
+	}
+	return t.key[i].V, t.key[i].K, i, true
+}
+
+// Between returns the span of indexes [from, to) of the keys >= lo and < hi.
+func (t *KeyVal[K, V]) Between(lo, hi K) (int, int) {
+	from, to := lowerBoundKeyVal(t.key, lo), lowerBoundKeyVal(t.key, hi)
+	if to < from {
+		to = from
+	}
+	return from, to
+}
+
+// Range iterates over the keys >= lo and < hi, and their values.
+func (t *KeyVal[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		from, to := t.Between(lo, hi)
+		for _, v := range t.key[from:to] {
+			if !yield(v.V, v.K) {
+				return
+			}
+		}
+	}
+}
+
+// Seek moves Next to the first key >= thekey. Returns false if there isn't one.
+func (t *KeyVal[K, V]) Seek(thekey K) bool {
+	if t.cursor = lowerBoundKeyVal(t.key, thekey); t.cursor == len(t.key) {
+		t.cursor = 0
+		return false
+	}
+	return true
+}
+
+// ---------- Counter ----------
+
+// LowerBound returns the index of the first key >= thekey, or Len() if there isn't one.
+func (t *Counter[K, N]) LowerBound(thekey K) int {
+	return lowerBoundKeyVal(t.key, thekey)
+}
+
+// UpperBound returns the index of the first key > thekey, or Len() if there isn't one.
+func (t *Counter[K, N]) UpperBound(thekey K) int {
+	return upperBoundKeyVal(t.key, thekey)
+}
+
+// Floor returns the greatest key <= thekey, its frequency and its index.
+func (t *Counter[K, N]) Floor(thekey K) (K, N, int, bool) {
+	i := upperBoundKeyVal(t.key, thekey) - 1
+	if i < 0 {
+		return *new(K), *new(N), 0, false
+	}
+	return t.key[i].V, t.key[i].K, i, true
+}
+
+// Ceiling returns the smallest key >= thekey, its frequency and its index.
+func (t *Counter[K, N]) Ceiling(thekey K) (K, N, int, bool) {
+	i := lowerBoundKeyVal(t.key, thekey)
+	if i == len(t.key) {
+		return *new(K), *new(N), 0, false
+	}
+	return t.key[i].V, t.key[i].K, i, true
+}
+
+// Between returns the span of indexes [from, to) of the keys >= lo and < hi.
+func (t *Counter[K, N]) Between(lo, hi K) (int, int) {
+	from, to := lowerBoundKeyVal(t.key, lo), lowerBoundKeyVal(t.key, hi)
+	if to < from {
+		to = from
+	}
+	return from, to
+}
+
+// Range iterates over the keys >= lo and < hi, and their frequencies.
+func (t *Counter[K, N]) Range(lo, hi K) iter.Seq2[K, N] {
+	return func(yield func(K, N) bool) {
+		from, to := t.Between(lo, hi)
+		for _, v := range t.key[from:to] {
+			if !yield(v.V, v.K) {
+				return
+			}
+		}
+	}
+}
+
+// Seek moves Next to the first key >= thekey. Returns false if there isn't one.
+func (t *Counter[K, N]) Seek(thekey K) bool {
+	if t.cursor = lowerBoundKeyVal(t.key, thekey); t.cursor == len(t.key) {
+		t.cursor = 0
+		return false
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package binsearch
+
+import (
+ "math"
+ "math/rand"
+ "testing"
+)
+
+func TestKeyRange(t *testing.T) {
+	rnd := rand.New(rand.NewSource(19))
+	k := NewKey[int64](0)
+	kv := new(KeyVal[int64, int])
+	for i:=0; i<200; i++ {
+		v := int64(rnd.Intn(1000) - 500)
+		if _, ok := k.Add(v); !ok {
+			kv.Add(v, int(v) * 2)
+		}
+	}
+	keys := k.Keys()
+	for trial:=0; trial<500; trial++ {
+		x := int64(rnd.Intn(1100) - 550)
+		// The brute force: the first key >= x and the first key > x.
+		lower, upper := len(keys), len(keys)
+		for i := len(keys) - 1; i >= 0; i-- {
+			if keys[i] >= x {
+				lower = i
+			}
+			if keys[i] > x {
+				upper = i
+			}
+		}
+		if k.LowerBound(x) != lower || k.UpperBound(x) != upper || kv.LowerBound(x) != lower || kv.UpperBound(x) != upper {
+			t.Fatal(`bounds of`, x)
+		}
+		v, i, ok := k.Floor(x)
+		if ok != (upper > 0) || (ok && (i != upper - 1 || v != keys[i])) {
+			t.Fatal(`Floor of`, x, v, i, ok)
+		}
+		v, i, ok = k.Ceiling(x)
+		if ok != (lower < len(keys)) || (ok && (i != lower || v != keys[i])) {
+			t.Fatal(`Ceiling of`, x, v, i, ok)
+		}
+		if key, val, i, ok := kv.Floor(x); ok != (upper > 0) || (ok && (i != upper - 1 || key != keys[i] || val != int(key) * 2)) {
+			t.Fatal(`KeyVal.Floor of`, x, key, val, i, ok)
+		}
+		hi := x + int64(rnd.Intn(200))
+		from, to := k.Between(x, hi)
+		n := 0
+		for key, i := range k.Range(x, hi) {
+			if key < x || key >= hi || i != from + n || keys[i] != key {
+				t.Fatal(`Range`, x, hi, key, i)
+			}
+			n++
+		}
+		for j := range keys {
+			if (keys[j] >= x && keys[j] < hi) != (j >= from && j < to) {
+				t.Fatal(`Between`, x, hi, from, to)
+			}
+		}
+		if n != to - from {
+			t.Fatal(`Range yielded`, n, `keys, Between gives`, to - from)
+		}
+		n = 0
+		for key, val := range kv.Range(x, hi) {
+			if key < x || key >= hi || val != int(key) * 2 {
+				t.Fatal(`KeyVal.Range`, x, hi, key, val)
+			}
+			n++
+		}
+		if n != to - from {
+			t.Fatal(`KeyVal.Range yielded`, n, `keys, want`, to - from)
+		}
+	}
+	if f, to := k.Between(10, -10); f != to {
+		t.Fatal(`Between with hi < lo`, f, to)
+	}
+	for range k.Range(-1000, 1000) {
+		break // stopping early must not panic
+	}
+	if !k.Seek(keys[4] + 1) {
+		t.Fatal(`Seek`)
+	}
+	if v, eof := k.Next(); v != keys[5] || eof {
+		t.Fatal(`Next after Seek`, v)
+	}
+}
+
+func TestCounterRange(t *testing.T) {
+	c := NewCounter[uint32, int](nil)
+	c.Add(3, 1)
+	c.Add(3, 1)
+	c.Add(7, 1)
+	c.Build()
+	if key, n, i, ok := c.Floor(5); !ok || key != 3 || n != 2 || i != 0 {
+		t.Fatal(key, n, i, ok)
+	}
+	if _, _, _, ok := c.Floor(2); ok {
+		t.Fatal(`Floor below the first key`)
+	}
+	if key, n, i, ok := c.Ceiling(4); !ok || key != 7 || n != 1 || i != 1 {
+		t.Fatal(key, n, i, ok)
+	}
+	var got []uint32
+	for key := range c.Range(0, 100) {
+		got = append(got, key)
+	}
+	if len(got) != 2 || got[1] != 7 {
+		t.Fatal(got)
+	}
+}
+
+func TestFloatRange(t *testing.T) {
+	f := new(KeyValFloat64)
+	f.Add(1.5, 1)
+	f.Add(-2, 2)
+	f.Add(3, 3)
+	f.Add(math.NaN(), 4)
+	if key, v, i, ok := f.Ceiling(0); !ok || key != 1.5 || v != 1 || i != 1 {
+		t.Fatal(key, v, i, ok)
+	}
+	if from, to := f.Between(-3, 2); from != 0 || to != 2 {
+		t.Fatal(from, to)
+	}
+	n := 0
+	for key, v := range f.Range(math.Inf(-1), math.NaN()) {
+		if f2, _ := f.Find(key); f2 != v {
+			t.Fatal(key, v)
+		}
+		n++
+	}
+	if n != 3 {
+		t.Fatal(n)
+	}
+	if !f.Seek(2) {
+		t.Fatal(`Seek`)
+	}
+	if key, v, _ := f.Next(); key != 3 || v != 3 {
+		t.Fatal(key, v)
+	}
+	k := new(KeyFloat32)
+	for _, v := range []float32{-1, 0.5, 2} {
+		k.Add(v)
+	}
+	for v, i := range k.Range(0, 3) {
+		if w, _ := k.Find(v); w != i || v < 0 {
+			t.Fatal(v, i)
+		}
+	}
+}
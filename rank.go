@@ -0,0 +1,376 @@
+package binsearch
+
+import (
+ "bytes"
+ "slices"
+ "sort"
+)
+
+/*
+	KeyAt is the reverse of Find for the Key types: it returns the key at an index, so a Key structure can be used as a symbol table in both directions.
+	Rank returns the number of keys less than a key, whether or not it exists. For the bytes and string types that is in the order of bytes.Compare (see NextLex)
+	and for the runes types it is in rune order, not the order of Next where shorter keys come first.
+	So for the bytes, string and runes types Rank is not an index: KeyAt(Rank(key)) is not key, use Find for the index of a key.
+	For every other type Rank is the index Find gives an existing key, and KeyAt(Rank(key)) is key.
+	The runes types decode every key for Rank, as the encoding is not in rune order, so it is O(n) where the others are O(log n).
+*/
+
+// rankBytes returns the number of keys less than thekey in the order of bytes.Compare.
+// Each length class is sorted, so it adds up a search of each one: a shorter key is less if it is <= the start of thekey,
+// and a longer key is less if it is < thekey padded with zeros to its length.
+func rankBytes(src prefixSource, thekey []byte) int {
+	if len(thekey) == 0 {
+		return 0
+	}
+	var rank int
+	// Class 0 is in the order of bytes.Compare except "\x00", which is stored after the other single bytes
+	if l := src.classLen(0); l > 0 {
+		from := 0
+		if src.classCompare(0, 0, [8]uint64{}) == 0 {
+			from = 1 // the empty key is less than any other
+		}
+		if src.classCompare(0, l - 1, [8]uint64{nulKey}) == 0 {
+			l--
+			if len(thekey) > 1 || thekey[0] != 0 {
+				rank++
+			}
+		}
+		word := [8]uint64{uint64(thekey[0])}
+		rank += from + sort.Search(l - from, func(i int) bool {
+			c := src.classCompare(0, from + i, word)
+			return c > 0 || (c == 0 && len(thekey) == 1)
+		})
+	}
+	var padded [64]byte
+	copy(padded[:], thekey)
+	for on:=1; on<64; on++ {
+		l := src.classLen(on)
+		if l == 0 {
+			continue
+		}
+		n := on + 1 // the length of the keys in this class
+		words, _ := splitKey(padded[0:n])
+		if n < len(thekey) {
+			rank += sort.Search(l, func(i int) bool {
+				return src.classCompare(on, i, words) > 0
+			})
+		} else {
+			rank += sort.Search(l, func(i int) bool {
+				return src.classCompare(on, i, words) >= 0
+			})
+		}
+	}
+	return rank + sort.Search(src.classLen(64), func(i int) bool {
+		key, _ := src.classEntry(64, i)
+		return bytes.Compare(key, thekey) >= 0
+	})
+}
+
+// rankRunes returns the number of keys less than thekey in rune order. The encoding of the runes is not in rune order, so it decodes and compares every key.
+// Each key is written out and decoded into the same buffers, so it allocates nothing for each key.
+func rankRunes(src prefixSource, thekey []rune, legacy bool) int {
+	var rank int
+	buf := make([]rune, 0, 64) // enough for any key but the overflow
+	var kb [64]byte
+	for on:=0; on<=64; on++ {
+		for i:=0; i<src.classLen(on); i++ {
+			var key []byte
+			if on < 64 {
+				words := src.classWords(on, i)
+				key = putClassKey(&kb, &words, on)
+			} else {
+				key, _ = src.classEntry(on, i)
+			}
+			buf = appendDecodedRunes(buf[:0], key, legacy)
+			if slices.Compare(buf, thekey) < 0 {
+				rank++
+			}
+		}
+	}
+	return rank
+}
+
+// ---------- KeyBytes ----------
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *KeyBytes) KeyAt(i int) ([]byte, bool) {
+	if i < 0 || i >= t.total {
+		return nil, false
+	}
+	return t.keyAt(i), true
+}
+
+// Rank returns the number of keys less than thekey in the order of bytes.Compare. This is not the index of Find, which is in the order of Next.
+func (t *KeyBytes) Rank(thekey []byte) int {
+	return rankBytes(t, thekey)
+}
+
+// ---------- KeyValBytes ----------
+
+// Rank returns the number of keys less than thekey in the order of bytes.Compare. This is not the index of Find, which is in the order of Next.
+func (t *KeyValBytes) Rank(thekey []byte) int {
+	return rankBytes(t, thekey)
+}
+
+// ---------- CounterBytes ----------
+
+// Rank returns the number of keys less than thekey in the order of bytes.Compare. This is not the index of Find, which is in the order of Next.
+func (t *CounterBytes) Rank(thekey []byte) int {
+	return rankBytes(t, thekey)
+}
+
+// Rank returns the number of keys less than thekey in the order of bytes.Compare. This is not the index of Find, which is in the order of Next.
+func (t *CounterBytesFloat64) Rank(thekey []byte) int {
+	return t.child.Rank(thekey)
+}
+
+// Rank returns the number of keys less than thekey in the order of bytes.Compare. This is not the index of Find, which is in the order of Next.
+func (t *CounterBytesInt64) Rank(thekey []byte) int {
+	return t.child.Rank(thekey)
+}
+
+// ---------- KeyValBytesOf ----------
+
+// Rank returns the number of keys less than thekey in the order of bytes.Compare. This is not the index of Find, which is in the order of Next.
+func (t *KeyValBytesOf[V]) Rank(thekey []byte) int {
+	return t.child.Rank(thekey)
+}
+
+// ---------- Runes ----------
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *KeyRunes) KeyAt(i int) ([]rune, bool) {
+	key, ok := t.child.KeyAt(i)
+	if !ok {
+		return nil, false
+	}
+	return decodeRunes(key, t.legacy), true
+}
+
+// Rank returns the number of keys less than thekey in rune order, not the index of Find. It decodes every key, as the encoding is not in rune order.
+func (t *KeyRunes) Rank(thekey []rune) int {
+	return rankRunes(&t.child, thekey, t.legacy)
+}
+
+// Rank returns the number of keys less than thekey in rune order, not the index of Find. It decodes every key, as the encoding is not in rune order.
+func (t *KeyValRunes) Rank(thekey []rune) int {
+	return rankRunes(&t.child, thekey, t.legacy)
+}
+
+// Rank returns the number of keys less than thekey in rune order, not the index of Find. It decodes every key, as the encoding is not in rune order.
+func (t *CounterRunes) Rank(thekey []rune) int {
+	return rankRunes(&t.child, thekey, t.legacy)
+}
+
+// ---------- String ----------
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *KeyString) KeyAt(i int) (string, bool) {
+	key, ok := t.child.KeyAt(i)
+	return string(key), ok
+}
+
+// Rank returns the number of keys less than thekey in the order of bytes.Compare. This is not the index of Find, which is in the order of Next.
+func (t *KeyString) Rank(thekey string) int {
+	return rankBytes(&t.child, stringBytes(thekey))
+}
+
+// Rank returns the number of keys less than thekey in the order of bytes.Compare. This is not the index of Find, which is in the order of Next.
+func (t *KeyValString) Rank(thekey string) int {
+	return rankBytes(&t.child, stringBytes(thekey))
+}
+
+// Rank returns the number of keys less than thekey in the order of bytes.Compare. This is not the index of Find, which is in the order of Next.
+func (t *CounterString) Rank(thekey string) int {
+	return rankBytes(&t.child, stringBytes(thekey))
+}
+
+// ---------- Key ----------
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *Key[K]) KeyAt(i int) (K, bool) {
+	if i < 0 || i >= len(t.key) {
+		return *new(K), false
+	}
+	return t.key[i], true
+}
+
+// Rank returns the number of keys less than thekey, the same as LowerBound.
+func (t *Key[K]) Rank(thekey K) int {
+	return lowerBound(t.key, thekey)
+}
+
+// Rank returns the number of keys less than thekey, the same as LowerBound.
+func (t *KeyVal[K, V]) Rank(thekey K) int {
+	return lowerBoundKeyVal(t.key, thekey)
+}
+
+// Rank returns the number of keys less than thekey, the same as LowerBound.
+func (t *Counter[K, N]) Rank(thekey K) int {
+	return lowerBoundKeyVal(t.key, thekey)
+}
+
+// ---------- Float ----------
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *KeyFloat64) KeyAt(i int) (float64, bool) {
+	if i < 0 || i >= len(t.child.key) {
+		return 0, false
+	}
+	return key2float64(t.child.key[i]), true
+}
+
+// Rank returns the number of keys less than thekey, the same as LowerBound.
+func (t *KeyFloat64) Rank(thekey float64) int {
+	return t.LowerBound(thekey)
+}
+
+// Rank returns the number of keys less than thekey, the same as LowerBound.
+func (t *KeyValFloat64) Rank(thekey float64) int {
+	return t.LowerBound(thekey)
+}
+
+// Rank returns the number of keys less than thekey, the same as LowerBound.
+func (t *CounterFloat64) Rank(thekey float64) int {
+	return t.LowerBound(thekey)
+}
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *KeyFloat32) KeyAt(i int) (float32, bool) {
+	if i < 0 || i >= len(t.child.key) {
+		return 0, false
+	}
+	return key2float32(t.child.key[i]), true
+}
+
+// Rank returns the number of keys less than thekey, the same as LowerBound.
+func (t *KeyFloat32) Rank(thekey float32) int {
+	return t.LowerBound(thekey)
+}
+
+// Rank returns the number of keys less than thekey, the same as LowerBound.
+func (t *KeyValFloat32) Rank(thekey float32) int {
+	return t.LowerBound(thekey)
+}
+
+// Rank returns the number of keys less than thekey, the same as LowerBound.
+func (t *CounterFloat32) Rank(thekey float32) int {
+	return t.LowerBound(thekey)
+}
+
+// ---------- Fixed size ----------
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *KeyUint128) KeyAt(i int) ([16]byte, bool) {
+	if i < 0 || i >= len(t.key) {
+		return [16]byte{}, false
+	}
+	return bytes128(t.key[i][:]), true
+}
+
+// Rank returns the number of keys less than thekey, i.e. the index it has or would have.
+func (t *KeyUint128) Rank(thekey [16]byte) int {
+	i, _ := t.Find(thekey)
+	return i
+}
+
+// Rank returns the number of keys less than thekey.
+func (t *KeyValUint128) Rank(thekey [16]byte) int {
+	key := uint128(thekey)
+	return sort.Search(len(t.key), func(i int) bool {
+		return compare128(key, t.key[i][:]) <= 0
+	})
+}
+
+// Rank returns the number of keys less than thekey.
+func (t *CounterUint128) Rank(thekey [16]byte) int {
+	key := uint128(thekey)
+	return sort.Search(len(t.key), func(i int) bool {
+		return compare128(key, t.key[i][:]) <= 0
+	})
+}
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *KeyUint160) KeyAt(i int) ([20]byte, bool) {
+	if i < 0 || i >= len(t.key) {
+		return [20]byte{}, false
+	}
+	return bytes160(t.key[i][:]), true
+}
+
+// Rank returns the number of keys less than thekey, i.e. the index it has or would have.
+func (t *KeyUint160) Rank(thekey [20]byte) int {
+	i, _ := t.Find(thekey)
+	return i
+}
+
+// Rank returns the number of keys less than thekey.
+func (t *KeyValUint160) Rank(thekey [20]byte) int {
+	key := uint160(thekey)
+	return sort.Search(len(t.key), func(i int) bool {
+		return compare160(key, t.key[i][:]) <= 0
+	})
+}
+
+// Rank returns the number of keys less than thekey.
+func (t *CounterUint160) Rank(thekey [20]byte) int {
+	key := uint160(thekey)
+	return sort.Search(len(t.key), func(i int) bool {
+		return compare160(key, t.key[i][:]) <= 0
+	})
+}
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *KeyUint256) KeyAt(i int) ([32]byte, bool) {
+	if i < 0 || i >= len(t.key) {
+		return [32]byte{}, false
+	}
+	return bytes256(t.key[i][:]), true
+}
+
+// Rank returns the number of keys less than thekey, i.e. the index it has or would have.
+func (t *KeyUint256) Rank(thekey [32]byte) int {
+	i, _ := t.Find(thekey)
+	return i
+}
+
+// Rank returns the number of keys less than thekey.
+func (t *KeyValUint256) Rank(thekey [32]byte) int {
+	key := uint256(thekey)
+	return sort.Search(len(t.key), func(i int) bool {
+		return compare256(key, t.key[i][:]) <= 0
+	})
+}
+
+// Rank returns the number of keys less than thekey.
+func (t *CounterUint256) Rank(thekey [32]byte) int {
+	key := uint256(thekey)
+	return sort.Search(len(t.key), func(i int) bool {
+		return compare256(key, t.key[i][:]) <= 0
+	})
+}
+
+// ---------- Postings ----------
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *KeyPostingsBytes) KeyAt(i int) ([]byte, bool) {
+	return t.child.KeyAt(i)
+}
+
+// Rank returns the number of keys less than thekey in the order of bytes.Compare. This is not the index of Find, which is in the order of Next.
+func (t *KeyPostingsBytes) Rank(thekey []byte) int {
+	return t.child.Rank(thekey)
+}
+
+// KeyAt returns the key at index i. Returns false if i is out of range.
+func (t *KeyPostings[K]) KeyAt(i int) (K, bool) {
+	if i < 0 || i >= len(t.key) {
+		return *new(K), false
+	}
+	return t.key[i], true
+}
+
+// Rank returns the number of keys less than thekey.
+func (t *KeyPostings[K]) Rank(thekey K) int {
+	return lowerBound(t.key, thekey)
+}
@@ -0,0 +1,150 @@
+package binsearch
+
+import (
+ "bytes"
+ "math/rand"
+ "slices"
+ "testing"
+)
+
+func TestKeyBytesRank(t *testing.T) {
+	rnd := rand.New(rand.NewSource(21))
+	gen := func(n int) []byte {
+		x := make([]byte, rnd.Intn(n))
+		for j := range x {
+			x[j] = []byte{0, 'a', 'b', 255}[rnd.Intn(4)]
+		}
+		return x
+	}
+	k := new(KeyBytes)
+	kv := new(KeyValBytes)
+	c := new(CounterBytes)
+	ks := new(KeyString)
+	seen := map[string]bool{``: true}
+	keys := [][]byte{{}}
+	for i:=0; i<2500; i++ {
+		x := gen(100)
+		if i >= 2000 {
+			x = gen(10)
+		}
+		if !seen[string(x)] {
+			seen[string(x)] = true
+			keys = append(keys, x)
+		}
+	}
+	for _, x := range keys {
+		k.AddUnsorted(x)
+		kv.AddUnsorted(x, 1)
+		c.Add(x, 1)
+		ks.AddUnsorted(string(x))
+	}
+	k.Build()
+	kv.Build()
+	c.Build()
+	ks.Build()
+	all := k.Keys()
+	for i, x := range all {
+		if got, ok := k.KeyAt(i); !ok || !bytes.Equal(got, x) {
+			t.Fatalf(`KeyAt(%d) = %q, want %q`, i, got, x)
+		}
+		if got, ok := ks.KeyAt(i); !ok || got != string(x) {
+			t.Fatalf(`KeyString.KeyAt(%d) = %q, want %q`, i, got, x)
+		}
+	}
+	if _, ok := k.KeyAt(len(all)); ok {
+		t.Fatal(`KeyAt past the end`)
+	}
+	if _, ok := k.KeyAt(-1); ok {
+		t.Fatal(`KeyAt(-1)`)
+	}
+	for trial:=0; trial<3000; trial++ {
+		var q []byte
+		switch {
+			case trial % 7 == 0: q = gen(12)
+			case trial < len(keys): q = keys[trial]
+			default: q = gen(100)
+		}
+		want := 0
+		for _, x := range keys {
+			if bytes.Compare(x, q) < 0 {
+				want++
+			}
+		}
+		if k.Rank(q) != want || kv.Rank(q) != want || c.Rank(q) != want || ks.Rank(string(q)) != want {
+			t.Fatalf(`Rank(%q) = %d, %d, %d, %d, want %d`, q, k.Rank(q), kv.Rank(q), c.Rank(q), ks.Rank(string(q)), want)
+		}
+	}
+}
+
+func TestKeyRunesRank(t *testing.T) {
+	words := [][]rune{{}, {'a'}, {0x100}, {'b', 0x10000}, {3}, {'z'}, {0xFF, 2}}
+	k := new(KeyRunes)
+	for _, x := range words {
+		k.Add(x)
+	}
+	for _, q := range append(words, []rune{0xFF}, []rune{'a', 'a'}, []rune{0x10FFFF}) {
+		want := 0
+		for _, x := range words {
+			if slices.Compare(x, q) < 0 {
+				want++
+			}
+		}
+		if n := k.Rank(q); n != want {
+			t.Fatalf(`Rank(%U) = %d, want %d`, q, n, want)
+		}
+	}
+	for i := range words {
+		x, ok := k.KeyAt(i)
+		if j, _ := k.Find(x); !ok || j != i {
+			t.Fatalf(`KeyAt(%d) = %U`, i, x)
+		}
+	}
+	// Keys of every length class, and then every key is decoded into the same buffer so Rank doesn't allocate for each key
+	rnd := rand.New(rand.NewSource(21))
+	alphabet := []rune{0, 1, 'a', 'z', 0xFF, 0x100, 0x10000}
+	for i:=0; i<1000; i++ {
+		x := make([]rune, rnd.Intn(24))
+		for j := range x {
+			x[j] = alphabet[rnd.Intn(len(alphabet))]
+		}
+		if _, ok := k.Add(x); !ok {
+			words = append(words, x)
+		}
+	}
+	for i:=0; i<50; i++ {
+		q := words[rnd.Intn(len(words))]
+		want := 0
+		for _, x := range words {
+			if slices.Compare(x, q) < 0 {
+				want++
+			}
+		}
+		if n := k.Rank(q); n != want {
+			t.Fatalf(`Rank(%U) = %d, want %d`, q, n, want)
+		}
+	}
+	if n := testing.AllocsPerRun(10, func() { k.Rank([]rune{'q'}) }); n > 1 {
+		t.Fatalf(`Rank allocates %v times for %d keys`, n, k.Len())
+	}
+}
+
+func TestKeyRank(t *testing.T) {
+	g := NewKey[uint64](0)
+	g.Add(10)
+	g.Add(20)
+	if g.Rank(15) != 1 || g.Rank(10) != 0 || g.Rank(21) != 2 {
+		t.Fatal(g.Rank(15), g.Rank(10), g.Rank(21))
+	}
+	if v, ok := g.KeyAt(1); !ok || v != 20 {
+		t.Fatal(v, ok)
+	}
+	f := new(KeyUint128)
+	f.Add([16]byte{2})
+	f.Add([16]byte{1})
+	if f.Rank([16]byte{1, 1}) != 1 {
+		t.Fatal(f.Rank([16]byte{1, 1}))
+	}
+	if v, ok := f.KeyAt(0); !ok || v != [16]byte{1} {
+		t.Fatal(v, ok)
+	}
+}
@@ -0,0 +1,234 @@
+package binsearch
+
+import (
+ "github.com/AlasdairF/BinSearch/Ordered"
+ "github.com/AlasdairF/BinSearch/Overflow"
+ "slices"
+)
+
+/*
+	Remove and RemoveAt take a key out of a built structure without rebuilding it.
+	For the Key types they return the index that was removed, every key after it moves down one, so remove the same index from any slice of values kept alongside.
+	For the KeyVal types Remove returns the value that was removed.
+*/
+
+// removeAt removes cur[i], keeping the order. The old last element is zeroed so that what it points to can be garbage collected.
+func removeAt[T any](cur []T, i int) []T {
+	copy(cur[i:], cur[i+1:])
+	clear(cur[len(cur)-1:])
+	return cur[0:len(cur)-1]
+}
+
+// splitKey returns the words of a key of 64 bytes or less, as used by the tiers, and the run it is in.
+func splitKey[K bytesOrString](thekey K) ([8]uint64, int) {
+	var words [8]uint64
+	var l int
+	words[0], l = key2uint64(thekey)
+	for i:=1; i<=(len(thekey) - 1) / 8; i++ {
+		words[i], l = bytes2uint64(thekey[i*8:])
+	}
+	return words, l
+}
+
+// removeRow removes the row with the key in words from cur and returns the value it had.
+func removeRow[T row](cur []T, words [8]uint64) ([]T, int, bool) {
+	var v T
+	for i:=0; i<len(v)-1; i++ {
+		v[i] = words[i]
+	}
+	i, ok := slices.BinarySearchFunc(cur, v, func(a, b T) int {
+		return compareRow(&a, &b)
+	})
+	if !ok {
+		return cur, 0, false
+	}
+	val := int(cur[i][len(v)-1])
+	return removeAt(cur, i), val, true
+}
+
+// ---------- KeyBytes ----------
+
+// RemoveAt removes the key at index i, the keys after it move down one. Returns false if i is out of range. Only use after Build.
+func (t *KeyBytes) RemoveAt(i int) bool {
+	if i < 0 || i >= t.total {
+		return false
+	}
+	if tiered := t.total - len(t.overflow); i >= tiered {
+		t.overflow = removeAt(t.overflow, i - tiered)
+		t.total--
+		return true
+	}
+	// The key is in the last length class that starts at or before i
+	on := 63
+	for t.count[on] > i {
+		on--
+	}
+	i -= t.count[on]
+	run := on & 7
+	switch on >> 3 {
+		case 0:
+			t.limit8[run] = removeAt(t.limit8[run], i)
+		case 1:
+			t.limit16[run] = removeAt(t.limit16[run], i)
+		case 2:
+			t.limit24[run] = removeAt(t.limit24[run], i)
+		case 3:
+			t.limit32[run] = removeAt(t.limit32[run], i)
+		case 4:
+			t.limit40[run] = removeAt(t.limit40[run], i)
+		case 5:
+			t.limit48[run] = removeAt(t.limit48[run], i)
+		case 6:
+			t.limit56[run] = removeAt(t.limit56[run], i)
+		case 7:
+			t.limit64[run] = removeAt(t.limit64[run], i)
+	}
+	for on++; on<64; on++ {
+		t.count[on]--
+	}
+	t.total--
+	return true
+}
+
+// Remove removes the key. Returns: the index it was at, exists. Only use after Build.
+func (t *KeyBytes) Remove(thekey []byte) (int, bool) {
+	return removeKeyBytes(t, thekey)
+}
+
+func removeKeyBytes[K bytesOrString](t *KeyBytes, thekey K) (int, bool) {
+	i, ok := findKeyBytes(t, thekey)
+	if ok {
+		t.RemoveAt(i)
+	}
+	return i, ok
+}
+
+// ---------- KeyValBytes ----------
+
+// Remove removes the key. Returns: the value it had, exists.
+func (t *KeyValBytes) Remove(thekey []byte) (int, bool) {
+	return removeKeyValBytes(t, thekey)
+}
+
+func removeKeyValBytes[K bytesOrString](t *KeyValBytes, thekey K) (int, bool) {
+	t.valindex = nil
+	var val int
+	var ok bool
+	if len(thekey) > 64 {
+		var i int
+		if i, ok = slices.BinarySearchFunc(t.overflow, thekey, func(a sortOverflow.KeyVal, b K) int {
+			return -compareBytes(b, a.V)
+		}); ok {
+			val = t.overflow[i].K
+			t.overflow = removeAt(t.overflow, i)
+			t.total--
+		}
+		return val, ok
+	}
+	words, l := splitKey(thekey)
+	switch (len(thekey) - 1) / 8 {
+		case 0:
+			t.limit8[l], val, ok = removeRow(t.limit8[l], words)
+		case 1:
+			t.limit16[l], val, ok = removeRow(t.limit16[l], words)
+		case 2:
+			t.limit24[l], val, ok = removeRow(t.limit24[l], words)
+		case 3:
+			t.limit32[l], val, ok = removeRow(t.limit32[l], words)
+		case 4:
+			t.limit40[l], val, ok = removeRow(t.limit40[l], words)
+		case 5:
+			t.limit48[l], val, ok = removeRow(t.limit48[l], words)
+		case 6:
+			t.limit56[l], val, ok = removeRow(t.limit56[l], words)
+		case 7:
+			t.limit64[l], val, ok = removeRow(t.limit64[l], words)
+	}
+	if ok {
+		t.total--
+	}
+	return val, ok
+}
+
+// ---------- KeyValBytesOf ----------
+
+// Remove removes the key. Returns: the value it had, exists. Only use after Build.
+func (t *KeyValBytesOf[V]) Remove(thekey []byte) (V, bool) {
+	i, ok := t.child.Remove(thekey)
+	if !ok {
+		return *new(V), false
+	}
+	val := t.val[i]
+	t.val = removeAt(t.val, i)
+	return val, true
+}
+
+// ---------- Runes ----------
+
+// RemoveAt removes the key at index i, the keys after it move down one. Returns false if i is out of range.
+func (t *KeyRunes) RemoveAt(i int) bool {
+	return t.child.RemoveAt(i)
+}
+
+// Remove removes the key. Returns: the index it was at, exists.
+func (t *KeyRunes) Remove(thekey []rune) (int, bool) {
+	return t.child.Remove(encodeRunes(thekey, t.legacy))
+}
+
+// Remove removes the key. Returns: the value it had, exists.
+func (t *KeyValRunes) Remove(thekey []rune) (int, bool) {
+	return t.child.Remove(encodeRunes(thekey, t.legacy))
+}
+
+// ---------- String ----------
+
+// RemoveAt removes the key at index i, the keys after it move down one. Returns false if i is out of range.
+func (t *KeyString) RemoveAt(i int) bool {
+	return t.child.RemoveAt(i)
+}
+
+// Remove removes the key. Returns: the index it was at, exists.
+func (t *KeyString) Remove(thekey string) (int, bool) {
+	return removeKeyBytes(&t.child, thekey)
+}
+
+// Remove removes the key. Returns: the value it had, exists.
+func (t *KeyValString) Remove(thekey string) (int, bool) {
+	return removeKeyValBytes(&t.child, thekey)
+}
+
+// ---------- Key ----------
+
+// RemoveAt removes the key at index i, the keys after it move down one. Returns false if i is out of range.
+func (t *Key[K]) RemoveAt(i int) bool {
+	if i < 0 || i >= len(t.key) {
+		return false
+	}
+	t.key = removeAt(t.key, i)
+	return true
+}
+
+// Remove removes the key. Returns: the index it was at, exists.
+func (t *Key[K]) Remove(thekey K) (int, bool) {
+	i, ok := t.Find(thekey)
+	if ok {
+		t.key = removeAt(t.key, i)
+	}
+	return i, ok
+}
+
+// ---------- KeyVal ----------
+
+// Remove removes the key. Returns: the value it had, exists.
+func (t *KeyVal[K, V]) Remove(thekey K) (V, bool) {
+	t.valindex = nil
+	i, ok := slices.BinarySearchFunc(t.key, thekey, func(a sortOrdered.KeyVal[K, V], b K) int {
+		return compareOrdered(&a.V, &b)
+	})
+	if !ok {
+		return *new(V), false
+	}
+	val := t.key[i].K
+	t.key = removeAt(t.key, i)
+	return val, true
+}
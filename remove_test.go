@@ -0,0 +1,146 @@
+package binsearch
+
+import (
+ "fmt"
+ "strings"
+ "testing"
+)
+
+// classKeys returns the empty key and keys in every length class and the overflow.
+func classKeys(n int) []string {
+	keys := []string{``}
+	for i:=0; i<n; i++ {
+		keys = append(keys, strings.Repeat(`w`, i % 70) + fmt.Sprint(i))
+	}
+	return keys
+}
+
+func TestKeyBytesRemove(t *testing.T) {
+	keys := classKeys(200)
+	k := new(KeyBytes)
+	kv := new(KeyValBytes)
+	for i, x := range keys {
+		k.AddUnsorted([]byte(x))
+		kv.AddUnsorted([]byte(x), i)
+	}
+	k.Build()
+	kv.Build()
+	for n, x := range keys {
+		if n % 3 != 0 {
+			continue
+		}
+		i, ok := k.Find([]byte(x))
+		if j, ok2 := k.Remove([]byte(x)); !ok || !ok2 || i != j {
+			t.Fatalf(`Remove(%q) = %d, %v, it was at %d`, x, j, ok2, i)
+		}
+		if v, ok := kv.Remove([]byte(x)); !ok || v != n {
+			t.Fatalf(`KeyValBytes.Remove(%q) = %d, %v`, x, v, ok)
+		}
+		if _, ok := kv.Remove([]byte(x)); ok {
+			t.Fatalf(`%q removed twice`, x)
+		}
+	}
+	k2 := new(KeyBytes)
+	roundTrip(t, k.Write, k2.Read)
+	kv2 := new(KeyValBytes)
+	roundTrip(t, kv.Write, kv2.Read)
+	for _, st := range []*KeyBytes{k, k2} {
+		all := st.Keys()
+		if len(all) != st.Len() || kv2.Len() != st.Len() {
+			t.Fatal(len(all), st.Len(), kv2.Len())
+		}
+		for i, x := range all {
+			if j, ok := st.Find(x); !ok || j != i {
+				t.Fatalf(`%q found at %d, %v, want %d`, x, j, ok, i)
+			}
+		}
+	}
+	for n, x := range keys {
+		_, ok := k2.Find([]byte(x))
+		v, ok2 := kv2.Find([]byte(x))
+		if ok != (n % 3 != 0) || ok2 != ok || (ok && v != n) {
+			t.Fatalf(`%q = %d, %v, %v after Remove`, x, v, ok, ok2)
+		}
+	}
+	// The removed keys can be added again.
+	if _, ok := k.Add([]byte(keys[3])); ok {
+		t.Fatal(`a removed key is still present`)
+	}
+	if _, ok := k.Find([]byte(keys[3])); !ok {
+		t.Fatal(`a removed key could not be added again`)
+	}
+	for k.Len() > 0 {
+		if !k.RemoveAt(k.Len() / 2) {
+			t.Fatal(`RemoveAt`, k.Len())
+		}
+	}
+	if k.RemoveAt(0) || k.Reset() {
+		t.Fatal(`RemoveAt on an empty structure`)
+	}
+}
+
+func TestKeyRunesRemove(t *testing.T) {
+	k := new(KeyRunes)
+	kv := new(KeyValRunes)
+	for i, x := range []string{`é`, `中文`, "\x02", `plain`} {
+		k.Add([]rune(x))
+		kv.Add([]rune(x), i)
+	}
+	if i, ok := k.Remove([]rune(`中文`)); !ok || k.Len() != 3 {
+		t.Fatal(i, ok)
+	}
+	if _, ok := k.Find([]rune(`中文`)); ok {
+		t.Fatal(`found after Remove`)
+	}
+	if v, ok := kv.Remove([]rune("\x02")); !ok || v != 2 || kv.Len() != 3 {
+		t.Fatal(v, ok)
+	}
+	if v, ok := kv.Find([]rune(`plain`)); !ok || v != 3 {
+		t.Fatal(v, ok)
+	}
+}
+
+func TestKeyRemove(t *testing.T) {
+	g := NewKey[int](0)
+	g.Add(3)
+	g.Add(1)
+	g.Add(2)
+	if i, ok := g.Remove(2); !ok || i != 1 || g.Len() != 2 {
+		t.Fatal(i, ok)
+	}
+	if _, ok := g.Remove(2); ok {
+		t.Fatal(`removed twice`)
+	}
+	if !g.RemoveAt(0) || g.RemoveAt(1) {
+		t.Fatal(`RemoveAt`)
+	}
+	if keys := g.Keys(); len(keys) != 1 || keys[0] != 3 {
+		t.Fatal(keys)
+	}
+	kv := new(KeyValInt)
+	kv.Add(5, 50)
+	kv.Add(6, 60)
+	if v, ok := kv.Remove(6); !ok || v != 60 || kv.Len() != 1 {
+		t.Fatal(v, ok)
+	}
+	if v, ok := kv.Find(5); !ok || v != 50 {
+		t.Fatal(v, ok)
+	}
+	// The removed slot is zeroed, so the structure doesn't keep what the value pointed to
+	p := new(KeyVal[int, []byte])
+	for i:=0; i<3; i++ {
+		p.Add(i, make([]byte, 100))
+	}
+	p.Remove(0)
+	if tail := p.key[0:3][2]; tail.K != nil || tail.V != 0 {
+		t.Fatal(`the removed slot still holds`, tail.V, len(tail.K))
+	}
+	b := new(KeyBytes)
+	for i:=0; i<3; i++ {
+		b.Add([]byte(strings.Repeat(string(rune('a' + i)), 70))) // the overflow
+	}
+	b.Remove([]byte(strings.Repeat(`b`, 70)))
+	if tail := b.overflow[0:3][2]; tail != nil {
+		t.Fatal(`the removed overflow slot still holds`, string(tail))
+	}
+}
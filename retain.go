@@ -0,0 +1,389 @@
+package binsearch
+
+import (
+ "github.com/AlasdairF/BinSearch/Ordered"
+ "github.com/AlasdairF/BinSearch/Overflow"
+ "math"
+)
+
+/*
+	Retain keeps only the keys for which fn returns true, then releases the spare capacity as Optimize does.
+	For the Key types it returns a slice mapping the new indexes to the old indexes, the same as Build, so you can compact your values the same way.
+*/
+
+// retainTier keeps the elements of cur for which keep returns true, in place and in order.
+func retainTier[T any](cur []T, keep func(*T) bool) []T {
+	var n int
+	for i := range cur {
+		if keep(&cur[i]) {
+			cur[n] = cur[i]
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	return cur[0:n]
+}
+
+// ---------- KeyBytes ----------
+
+// Retain keeps only the keys for which fn returns true. Returns slice mapping new indexes to old indexes. Only use after Build.
+func (t *KeyBytes) Retain(fn func([]byte) bool) []int {
+	imap := make([]int, 0, t.total)
+	var on int
+	keep := func(key []byte) bool {
+		on++
+		if fn(key) {
+			imap = append(imap, on-1)
+			return true
+		}
+		return false
+	}
+	for run:=0; run<8; run++ {
+		t.limit8[run] = retainTier(t.limit8[run], func(v *uint64) bool {
+			return keep(reverse8(*v, run))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit16[run] = retainTier(t.limit16[run], func(v *[2]uint64) bool {
+			return keep(reverse16(*v, run))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit24[run] = retainTier(t.limit24[run], func(v *[3]uint64) bool {
+			return keep(reverse24(*v, run))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit32[run] = retainTier(t.limit32[run], func(v *[4]uint64) bool {
+			return keep(reverse32(*v, run))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit40[run] = retainTier(t.limit40[run], func(v *[5]uint64) bool {
+			return keep(reverse40(*v, run))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit48[run] = retainTier(t.limit48[run], func(v *[6]uint64) bool {
+			return keep(reverse48(*v, run))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit56[run] = retainTier(t.limit56[run], func(v *[7]uint64) bool {
+			return keep(reverse56(*v, run))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit64[run] = retainTier(t.limit64[run], func(v *[8]uint64) bool {
+			return keep(reverse64(*v, run))
+		})
+	}
+	t.overflow = retainTier(t.overflow, func(v *[]byte) bool {
+		return keep(*v)
+	})
+	t.recount()
+	t.Optimize()
+	return imap
+}
+
+// ---------- KeyValBytes ----------
+
+// Retain keeps only the keys for which fn returns true.
+func (t *KeyValBytes) Retain(fn func([]byte, int) bool) {
+	t.valindex = nil
+	for run:=0; run<8; run++ {
+		t.limit8[run] = retainTier(t.limit8[run], func(v *[2]uint64) bool {
+			return fn(reverse8b(*v, run), int(v[1]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit16[run] = retainTier(t.limit16[run], func(v *[3]uint64) bool {
+			return fn(reverse16b(*v, run), int(v[2]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit24[run] = retainTier(t.limit24[run], func(v *[4]uint64) bool {
+			return fn(reverse24b(*v, run), int(v[3]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit32[run] = retainTier(t.limit32[run], func(v *[5]uint64) bool {
+			return fn(reverse32b(*v, run), int(v[4]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit40[run] = retainTier(t.limit40[run], func(v *[6]uint64) bool {
+			return fn(reverse40b(*v, run), int(v[5]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit48[run] = retainTier(t.limit48[run], func(v *[7]uint64) bool {
+			return fn(reverse48b(*v, run), int(v[6]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit56[run] = retainTier(t.limit56[run], func(v *[8]uint64) bool {
+			return fn(reverse56b(*v, run), int(v[7]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit64[run] = retainTier(t.limit64[run], func(v *[9]uint64) bool {
+			return fn(reverse64b(*v, run), int(v[8]))
+		})
+	}
+	t.overflow = retainTier(t.overflow, func(v *sortOverflow.KeyVal) bool {
+		return fn(v.V, v.K)
+	})
+	t.total = t.tiered() + len(t.overflow)
+	t.Optimize()
+}
+
+// ---------- CounterBytes ----------
+
+// Retain keeps only the keys for which fn returns true, e.g. to drop rare keys after Build.
+func (t *CounterBytes) Retain(fn func([]byte, int) bool) {
+	for run:=0; run<8; run++ {
+		t.limit8[run] = retainTier(t.limit8[run], func(v *[2]uint64) bool {
+			return fn(reverse8b(*v, run), int(v[1]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit16[run] = retainTier(t.limit16[run], func(v *[3]uint64) bool {
+			return fn(reverse16b(*v, run), int(v[2]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit24[run] = retainTier(t.limit24[run], func(v *[4]uint64) bool {
+			return fn(reverse24b(*v, run), int(v[3]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit32[run] = retainTier(t.limit32[run], func(v *[5]uint64) bool {
+			return fn(reverse32b(*v, run), int(v[4]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit40[run] = retainTier(t.limit40[run], func(v *[6]uint64) bool {
+			return fn(reverse40b(*v, run), int(v[5]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit48[run] = retainTier(t.limit48[run], func(v *[7]uint64) bool {
+			return fn(reverse48b(*v, run), int(v[6]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit56[run] = retainTier(t.limit56[run], func(v *[8]uint64) bool {
+			return fn(reverse56b(*v, run), int(v[7]))
+		})
+	}
+	for run:=0; run<8; run++ {
+		t.limit64[run] = retainTier(t.limit64[run], func(v *[9]uint64) bool {
+			return fn(reverse64b(*v, run), int(v[8]))
+		})
+	}
+	t.overflow = retainTier(t.overflow, func(v *sortOverflow.KeyVal) bool {
+		return fn(v.V, v.K)
+	})
+	t.total = t.tiered() + len(t.overflow)
+	t.Optimize()
+}
+
+// ---------- KeyValBytesOf ----------
+
+// Retain keeps only the keys for which fn returns true. Only use after Build.
+func (t *KeyValBytesOf[V]) Retain(fn func([]byte, V) bool) {
+	var on int
+	imap := t.child.Retain(func(key []byte) bool {
+		on++
+		return fn(key, t.val[on-1])
+	})
+	for i, old := range imap {
+		t.val[i] = t.val[old]
+	}
+	t.val = t.val[0:len(imap)]
+	temp := make([]V, len(t.val))
+	copy(temp, t.val)
+	t.val = temp
+}
+
+// ---------- Runes ----------
+
+// Retain keeps only the keys for which fn returns true. Returns slice mapping new indexes to old indexes.
+func (t *KeyRunes) Retain(fn func([]rune) bool) []int {
+	return t.child.Retain(func(key []byte) bool {
+		return fn(decodeRunes(key, t.legacy))
+	})
+}
+
+// Retain keeps only the keys for which fn returns true.
+func (t *KeyValRunes) Retain(fn func([]rune, int) bool) {
+	t.child.Retain(func(key []byte, val int) bool {
+		return fn(decodeRunes(key, t.legacy), val)
+	})
+}
+
+// Retain keeps only the keys for which fn returns true.
+func (t *CounterRunes) Retain(fn func([]rune, int) bool) {
+	t.child.Retain(func(key []byte, val int) bool {
+		return fn(decodeRunes(key, t.legacy), val)
+	})
+}
+
+// ---------- String ----------
+
+// Retain keeps only the keys for which fn returns true. Returns slice mapping new indexes to old indexes.
+func (t *KeyString) Retain(fn func(string) bool) []int {
+	return t.child.Retain(func(key []byte) bool {
+		return fn(string(key))
+	})
+}
+
+// Retain keeps only the keys for which fn returns true.
+func (t *KeyValString) Retain(fn func(string, int) bool) {
+	t.child.Retain(func(key []byte, val int) bool {
+		return fn(string(key), val)
+	})
+}
+
+// Retain keeps only the keys for which fn returns true.
+func (t *CounterString) Retain(fn func(string, int) bool) {
+	t.child.Retain(func(key []byte, val int) bool {
+		return fn(string(key), val)
+	})
+}
+
+// ---------- Key ----------
+
+// Retain keeps only the keys for which fn returns true. Returns slice mapping new indexes to old indexes.
+func (t *Key[K]) Retain(fn func(K) bool) []int {
+	imap := make([]int, 0, len(t.key))
+	var on int
+	t.key = retainTier(t.key, func(k *K) bool {
+		on++
+		if fn(*k) {
+			imap = append(imap, on-1)
+			return true
+		}
+		return false
+	})
+	t.Optimize()
+	return imap
+}
+
+// ---------- KeyVal ----------
+
+// Retain keeps only the keys for which fn returns true.
+func (t *KeyVal[K, V]) Retain(fn func(K, V) bool) {
+	t.valindex = nil
+	t.key = retainTier(t.key, func(v *sortOrdered.KeyVal[K, V]) bool {
+		return fn(v.V, v.K)
+	})
+	t.Optimize()
+}
+
+// ---------- Counter ----------
+
+// Retain keeps only the keys for which fn returns true, e.g. to drop rare keys after Build.
+func (t *Counter[K, N]) Retain(fn func(K, N) bool) {
+	t.key = retainTier(t.key, func(v *sortOrdered.KeyVal[K, N]) bool {
+		return fn(v.V, v.K)
+	})
+	t.Optimize()
+}
+
+// ---------- CounterBytesFloat64 & CounterBytesInt64 ----------
+
+// Retain keeps only the keys for which fn returns true, e.g. to drop rare keys after Build.
+func (t *CounterBytesFloat64) Retain(fn func([]byte, float64) bool) {
+	t.child.Retain(func(key []byte, val int) bool {
+		return fn(key, math.Float64frombits(uint64(val)))
+	})
+}
+
+// Retain keeps only the keys for which fn returns true, e.g. to drop rare keys after Build.
+func (t *CounterBytesInt64) Retain(fn func([]byte, int64) bool) {
+	t.child.Retain(func(key []byte, val int) bool {
+		return fn(key, int64(val))
+	})
+}
+
+// ---------- Float ----------
+
+// Retain keeps only the keys for which fn returns true. Returns slice mapping new indexes to old indexes.
+func (t *KeyFloat64) Retain(fn func(float64) bool) []int {
+	return t.child.Retain(func(key uint64) bool {
+		return fn(key2float64(key))
+	})
+}
+
+// Retain keeps only the keys for which fn returns true.
+func (t *KeyValFloat64) Retain(fn func(float64, int) bool) {
+	t.child.Retain(func(key uint64, val int) bool {
+		return fn(key2float64(key), val)
+	})
+}
+
+// Retain keeps only the keys for which fn returns true, e.g. to drop rare keys after Build.
+func (t *CounterFloat64) Retain(fn func(float64, int) bool) {
+	t.child.Retain(func(key uint64, val int) bool {
+		return fn(key2float64(key), val)
+	})
+}
+
+// Retain keeps only the keys for which fn returns true. Returns slice mapping new indexes to old indexes.
+func (t *KeyFloat32) Retain(fn func(float32) bool) []int {
+	return t.child.Retain(func(key uint32) bool {
+		return fn(key2float32(key))
+	})
+}
+
+// Retain keeps only the keys for which fn returns true.
+func (t *KeyValFloat32) Retain(fn func(float32, int) bool) {
+	t.child.Retain(func(key uint32, val int) bool {
+		return fn(key2float32(key), val)
+	})
+}
+
+// Retain keeps only the keys for which fn returns true, e.g. to drop rare keys after Build.
+func (t *CounterFloat32) Retain(fn func(float32, int) bool) {
+	t.child.Retain(func(key uint32, val int) bool {
+		return fn(key2float32(key), val)
+	})
+}
+
+// ---------- KeyUint128, KeyUint160 & KeyUint256 ----------
+
+// Retain keeps only the keys for which fn returns true. Returns slice mapping new indexes to old indexes.
+func (t *keyFixed[B, W, R]) Retain(fn func(B) bool) []int {
+	imap := make([]int, 0, len(t.key))
+	var on int
+	t.key = retainTier(t.key, func(v *W) bool {
+		on++
+		if fn(fromWords[B](v)) {
+			imap = append(imap, on-1)
+			return true
+		}
+		return false
+	})
+	t.Optimize()
+	return imap
+}
+
+// Retain keeps only the keys for which fn returns true.
+func (t *keyValFixed[B, W, R]) Retain(fn func(B, int) bool) {
+	t.key = retainTier(t.key, func(v *R) bool {
+		return fn(fromWords[B](v), int((*v)[len(*v) - 1]))
+	})
+	t.Optimize()
+}
+
+// Retain keeps only the keys for which fn returns true, e.g. to drop rare keys after Build.
+func (t *counterFixed[B, W, R]) Retain(fn func(B, int) bool) {
+	t.key = retainTier(t.key, func(v *R) bool {
+		return fn(fromWords[B](v), int((*v)[len(*v) - 1]))
+	})
+	t.Optimize()
+}
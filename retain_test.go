@@ -0,0 +1,148 @@
+package binsearch
+
+import (
+ "crypto/sha1"
+ "math"
+ "testing"
+)
+
+func TestKeyBytesRetain(t *testing.T) {
+	keys := classKeys(300)
+	k := new(KeyBytes)
+	for _, x := range keys {
+		k.AddUnsorted([]byte(x))
+	}
+	k.Build()
+	old := k.Keys()
+	imap := k.Retain(func(b []byte) bool { return len(b) % 2 == 0 })
+	all := k.Keys()
+	if len(imap) != len(all) || k.Len() != len(all) {
+		t.Fatal(len(imap), len(all), k.Len())
+	}
+	for i, o := range imap {
+		if string(old[o]) != string(all[i]) {
+			t.Fatalf(`%d maps to %q, want %q`, i, old[o], all[i])
+		}
+	}
+	k2 := new(KeyBytes)
+	roundTrip(t, k.Write, k2.Read)
+	for i, x := range all {
+		if j, ok := k2.Find(x); !ok || j != i {
+			t.Fatalf(`%q found at %d, %v, want %d`, x, j, ok, i)
+		}
+	}
+	for _, x := range keys {
+		if _, ok := k2.Find([]byte(x)); ok != (len(x) % 2 == 0) {
+			t.Fatalf(`%q found %v after Retain`, x, ok)
+		}
+	}
+}
+
+func TestCounterBytesRetain(t *testing.T) {
+	keys := classKeys(300)
+	c := new(CounterBytes)
+	kv := new(KeyValBytes)
+	for i, x := range keys {
+		for j:=0; j<=i % 5; j++ {
+			c.Add([]byte(x), 1)
+		}
+		kv.AddUnsorted([]byte(x), i)
+	}
+	c.Build()
+	kv.Build()
+	c.Retain(func(b []byte, v int) bool { return v >= 3 })
+	kv.Retain(func(b []byte, v int) bool { return v % 2 == 0 })
+	c2 := new(CounterBytes)
+	roundTrip(t, c.Write, c2.Read)
+	n := 0
+	for i, x := range keys {
+		v, ok := c2.Find([]byte(x))
+		if ok != (i % 5 >= 2) || (ok && v != i % 5 + 1) {
+			t.Fatalf(`%q = %d, %v after Retain`, x, v, ok)
+		}
+		if ok {
+			n++
+		}
+		if v, ok := kv.Find([]byte(x)); ok != (i % 2 == 0) || (ok && v != i) {
+			t.Fatalf(`KeyValBytes %q = %d, %v after Retain`, x, v, ok)
+		}
+	}
+	if c2.Len() != n {
+		t.Fatal(c2.Len(), n)
+	}
+	f := new(CounterBytesFloat64)
+	f.Add([]byte(`a`), 0.5)
+	f.Add([]byte(`b`), 2)
+	f.Build()
+	f.Retain(func(b []byte, v float64) bool { return v > 1 })
+	if _, ok := f.Find([]byte(`a`)); ok || f.Len() != 1 {
+		t.Fatal(`CounterBytesFloat64`, f.Len())
+	}
+}
+
+func TestKeyRetain(t *testing.T) {
+	g := NewKey[int](0)
+	for i:=0; i<10; i++ {
+		g.Add(i)
+	}
+	m := g.Retain(func(x int) bool { return x % 3 == 0 })
+	if g.Len() != 4 || len(m) != 4 || m[3] != 9 {
+		t.Fatal(m)
+	}
+	c := NewCounter[string, int](nil)
+	for _, x := range []string{`a`, `b`, `a`, `c`, `c`, `c`} {
+		c.Add(x, 1)
+	}
+	c.Build()
+	c.Retain(func(k string, v int) bool { return v > 1 })
+	if keys := c.Keys(); len(keys) != 2 || keys[0] != `a` || keys[1] != `c` {
+		t.Fatal(keys)
+	}
+}
+
+func TestFloatRetain(t *testing.T) {
+	k := new(KeyFloat64)
+	c := new(CounterFloat32)
+	for _, v := range []float64{math.NaN(), -1, 0, 2.5, math.Inf(1)} {
+		k.Add(v)
+		c.Add(float32(v), 1)
+	}
+	c.Add(2.5, 1)
+	c.Build()
+	m := k.Retain(func(v float64) bool { return v == v && v >= 0 })
+	if keys := k.Keys(); len(keys) != 3 || keys[0] != 0 || keys[2] != math.Inf(1) || m[0] != 1 || m[2] != 3 {
+		t.Fatal(keys, m)
+	}
+	c.Retain(func(v float32, n int) bool { return n > 1 })
+	if n, ok := c.Find(2.5); !ok || n != 2 || c.Len() != 1 {
+		t.Fatal(n, ok, c.Len())
+	}
+}
+
+func TestFixedRetain(t *testing.T) {
+	k := new(KeyUint160)
+	c := new(CounterUint160)
+	for i:=0; i<50; i++ {
+		h := sha1.Sum([]byte{byte(i)})
+		k.AddUnsorted(h)
+		c.Add(h, i)
+	}
+	k.Build()
+	c.Build()
+	old := k.Keys()
+	m := k.Retain(func(h [20]byte) bool { return h[0] < 128 })
+	for i, h := range k.Keys() {
+		if h != old[m[i]] || h[0] >= 128 {
+			t.Fatal(i, m[i])
+		}
+	}
+	c.Retain(func(h [20]byte, n int) bool { return n % 2 == 0 })
+	if c.Len() != 25 {
+		t.Fatal(c.Len())
+	}
+	for i:=0; i<50; i++ {
+		if n, ok := c.Find(sha1.Sum([]byte{byte(i)})); ok != (i % 2 == 0) || (ok && n != i) {
+			t.Fatal(i, n, ok)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package binsearch
+
+import (
+ "slices"
+ "testing"
+ "unicode/utf8"
+)
+
+func TestRunesEncoding(t *testing.T) {
+	word := make([]rune, 0, 64)
+	check := func() {
+		b := runes2bytes(word)
+		if got := bytes2runes(b); !slices.Equal(got, word) {
+			t.Fatalf(`%U decoded as %U`, word, got)
+		}
+	}
+	for r := rune(0); r <= utf8.MaxRune; r++ {
+		if !utf8.ValidRune(r) {
+			continue
+		}
+		word = append(word, r)
+		if len(word) == cap(word) {
+			check()
+			word = word[:0]
+		}
+	}
+	check()
+	if len(runes2bytes([]rune(`Grüße`))) != 5 {
+		t.Fatal(`Latin-1 runes must take one byte each`)
+	}
+}
+
+func TestKeyRunes(t *testing.T) {
+	keys := []string{"\x02", "\x03", "\x01a", "\x02\x03\x04", "é", "ÿ\x02", "中文", "😀x", "\U0010FFFF", "", "\x00", "plain"}
+	k := new(KeyRunes)
+	c := new(CounterRunes)
+	for i, x := range keys {
+		if err := k.AddUnsorted([]rune(x)); err != nil {
+			t.Fatal(err)
+		}
+		c.Add([]rune(x), i+1)
+	}
+	k.Build()
+	c.Build()
+	if k.Len() != len(keys) || c.Len() != len(keys) {
+		t.Fatal(k.Len(), c.Len())
+	}
+	k2 := new(KeyRunes)
+	roundTrip(t, k.Write, k2.Read)
+	c2 := new(CounterRunes)
+	roundTrip(t, c.Write, c2.Read)
+	kv := c2.KeyValRunes()
+	kv2 := new(KeyValRunes)
+	roundTrip(t, kv.Write, kv2.Read)
+	got := make(map[string]bool)
+	for _, x := range k2.Keys() {
+		got[string(x)] = true
+	}
+	for i, x := range keys {
+		if !got[x] {
+			t.Fatalf(`%q missing from Keys`, x)
+		}
+		a, ok1 := k.Find([]rune(x))
+		b, ok2 := k2.Find([]rune(x))
+		if !ok1 || !ok2 || a != b {
+			t.Fatalf(`%q found at %d, %v and %d, %v`, x, a, ok1, b, ok2)
+		}
+		if v, ok := kv2.Find([]rune(x)); !ok || v != i+1 {
+			t.Fatalf(`%q = %d, %v`, x, v, ok)
+		}
+	}
+	if kv2.Reset() {
+		for {
+			x, v, eof := kv2.Next()
+			if keys[v-1] != string(x) {
+				t.Fatalf(`Next %q = %d`, x, v)
+			}
+			if eof {
+				break
+			}
+		}
+	}
+}
+
+func TestKeyRunesLegacy(t *testing.T) {
+	// A file written with the original encoding has no header and stores the runes as KeyBytes.
+	old := new(KeyBytes)
+	old.AddUnsorted(runes2bytesLegacy([]rune("\x01a")))
+	old.AddUnsorted(runes2bytesLegacy([]rune(`中`)))
+	old.Build()
+	k := new(KeyRunes)
+	roundTrip(t, old.Write, k.Read)
+	for _, x := range []string{"\x01a", `中`} {
+		if _, ok := k.Find([]rune(x)); !ok {
+			t.Fatalf(`%q not found in the legacy file`, x)
+		}
+	}
+	k.Add([]rune(`é`))
+	k2 := new(KeyRunes)
+	roundTrip(t, k.Write, k2.Read)
+	for _, x := range []string{"\x01a", `中`, `é`} {
+		if _, ok := k2.Find([]rune(x)); !ok {
+			t.Fatalf(`%q not found after writing the legacy file back`, x)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package binsearch
+
+import (
+ "bytes"
+ "cmp"
+)
+
+/*
+	Union, Intersect, Difference and SymmetricDifference merge two built structures in linear time and return a new built structure.
+	The Len versions return the number of keys the result would have without allocating anything.
+	KeyBytes keys of the same length are always in the same tier, so each tier is merged with the same tier of the other.
+*/
+
+const (
+	setA = 1 << iota // keys only in the first
+	setBoth // keys in both
+	setB // keys only in the second
+)
+
+// mergeSorted merges two sorted slices, keeping the keys selected by op.
+func mergeSorted[T any](a, b []T, compare func(*T, *T) int, op int) []T {
+	l := mergeSortedLen(a, b, compare, op)
+	if l == 0 {
+		return nil
+	}
+	out := make([]T, 0, l)
+	var i, j int
+	for i < len(a) && j < len(b) {
+		if c := compare(&a[i], &b[j]); c < 0 {
+			if op & setA != 0 {
+				out = append(out, a[i])
+			}
+			i++
+		} else if c > 0 {
+			if op & setB != 0 {
+				out = append(out, b[j])
+			}
+			j++
+		} else {
+			if op & setBoth != 0 {
+				out = append(out, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if op & setA != 0 {
+		out = append(out, a[i:]...)
+	}
+	if op & setB != 0 {
+		out = append(out, b[j:]...)
+	}
+	return out
+}
+
+// mergeSortedLen is the length of the result of mergeSorted.
+func mergeSortedLen[T any](a, b []T, compare func(*T, *T) int, op int) int {
+	var i, j, l int
+	for i < len(a) && j < len(b) {
+		if c := compare(&a[i], &b[j]); c < 0 {
+			if op & setA != 0 {
+				l++
+			}
+			i++
+		} else if c > 0 {
+			if op & setB != 0 {
+				l++
+			}
+			j++
+		} else {
+			if op & setBoth != 0 {
+				l++
+			}
+			i++
+			j++
+		}
+	}
+	if op & setA != 0 {
+		l += len(a) - i
+	}
+	if op & setB != 0 {
+		l += len(b) - j
+	}
+	return l
+}
+
+func compareOrdered[K cmp.Ordered](a, b *K) int {
+	if *a < *b {
+		return -1
+	}
+	if *a > *b {
+		return 1
+	}
+	return 0
+}
+
+func compareWords(a, b []uint64) int {
+	for i := range a {
+		if a[i] < b[i] {
+			return -1
+		}
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+func compareTier16(a, b *[2]uint64) int {
+	return compareWords(a[:], b[:])
+}
+
+func compareTier24(a, b *[3]uint64) int {
+	return compareWords(a[:], b[:])
+}
+
+func compareTier32(a, b *[4]uint64) int {
+	return compareWords(a[:], b[:])
+}
+
+func compareTier40(a, b *[5]uint64) int {
+	return compareWords(a[:], b[:])
+}
+
+func compareTier48(a, b *[6]uint64) int {
+	return compareWords(a[:], b[:])
+}
+
+func compareTier56(a, b *[7]uint64) int {
+	return compareWords(a[:], b[:])
+}
+
+func compareTier64(a, b *[8]uint64) int {
+	return compareWords(a[:], b[:])
+}
+
+func compareOverflow(a, b *[]byte) int {
+	return bytes.Compare(*a, *b)
+}
+
+// ---------- KeyBytes ----------
+
+func (t *KeyBytes) setOp(other *KeyBytes, op int) *KeyBytes {
+	obj := new(KeyBytes)
+	for run:=0; run<8; run++ {
+		obj.limit8[run] = mergeSorted(t.limit8[run], other.limit8[run], compareOrdered[uint64], op)
+		obj.limit16[run] = mergeSorted(t.limit16[run], other.limit16[run], compareTier16, op)
+		obj.limit24[run] = mergeSorted(t.limit24[run], other.limit24[run], compareTier24, op)
+		obj.limit32[run] = mergeSorted(t.limit32[run], other.limit32[run], compareTier32, op)
+		obj.limit40[run] = mergeSorted(t.limit40[run], other.limit40[run], compareTier40, op)
+		obj.limit48[run] = mergeSorted(t.limit48[run], other.limit48[run], compareTier48, op)
+		obj.limit56[run] = mergeSorted(t.limit56[run], other.limit56[run], compareTier56, op)
+		obj.limit64[run] = mergeSorted(t.limit64[run], other.limit64[run], compareTier64, op)
+	}
+	obj.overflow = mergeSorted(t.overflow, other.overflow, compareOverflow, op)
+	obj.recount()
+	return obj
+}
+
+func (t *KeyBytes) setLen(other *KeyBytes, op int) int {
+	var l int
+	for run:=0; run<8; run++ {
+		l += mergeSortedLen(t.limit8[run], other.limit8[run], compareOrdered[uint64], op)
+		l += mergeSortedLen(t.limit16[run], other.limit16[run], compareTier16, op)
+		l += mergeSortedLen(t.limit24[run], other.limit24[run], compareTier24, op)
+		l += mergeSortedLen(t.limit32[run], other.limit32[run], compareTier32, op)
+		l += mergeSortedLen(t.limit40[run], other.limit40[run], compareTier40, op)
+		l += mergeSortedLen(t.limit48[run], other.limit48[run], compareTier48, op)
+		l += mergeSortedLen(t.limit56[run], other.limit56[run], compareTier56, op)
+		l += mergeSortedLen(t.limit64[run], other.limit64[run], compareTier64, op)
+	}
+	return l + mergeSortedLen(t.overflow, other.overflow, compareOverflow, op)
+}
+
+// Union returns a new KeyBytes with the keys that are in either. Both must be built.
+func (t *KeyBytes) Union(other *KeyBytes) *KeyBytes {
+	return t.setOp(other, setA | setBoth | setB)
+}
+
+// Intersect returns a new KeyBytes with the keys that are in both. Both must be built.
+func (t *KeyBytes) Intersect(other *KeyBytes) *KeyBytes {
+	return t.setOp(other, setBoth)
+}
+
+// Difference returns a new KeyBytes with the keys that are in t but not in other. Both must be built.
+func (t *KeyBytes) Difference(other *KeyBytes) *KeyBytes {
+	return t.setOp(other, setA)
+}
+
+// SymmetricDifference returns a new KeyBytes with the keys that are in only one of them. Both must be built.
+func (t *KeyBytes) SymmetricDifference(other *KeyBytes) *KeyBytes {
+	return t.setOp(other, setA | setB)
+}
+
+// UnionLen returns the number of keys Union would return, without allocating.
+func (t *KeyBytes) UnionLen(other *KeyBytes) int {
+	return t.setLen(other, setA | setBoth | setB)
+}
+
+// IntersectLen returns the number of keys Intersect would return, without allocating.
+func (t *KeyBytes) IntersectLen(other *KeyBytes) int {
+	return t.setLen(other, setBoth)
+}
+
+// DifferenceLen returns the number of keys Difference would return, without allocating.
+func (t *KeyBytes) DifferenceLen(other *KeyBytes) int {
+	return t.setLen(other, setA)
+}
+
+// SymmetricDifferenceLen returns the number of keys SymmetricDifference would return, without allocating.
+func (t *KeyBytes) SymmetricDifferenceLen(other *KeyBytes) int {
+	return t.setLen(other, setA | setB)
+}
+
+// ---------- Key ----------
+
+// Union returns a new Key with the keys that are in either. Both must be built.
+func (t *Key[K]) Union(other *Key[K]) *Key[K] {
+	return &Key[K]{key:mergeSorted(t.key, other.key, compareOrdered[K], setA | setBoth | setB)}
+}
+
+// Intersect returns a new Key with the keys that are in both. Both must be built.
+func (t *Key[K]) Intersect(other *Key[K]) *Key[K] {
+	return &Key[K]{key:mergeSorted(t.key, other.key, compareOrdered[K], setBoth)}
+}
+
+// Difference returns a new Key with the keys that are in t but not in other. Both must be built.
+func (t *Key[K]) Difference(other *Key[K]) *Key[K] {
+	return &Key[K]{key:mergeSorted(t.key, other.key, compareOrdered[K], setA)}
+}
+
+// SymmetricDifference returns a new Key with the keys that are in only one of them. Both must be built.
+func (t *Key[K]) SymmetricDifference(other *Key[K]) *Key[K] {
+	return &Key[K]{key:mergeSorted(t.key, other.key, compareOrdered[K], setA | setB)}
+}
+
+// UnionLen returns the number of keys Union would return, without allocating.
+func (t *Key[K]) UnionLen(other *Key[K]) int {
+	return mergeSortedLen(t.key, other.key, compareOrdered[K], setA | setBoth | setB)
+}
+
+// IntersectLen returns the number of keys Intersect would return, without allocating.
+func (t *Key[K]) IntersectLen(other *Key[K]) int {
+	return mergeSortedLen(t.key, other.key, compareOrdered[K], setBoth)
+}
+
+// DifferenceLen returns the number of keys Difference would return, without allocating.
+func (t *Key[K]) DifferenceLen(other *Key[K]) int {
+	return mergeSortedLen(t.key, other.key, compareOrdered[K], setA)
+}
+
+// SymmetricDifferenceLen returns the number of keys SymmetricDifference would return, without allocating.
+func (t *Key[K]) SymmetricDifferenceLen(other *Key[K]) int {
+	return mergeSortedLen(t.key, other.key, compareOrdered[K], setA | setB)
+}
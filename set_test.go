@@ -0,0 +1,104 @@
+package binsearch
+
+import (
+ "math/rand"
+ "strings"
+ "testing"
+)
+
+// setWant returns the keys of a and b that op keeps, op is one of Union, Intersect, Difference and SymmetricDifference.
+func setWant[K comparable](a, b map[K]bool, op string) map[K]bool {
+	want := make(map[K]bool)
+	for k := range a {
+		if op == `Union` || (op == `Intersect`) == b[k] {
+			want[k] = true
+		}
+	}
+	for k := range b {
+		if op == `Union` || (op == `SymmetricDifference` && !a[k]) {
+			want[k] = true
+		}
+	}
+	return want
+}
+
+func randomKeys(rnd *rand.Rand) map[string]bool {
+	keys := make(map[string]bool)
+	for i:=0; i<150; i++ {
+		keys[strings.Repeat(string(`ab`[rnd.Intn(2)]), rnd.Intn(100))] = true
+		keys[string([]byte{byte(rnd.Intn(4)), byte(rnd.Intn(4))})] = true
+	}
+	return keys
+}
+
+func TestKeyBytesSet(t *testing.T) {
+	rnd := rand.New(rand.NewSource(12))
+	for round:=0; round<10; round++ {
+		a, b := randomKeys(rnd), randomKeys(rnd)
+		x, y := new(KeyBytes), new(KeyBytes)
+		for k := range a {
+			x.AddUnsorted([]byte(k))
+		}
+		for k := range b {
+			y.AddUnsorted([]byte(k))
+		}
+		x.Build()
+		y.Build()
+		ops := map[string]func(*KeyBytes) *KeyBytes{`Union`: x.Union, `Intersect`: x.Intersect, `Difference`: x.Difference, `SymmetricDifference`: x.SymmetricDifference}
+		lens := map[string]func(*KeyBytes) int{`Union`: x.UnionLen, `Intersect`: x.IntersectLen, `Difference`: x.DifferenceLen, `SymmetricDifference`: x.SymmetricDifferenceLen}
+		for op, fn := range ops {
+			want := setWant(a, b, op)
+			got := fn(y)
+			if got.Len() != len(want) || lens[op](y) != len(want) {
+				t.Fatalf(`%s has %d keys and Len %d, want %d`, op, got.Len(), lens[op](y), len(want))
+			}
+			for k := range want {
+				if _, ok := got.Find([]byte(k)); !ok {
+					t.Fatalf(`%s is missing %q`, op, k)
+				}
+			}
+			if n := testing.AllocsPerRun(10, func() { lens[op](y) }); n != 0 {
+				t.Fatalf(`%sLen allocates %v times`, op, n)
+			}
+		}
+	}
+}
+
+func TestKeySet(t *testing.T) {
+	rnd := rand.New(rand.NewSource(13))
+	for round:=0; round<10; round++ {
+		a, b := make(map[uint64]bool), make(map[uint64]bool)
+		x, y := new(KeyUint64), new(KeyUint64)
+		for i:=0; i<100; i++ {
+			v, w := uint64(rnd.Intn(200)), uint64(rnd.Intn(200))
+			if !a[v] {
+				a[v] = true
+				x.AddUnsorted(v)
+			}
+			if !b[w] {
+				b[w] = true
+				y.AddUnsorted(w)
+			}
+		}
+		x.Build()
+		y.Build()
+		ops := map[string]func(*KeyUint64) *KeyUint64{`Union`: x.Union, `Intersect`: x.Intersect, `Difference`: x.Difference, `SymmetricDifference`: x.SymmetricDifference}
+		lens := map[string]func(*KeyUint64) int{`Union`: x.UnionLen, `Intersect`: x.IntersectLen, `Difference`: x.DifferenceLen, `SymmetricDifference`: x.SymmetricDifferenceLen}
+		for op, fn := range ops {
+			want := setWant(a, b, op)
+			got := fn(y)
+			if got.Len() != len(want) || lens[op](y) != len(want) {
+				t.Fatalf(`%s has %d keys and Len %d, want %d`, op, got.Len(), lens[op](y), len(want))
+			}
+			keys := got.Keys()
+			for i, v := range keys {
+				if !want[v] || (i > 0 && keys[i-1] >= v) {
+					t.Fatalf(`%s keys %v`, op, keys)
+				}
+			}
+			if n := testing.AllocsPerRun(10, func() { lens[op](y) }); n != 0 {
+				t.Fatalf(`%sLen allocates %v times`, op, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package binsearch
+
+import (
+ "math"
+ "math/rand"
+ "slices"
+ "testing"
+)
+
+// testSigned checks the order, Find and Write/Read of the signed types for one width.
+func testSigned[K int8 | int16 | int32 | int64](t *testing.T, vals []K) {
+	t.Helper()
+	k := new(Key[K])
+	kv := new(KeyVal[K, int])
+	c := new(Counter[K, int])
+	for i, v := range vals {
+		k.AddUnsorted(v)
+		kv.AddUnsorted(v, -i)
+		c.Add(v, 1)
+		c.Add(v, i)
+	}
+	k.Build()
+	kv.Build()
+	c.Build()
+	want := slices.Clone(vals)
+	slices.Sort(want)
+	if keys := k.Keys(); !slices.Equal(keys, want) {
+		t.Fatalf(`%T keys %v, want %v`, k, keys, want)
+	}
+	k2 := new(Key[K])
+	roundTrip(t, k.Write, k2.Read)
+	kv2 := new(KeyVal[K, int])
+	roundTrip(t, kv.Write, kv2.Read)
+	c2 := new(Counter[K, int])
+	roundTrip(t, c.Write, c2.Read)
+	for i, v := range vals {
+		if j, ok := k2.Find(v); !ok || want[j] != v {
+			t.Fatalf(`%T %d found at %d, %v`, k, v, j, ok)
+		}
+		if n, ok := kv2.Find(v); !ok || n != -i {
+			t.Fatalf(`%T %d = %d, %v`, kv, v, n, ok)
+		}
+		if n, ok := c2.Find(v); !ok || n != i+1 {
+			t.Fatalf(`%T %d = %d, %v`, c, v, n, ok)
+		}
+	}
+}
+
+// signedVals returns the extremes, the values around zero and distinct random values of the width of K.
+func signedVals[K int8 | int16 | int32 | int64](min, max K, bits uint) []K {
+	vals := []K{min, min + 1, -1, 0, 1, max - 1, max}
+	rnd := rand.New(rand.NewSource(int64(bits)))
+	for i:=0; i<200; i++ {
+		v := K(rnd.Uint64() >> (64 - bits))
+		if !slices.Contains(vals, v) {
+			vals = append(vals, v)
+		}
+	}
+	return vals
+}
+
+func TestSigned(t *testing.T) {
+	testSigned(t, signedVals[int8](math.MinInt8, math.MaxInt8, 8))
+	testSigned(t, signedVals[int16](math.MinInt16, math.MaxInt16, 16))
+	testSigned(t, signedVals[int32](math.MinInt32, math.MaxInt32, 32))
+	testSigned(t, signedVals[int64](math.MinInt64, math.MaxInt64, 64))
+}
+
+func TestCounterInt32(t *testing.T) {
+	c := new(CounterInt32)
+	c.Add(-7, 2)
+	c.Add(3, 1)
+	c.Add(-7, 1)
+	c.Build()
+	if keys := c.KeyInt32().Keys(); len(keys) != 2 || keys[0] != -7 || keys[1] != 3 {
+		t.Fatal(keys)
+	}
+	if v, ok := c.KeyValInt32().Find(-7); !ok || v != 3 {
+		t.Fatal(v, ok)
+	}
+	c2 := new(CounterInt32)
+	roundTrip(t, c.Write, c2.Read)
+	if v, ok := c2.Find(-7); !ok || v != 3 || c2.Len() != 2 {
+		t.Fatal(v, ok)
+	}
+}
@@ -0,0 +1,108 @@
+package binsearch
+
+import (
+ "strings"
+ "testing"
+)
+
+var stringKeys = []string{"", "\x00", "\x00a", "a", "hello world", strings.Repeat("x", 33), strings.Repeat("y", 64), strings.Repeat("z", 100)}
+
+func TestKeyString(t *testing.T) {
+	k := new(KeyString)
+	kb := new(KeyBytes)
+	c := new(CounterString)
+	kv := new(KeyValString)
+	for i, x := range stringKeys {
+		k.AddUnsorted(x)
+		kb.AddUnsorted([]byte(x))
+		c.Add(x, 1)
+		c.Add(x, i)
+		kv.Add(x, i)
+	}
+	k.Build()
+	kb.Build()
+	c.Build()
+	for i, x := range stringKeys {
+		a, ok := k.Find(x)
+		b, ok2 := kb.Find([]byte(x))
+		if !ok || !ok2 || a != b {
+			t.Fatalf(`%q found at %d, %v and %d, %v`, x, a, ok, b, ok2)
+		}
+		if v, ok := c.Find(x); !ok || v != i+1 {
+			t.Fatalf(`CounterString %q = %d, %v`, x, v, ok)
+		}
+		if v, ok := kv.Find(x); !ok || v != i {
+			t.Fatalf(`KeyValString %q = %d, %v`, x, v, ok)
+		}
+	}
+	if _, ok := k.Find(`nope`); ok {
+		t.Fatal(`found a key that was not added`)
+	}
+	got := make(map[string]bool)
+	for _, x := range c.KeyString().Keys() {
+		got[x] = true
+	}
+	if k.Reset() {
+		for {
+			x, eof := k.Next()
+			if !got[x] {
+				t.Fatalf(`Next %q`, x)
+			}
+			if eof {
+				break
+			}
+		}
+	}
+	for _, x := range stringKeys {
+		if !got[x] {
+			t.Fatalf(`%q missing from Keys`, x)
+		}
+	}
+	k2 := new(KeyString)
+	for _, x := range stringKeys {
+		k2.Add(x)
+	}
+	for _, x := range stringKeys {
+		a, _ := k.Find(x)
+		if b, ok := k2.Find(x); !ok || a != b {
+			t.Fatalf(`%q found at %d after Add, want %d`, x, b, a)
+		}
+	}
+	for _, x := range stringKeys {
+		if n := testing.AllocsPerRun(100, func() { k.Find(x); c.Find(x); kv.Find(x) }); n != 0 {
+			t.Fatalf(`Find(%q) allocates %v times`, x, n)
+		}
+	}
+}
+
+func TestKeyStringFormat(t *testing.T) {
+	k := new(KeyString)
+	c := new(CounterString)
+	for i, x := range stringKeys {
+		k.AddUnsorted(x)
+		c.Add(x, i)
+	}
+	k.Build()
+	c.Build()
+	// The string types share the file format of the bytes types.
+	kb := new(KeyBytes)
+	roundTrip(t, k.Write, kb.Read)
+	k2 := new(KeyString)
+	roundTrip(t, kb.Write, k2.Read)
+	cb := new(CounterBytes)
+	roundTrip(t, c.Write, cb.Read)
+	c2 := new(CounterString)
+	roundTrip(t, cb.Write, c2.Read)
+	for i, x := range stringKeys {
+		if _, ok := kb.Find([]byte(x)); !ok {
+			t.Fatalf(`%q missing from KeyBytes`, x)
+		}
+		a, _ := k.Find(x)
+		if b, ok := k2.Find(x); !ok || a != b {
+			t.Fatalf(`%q found at %d after Read, want %d`, x, b, a)
+		}
+		if v, ok := c2.Find(x); !ok || v != i {
+			t.Fatalf(`%q = %d, %v after Read`, x, v, ok)
+		}
+	}
+}
@@ -0,0 +1,933 @@
+package binsearch
+
+import (
+ "github.com/AlasdairF/BinSearch/Ordered"
+ "cmp"
+ "errors"
+ "math"
+ "reflect"
+ "slices"
+ "unsafe"
+)
+
+/*
+	TopK returns the k keys with the greatest values. It reads the values straight from the tiers and keeps only the best k on a heap.
+	ResetByValue and NextByValue iterate through all the keys ordered by their values, in the same way as Reset and Next.
+*/
+
+type ranked[N any] struct {
+ val N
+ i int
+}
+
+// rankValues returns the indexes of the k greatest values given by each, greatest first, or the k least, least first, if not descending. All of them if k < 0.
+// Equal values keep the order of their keys.
+func rankValues[N any](n, k int, descending bool, compare func(N, N) int, each func(func(int, N))) []int {
+	before := func(a, b ranked[N]) bool {
+		c := compare(a.val, b.val)
+		if c == 0 {
+			return a.i < b.i
+		}
+		return (c > 0) == descending
+	}
+	if k < 0 || k > n {
+		k = n
+	}
+	// The heap keeps the best k so far, with the worst of them on top so it can be replaced
+	heap := make([]ranked[N], 0, k)
+	down := func(i int) {
+		for {
+			worst := i
+			if c := 2*i + 1; c < len(heap) && before(heap[worst], heap[c]) {
+				worst = c
+			}
+			if c := 2*i + 2; c < len(heap) && before(heap[worst], heap[c]) {
+				worst = c
+			}
+			if worst == i {
+				return
+			}
+			heap[i], heap[worst] = heap[worst], heap[i]
+			i = worst
+		}
+	}
+	each(func(i int, v N) {
+		r := ranked[N]{v, i}
+		if len(heap) < k {
+			heap = append(heap, r)
+			if k < n { // no need for the heap if everything is kept
+				for c := len(heap) - 1; c > 0 && before(heap[(c-1)/2], heap[c]); c = (c-1)/2 {
+					heap[c], heap[(c-1)/2] = heap[(c-1)/2], heap[c]
+				}
+			}
+			return
+		}
+		if k > 0 && before(r, heap[0]) {
+			heap[0] = r
+			down(0)
+		}
+	})
+	slices.SortFunc(heap, func(a, b ranked[N]) int {
+		if before(a, b) {
+			return -1
+		}
+		return 1
+	})
+	order := make([]int, len(heap))
+	for i, r := range heap {
+		order[i] = r.i
+	}
+	return order
+}
+
+// valueOrder is the state of ResetByValue & NextByValue.
+type valueOrder struct {
+ order []int
+ cursor int
+}
+
+func (o *valueOrder) reset(order []int) bool {
+	o.order = order
+	o.cursor = 0
+	return len(order) > 0
+}
+
+// next returns the index of the next key, and whether it is the last.
+func (o *valueOrder) next() (int, bool) {
+	on := o.order[o.cursor]
+	if o.cursor++; o.cursor == len(o.order) {
+		o.cursor = 0
+		return on, true
+	}
+	return on, false
+}
+
+// ErrNotOrdered is returned by DefaultCompare for value types that can't be ordered, use NewKeyValFunc or NewKeyValBytesOfFunc.
+var ErrNotOrdered = errors.New(`There is no default order for this value type, use NewKeyValFunc or NewKeyValBytesOfFunc`)
+
+/*
+	DefaultCompare returns the comparison used to order the values of KeyVal[K, V] and KeyValBytesOf[V], or ErrNotOrdered.
+	Integers, floats and strings (and types based on them) are ordered as cmp.Compare orders them.
+	The type of V is checked once here and not for each comparison, in the same way as DefaultCodec.
+*/
+func DefaultCompare[V any]() (func(V, V) int, error) {
+	var fn interface{}
+	switch interface{}(*new(V)).(type) {
+		case int: fn = cmp.Compare[int]
+		case int8: fn = cmp.Compare[int8]
+		case int16: fn = cmp.Compare[int16]
+		case int32: fn = cmp.Compare[int32]
+		case int64: fn = cmp.Compare[int64]
+		case uint: fn = cmp.Compare[uint]
+		case uint8: fn = cmp.Compare[uint8]
+		case uint16: fn = cmp.Compare[uint16]
+		case uint32: fn = cmp.Compare[uint32]
+		case uint64: fn = cmp.Compare[uint64]
+		case uintptr: fn = cmp.Compare[uintptr]
+		case float32: fn = cmp.Compare[float32]
+		case float64: fn = cmp.Compare[float64]
+		case string: fn = cmp.Compare[string]
+	}
+	if f, ok := fn.(func(V, V) int); ok {
+		return f, nil
+	}
+	// A named type, compared as the type it's based on
+	switch reflect.TypeOf((*V)(nil)).Elem().Kind() {
+		case reflect.Int: return compareAs[V, int], nil
+		case reflect.Int8: return compareAs[V, int8], nil
+		case reflect.Int16: return compareAs[V, int16], nil
+		case reflect.Int32: return compareAs[V, int32], nil
+		case reflect.Int64: return compareAs[V, int64], nil
+		case reflect.Uint: return compareAs[V, uint], nil
+		case reflect.Uint8: return compareAs[V, uint8], nil
+		case reflect.Uint16: return compareAs[V, uint16], nil
+		case reflect.Uint32: return compareAs[V, uint32], nil
+		case reflect.Uint64: return compareAs[V, uint64], nil
+		case reflect.Uintptr: return compareAs[V, uintptr], nil
+		case reflect.Float32: return compareAs[V, float32], nil
+		case reflect.Float64: return compareAs[V, float64], nil
+		case reflect.String: return compareAs[V, string], nil
+	}
+	return nil, ErrNotOrdered
+}
+
+// compareAs compares a and b as T, DefaultCompare has already checked the kind of V.
+func compareAs[V any, T cmp.Ordered](a, b V) int {
+	return cmp.Compare(*(*T)(unsafe.Pointer(&a)), *(*T)(unsafe.Pointer(&b)))
+}
+
+// mustCompare is DefaultCompare for the value order, it panics before anything is ordered.
+func mustCompare[V any]() func(V, V) int {
+	compare, err := DefaultCompare[V]()
+	if err != nil {
+		panic(err)
+	}
+	return compare
+}
+
+// eachRowValue calls fn with the index and value of every row.
+func eachRowValue[T row](rows []T, fn func(int, int)) {
+	for i := range rows {
+		fn(i, int(rows[i][len(rows[i])-1]))
+	}
+}
+
+// keyAt returns the key at index i.
+func (t *KeyBytes) keyAt(i int) []byte {
+	var key []byte
+	if tiered := t.total - len(t.overflow); i >= tiered {
+		key, _ = t.classEntry(64, i - tiered)
+		return key
+	}
+	on := 63
+	for t.count[on] > i {
+		on--
+	}
+	key, _ = t.classEntry(on, i - t.count[on])
+	return key
+}
+
+// ---------- KeyValBytes ----------
+
+// offsets returns the index of the first key of each length class, the same as count in KeyBytes, and the number of keys in the tiers last.
+func (t *KeyValBytes) offsets() [65]int {
+	var offs [65]int
+	for run:=0; run<8; run++ {
+		offs[run + 1] = len(t.limit8[run])
+		offs[run + 9] = len(t.limit16[run])
+		offs[run + 17] = len(t.limit24[run])
+		offs[run + 25] = len(t.limit32[run])
+		offs[run + 33] = len(t.limit40[run])
+		offs[run + 41] = len(t.limit48[run])
+		offs[run + 49] = len(t.limit56[run])
+		offs[run + 57] = len(t.limit64[run])
+	}
+	for run:=2; run<65; run++ {
+		offs[run] += offs[run-1]
+	}
+	return offs
+}
+
+// entry returns the key and value at index i.
+func (t *KeyValBytes) entry(offs *[65]int, i int) ([]byte, int) {
+	if i >= offs[64] {
+		return t.classEntry(64, i - offs[64])
+	}
+	on := 63
+	for offs[on] > i {
+		on--
+	}
+	return t.classEntry(on, i - offs[on])
+}
+
+// eachValue calls fn with the index and value of every key, in order.
+func (t *KeyValBytes) eachValue(fn func(int, int)) {
+	var on int
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit8[run] {
+			fn(on, int(v[1]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit16[run] {
+			fn(on, int(v[2]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit24[run] {
+			fn(on, int(v[3]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit32[run] {
+			fn(on, int(v[4]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit40[run] {
+			fn(on, int(v[5]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit48[run] {
+			fn(on, int(v[6]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit56[run] {
+			fn(on, int(v[7]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit64[run] {
+			fn(on, int(v[8]))
+			on++
+		}
+	}
+	for _, v := range t.overflow {
+		fn(on, v.K)
+		on++
+	}
+}
+
+func (t *KeyValBytes) rank(k int, descending bool, compare func(int, int) int) []int {
+	return rankValues(t.total, k, descending, compare, t.eachValue)
+}
+
+func (t *KeyValBytes) entries(order []int) ([][]byte, []int) {
+	offs := t.offsets()
+	keys := make([][]byte, len(order))
+	vals := make([]int, len(order))
+	for i, on := range order {
+		keys[i], vals[i] = t.entry(&offs, on)
+	}
+	return keys, vals
+}
+
+func (t *KeyValBytes) resetByValue(descending bool, compare func(int, int) int) bool {
+	t.byoffsets = t.offsets()
+	return t.byvalue.reset(t.rank(-1, descending, compare))
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *KeyValBytes) TopK(k int) ([][]byte, []int) {
+	return t.entries(t.rank(k, true, cmp.Compare[int]))
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *KeyValBytes) ResetByValue(descending bool) bool {
+	return t.resetByValue(descending, cmp.Compare[int])
+}
+
+// NextByValue returns: original slice of bytes, value, EOF (true = EOF), ordered by value.
+func (t *KeyValBytes) NextByValue() ([]byte, int, bool) {
+	on, eof := t.byvalue.next()
+	key, val := t.entry(&t.byoffsets, on)
+	return key, val, eof
+}
+
+// ---------- CounterBytes ----------
+
+// offsets returns the index of the first key of each length class, the same as count in KeyBytes, and the number of keys in the tiers last.
+func (t *CounterBytes) offsets() [65]int {
+	var offs [65]int
+	for run:=0; run<8; run++ {
+		offs[run + 1] = len(t.limit8[run])
+		offs[run + 9] = len(t.limit16[run])
+		offs[run + 17] = len(t.limit24[run])
+		offs[run + 25] = len(t.limit32[run])
+		offs[run + 33] = len(t.limit40[run])
+		offs[run + 41] = len(t.limit48[run])
+		offs[run + 49] = len(t.limit56[run])
+		offs[run + 57] = len(t.limit64[run])
+	}
+	for run:=2; run<65; run++ {
+		offs[run] += offs[run-1]
+	}
+	return offs
+}
+
+// entry returns the key and value at index i.
+func (t *CounterBytes) entry(offs *[65]int, i int) ([]byte, int) {
+	if i >= offs[64] {
+		return t.classEntry(64, i - offs[64])
+	}
+	on := 63
+	for offs[on] > i {
+		on--
+	}
+	return t.classEntry(on, i - offs[on])
+}
+
+// eachValue calls fn with the index and value of every key, in order.
+func (t *CounterBytes) eachValue(fn func(int, int)) {
+	var on int
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit8[run] {
+			fn(on, int(v[1]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit16[run] {
+			fn(on, int(v[2]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit24[run] {
+			fn(on, int(v[3]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit32[run] {
+			fn(on, int(v[4]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit40[run] {
+			fn(on, int(v[5]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit48[run] {
+			fn(on, int(v[6]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit56[run] {
+			fn(on, int(v[7]))
+			on++
+		}
+	}
+	for run:=0; run<8; run++ {
+		for _, v := range t.limit64[run] {
+			fn(on, int(v[8]))
+			on++
+		}
+	}
+	for _, v := range t.overflow {
+		fn(on, v.K)
+		on++
+	}
+}
+
+func (t *CounterBytes) rank(k int, descending bool, compare func(int, int) int) []int {
+	return rankValues(t.total, k, descending, compare, t.eachValue)
+}
+
+func (t *CounterBytes) entries(order []int) ([][]byte, []int) {
+	offs := t.offsets()
+	keys := make([][]byte, len(order))
+	vals := make([]int, len(order))
+	for i, on := range order {
+		keys[i], vals[i] = t.entry(&offs, on)
+	}
+	return keys, vals
+}
+
+func (t *CounterBytes) resetByValue(descending bool, compare func(int, int) int) bool {
+	t.byoffsets = t.offsets()
+	return t.byvalue.reset(t.rank(-1, descending, compare))
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *CounterBytes) TopK(k int) ([][]byte, []int) {
+	return t.entries(t.rank(k, true, cmp.Compare[int]))
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *CounterBytes) ResetByValue(descending bool) bool {
+	return t.resetByValue(descending, cmp.Compare[int])
+}
+
+// NextByValue returns: original slice of bytes, value, EOF (true = EOF), ordered by value.
+func (t *CounterBytes) NextByValue() ([]byte, int, bool) {
+	on, eof := t.byvalue.next()
+	key, val := t.entry(&t.byoffsets, on)
+	return key, val, eof
+}
+
+// ---------- CounterBytesFloat64 ----------
+
+func compareFloat64Bits(a, b int) int {
+	return cmp.Compare(math.Float64frombits(uint64(a)), math.Float64frombits(uint64(b)))
+}
+
+// TopK returns the k keys with the greatest totals, and their totals, greatest first. Equal totals are in key order.
+func (t *CounterBytesFloat64) TopK(k int) ([][]byte, []float64) {
+	keys, vals := t.child.entries(t.child.rank(k, true, compareFloat64Bits))
+	res := make([]float64, len(vals))
+	for i, v := range vals {
+		res[i] = math.Float64frombits(uint64(v))
+	}
+	return keys, res
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *CounterBytesFloat64) ResetByValue(descending bool) bool {
+	return t.child.resetByValue(descending, compareFloat64Bits)
+}
+
+// NextByValue returns: original slice of bytes, total, EOF (true = EOF), ordered by total.
+func (t *CounterBytesFloat64) NextByValue() ([]byte, float64, bool) {
+	key, val, eof := t.child.NextByValue()
+	return key, math.Float64frombits(uint64(val)), eof
+}
+
+// ---------- CounterBytesInt64 ----------
+
+// TopK returns the k keys with the greatest totals, and their totals, greatest first. Equal totals are in key order.
+func (t *CounterBytesInt64) TopK(k int) ([][]byte, []int64) {
+	keys, vals := t.child.TopK(k)
+	res := make([]int64, len(vals))
+	for i, v := range vals {
+		res[i] = int64(v)
+	}
+	return keys, res
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *CounterBytesInt64) ResetByValue(descending bool) bool {
+	return t.child.ResetByValue(descending)
+}
+
+// NextByValue returns: original slice of bytes, total, EOF (true = EOF), ordered by total.
+func (t *CounterBytesInt64) NextByValue() ([]byte, int64, bool) {
+	key, val, eof := t.child.NextByValue()
+	return key, int64(val), eof
+}
+
+// ---------- KeyValBytesOf ----------
+
+// valueCompare returns the order of the values, choosing DefaultCompare the first time if there isn't one.
+func (t *KeyValBytesOf[V]) valueCompare() func(V, V) int {
+	if t.compare == nil {
+		t.compare = mustCompare[V]()
+	}
+	return t.compare
+}
+
+func (t *KeyValBytesOf[V]) rank(k int, descending bool) []int {
+	return rankValues(len(t.val), k, descending, t.valueCompare(), func(fn func(int, V)) {
+		for i, v := range t.val {
+			fn(i, v)
+		}
+	})
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+// V must be an integer, float or string type, or the structure made by NewKeyValBytesOfFunc, otherwise it panics with ErrNotOrdered.
+func (t *KeyValBytesOf[V]) TopK(k int) ([][]byte, []V) {
+	order := t.rank(k, true)
+	keys := make([][]byte, len(order))
+	vals := make([]V, len(order))
+	for i, on := range order {
+		keys[i], vals[i] = t.child.keyAt(on), t.val[on]
+	}
+	return keys, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *KeyValBytesOf[V]) ResetByValue(descending bool) bool {
+	return t.byvalue.reset(t.rank(-1, descending))
+}
+
+// NextByValue returns: original slice of bytes, value, EOF (true = EOF), ordered by value.
+func (t *KeyValBytesOf[V]) NextByValue() ([]byte, V, bool) {
+	on, eof := t.byvalue.next()
+	return t.child.keyAt(on), t.val[on], eof
+}
+
+// ---------- Runes ----------
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *KeyValRunes) TopK(k int) ([][]rune, []int) {
+	keys, vals := t.child.TopK(k)
+	res := make([][]rune, len(keys))
+	for i, key := range keys {
+		res[i] = decodeRunes(key, t.legacy)
+	}
+	return res, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *KeyValRunes) ResetByValue(descending bool) bool {
+	return t.child.ResetByValue(descending)
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *KeyValRunes) NextByValue() ([]rune, int, bool) {
+	key, val, eof := t.child.NextByValue()
+	return decodeRunes(key, t.legacy), val, eof
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *CounterRunes) TopK(k int) ([][]rune, []int) {
+	keys, vals := t.child.TopK(k)
+	res := make([][]rune, len(keys))
+	for i, key := range keys {
+		res[i] = decodeRunes(key, t.legacy)
+	}
+	return res, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *CounterRunes) ResetByValue(descending bool) bool {
+	return t.child.ResetByValue(descending)
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *CounterRunes) NextByValue() ([]rune, int, bool) {
+	key, val, eof := t.child.NextByValue()
+	return decodeRunes(key, t.legacy), val, eof
+}
+
+// ---------- String ----------
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *KeyValString) TopK(k int) ([]string, []int) {
+	keys, vals := t.child.TopK(k)
+	res := make([]string, len(keys))
+	for i, key := range keys {
+		res[i] = string(key)
+	}
+	return res, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *KeyValString) ResetByValue(descending bool) bool {
+	return t.child.ResetByValue(descending)
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *KeyValString) NextByValue() (string, int, bool) {
+	key, val, eof := t.child.NextByValue()
+	return string(key), val, eof
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *CounterString) TopK(k int) ([]string, []int) {
+	keys, vals := t.child.TopK(k)
+	res := make([]string, len(keys))
+	for i, key := range keys {
+		res[i] = string(key)
+	}
+	return res, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *CounterString) ResetByValue(descending bool) bool {
+	return t.child.ResetByValue(descending)
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *CounterString) NextByValue() (string, int, bool) {
+	key, val, eof := t.child.NextByValue()
+	return string(key), val, eof
+}
+
+// ---------- KeyVal ----------
+
+// valueCompare returns the order of the values, choosing DefaultCompare the first time if there isn't one.
+func (t *KeyVal[K, V]) valueCompare() func(V, V) int {
+	if t.compare == nil {
+		t.compare = mustCompare[V]()
+	}
+	return t.compare
+}
+
+func (t *KeyVal[K, V]) rank(k int, descending bool) []int {
+	return rankValues(len(t.key), k, descending, t.valueCompare(), func(fn func(int, V)) {
+		for i, v := range t.key {
+			fn(i, v.K)
+		}
+	})
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+// V must be an integer, float or string type, or the structure made by NewKeyValFunc, otherwise it panics with ErrNotOrdered.
+func (t *KeyVal[K, V]) TopK(k int) ([]K, []V) {
+	return topKOrdered(t.key, t.rank(k, true))
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *KeyVal[K, V]) ResetByValue(descending bool) bool {
+	return t.byvalue.reset(t.rank(-1, descending))
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *KeyVal[K, V]) NextByValue() (K, V, bool) {
+	on, eof := t.byvalue.next()
+	return t.key[on].V, t.key[on].K, eof
+}
+
+// ---------- Counter ----------
+
+func (t *Counter[K, N]) rank(k int, descending bool) []int {
+	return rankValues(len(t.key), k, descending, cmp.Compare[N], func(fn func(int, N)) {
+		for i, v := range t.key {
+			fn(i, v.K)
+		}
+	})
+}
+
+// TopK returns the k keys with the greatest totals, and their totals, greatest first. Equal totals are in key order.
+func (t *Counter[K, N]) TopK(k int) ([]K, []N) {
+	return topKOrdered(t.key, t.rank(k, true))
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *Counter[K, N]) ResetByValue(descending bool) bool {
+	return t.byvalue.reset(t.rank(-1, descending))
+}
+
+// NextByValue returns: key, total, EOF (true = EOF), ordered by total.
+func (t *Counter[K, N]) NextByValue() (K, N, bool) {
+	on, eof := t.byvalue.next()
+	return t.key[on].V, t.key[on].K, eof
+}
+
+func topKOrdered[K cmp.Ordered, V any](cur []sortOrdered.KeyVal[K, V], order []int) ([]K, []V) {
+	keys := make([]K, len(order))
+	vals := make([]V, len(order))
+	for i, on := range order {
+		keys[i], vals[i] = cur[on].V, cur[on].K
+	}
+	return keys, vals
+}
+
+// ---------- Float ----------
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *KeyValFloat64) TopK(k int) ([]float64, []int) {
+	keys, vals := t.child.TopK(k)
+	res := make([]float64, len(keys))
+	for i, key := range keys {
+		res[i] = key2float64(key)
+	}
+	return res, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *KeyValFloat64) ResetByValue(descending bool) bool {
+	return t.child.ResetByValue(descending)
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *KeyValFloat64) NextByValue() (float64, int, bool) {
+	key, val, eof := t.child.NextByValue()
+	return key2float64(key), val, eof
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *CounterFloat64) TopK(k int) ([]float64, []int) {
+	keys, vals := t.child.TopK(k)
+	res := make([]float64, len(keys))
+	for i, key := range keys {
+		res[i] = key2float64(key)
+	}
+	return res, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *CounterFloat64) ResetByValue(descending bool) bool {
+	return t.child.ResetByValue(descending)
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *CounterFloat64) NextByValue() (float64, int, bool) {
+	key, val, eof := t.child.NextByValue()
+	return key2float64(key), val, eof
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *KeyValFloat32) TopK(k int) ([]float32, []int) {
+	keys, vals := t.child.TopK(k)
+	res := make([]float32, len(keys))
+	for i, key := range keys {
+		res[i] = key2float32(key)
+	}
+	return res, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *KeyValFloat32) ResetByValue(descending bool) bool {
+	return t.child.ResetByValue(descending)
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *KeyValFloat32) NextByValue() (float32, int, bool) {
+	key, val, eof := t.child.NextByValue()
+	return key2float32(key), val, eof
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *CounterFloat32) TopK(k int) ([]float32, []int) {
+	keys, vals := t.child.TopK(k)
+	res := make([]float32, len(keys))
+	for i, key := range keys {
+		res[i] = key2float32(key)
+	}
+	return res, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *CounterFloat32) ResetByValue(descending bool) bool {
+	return t.child.ResetByValue(descending)
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *CounterFloat32) NextByValue() (float32, int, bool) {
+	key, val, eof := t.child.NextByValue()
+	return key2float32(key), val, eof
+}
+
+// ---------- Fixed size ----------
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *KeyValUint128) TopK(k int) ([][16]byte, []int) {
+	order := rankValues(len(t.key), k, true, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	})
+	keys := make([][16]byte, len(order))
+	vals := make([]int, len(order))
+	for i, on := range order {
+		keys[i], vals[i] = bytes128(t.key[on][:]), int(t.key[on][2])
+	}
+	return keys, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *KeyValUint128) ResetByValue(descending bool) bool {
+	return t.byvalue.reset(rankValues(len(t.key), -1, descending, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	}))
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *KeyValUint128) NextByValue() ([16]byte, int, bool) {
+	on, eof := t.byvalue.next()
+	return bytes128(t.key[on][:]), int(t.key[on][2]), eof
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *CounterUint128) TopK(k int) ([][16]byte, []int) {
+	order := rankValues(len(t.key), k, true, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	})
+	keys := make([][16]byte, len(order))
+	vals := make([]int, len(order))
+	for i, on := range order {
+		keys[i], vals[i] = bytes128(t.key[on][:]), int(t.key[on][2])
+	}
+	return keys, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *CounterUint128) ResetByValue(descending bool) bool {
+	return t.byvalue.reset(rankValues(len(t.key), -1, descending, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	}))
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *CounterUint128) NextByValue() ([16]byte, int, bool) {
+	on, eof := t.byvalue.next()
+	return bytes128(t.key[on][:]), int(t.key[on][2]), eof
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *KeyValUint160) TopK(k int) ([][20]byte, []int) {
+	order := rankValues(len(t.key), k, true, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	})
+	keys := make([][20]byte, len(order))
+	vals := make([]int, len(order))
+	for i, on := range order {
+		keys[i], vals[i] = bytes160(t.key[on][:]), int(t.key[on][3])
+	}
+	return keys, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *KeyValUint160) ResetByValue(descending bool) bool {
+	return t.byvalue.reset(rankValues(len(t.key), -1, descending, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	}))
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *KeyValUint160) NextByValue() ([20]byte, int, bool) {
+	on, eof := t.byvalue.next()
+	return bytes160(t.key[on][:]), int(t.key[on][3]), eof
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *CounterUint160) TopK(k int) ([][20]byte, []int) {
+	order := rankValues(len(t.key), k, true, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	})
+	keys := make([][20]byte, len(order))
+	vals := make([]int, len(order))
+	for i, on := range order {
+		keys[i], vals[i] = bytes160(t.key[on][:]), int(t.key[on][3])
+	}
+	return keys, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *CounterUint160) ResetByValue(descending bool) bool {
+	return t.byvalue.reset(rankValues(len(t.key), -1, descending, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	}))
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *CounterUint160) NextByValue() ([20]byte, int, bool) {
+	on, eof := t.byvalue.next()
+	return bytes160(t.key[on][:]), int(t.key[on][3]), eof
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *KeyValUint256) TopK(k int) ([][32]byte, []int) {
+	order := rankValues(len(t.key), k, true, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	})
+	keys := make([][32]byte, len(order))
+	vals := make([]int, len(order))
+	for i, on := range order {
+		keys[i], vals[i] = bytes256(t.key[on][:]), int(t.key[on][4])
+	}
+	return keys, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *KeyValUint256) ResetByValue(descending bool) bool {
+	return t.byvalue.reset(rankValues(len(t.key), -1, descending, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	}))
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *KeyValUint256) NextByValue() ([32]byte, int, bool) {
+	on, eof := t.byvalue.next()
+	return bytes256(t.key[on][:]), int(t.key[on][4]), eof
+}
+
+// TopK returns the k keys with the greatest values, and their values, greatest first. Equal values are in key order.
+func (t *CounterUint256) TopK(k int) ([][32]byte, []int) {
+	order := rankValues(len(t.key), k, true, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	})
+	keys := make([][32]byte, len(order))
+	vals := make([]int, len(order))
+	for i, on := range order {
+		keys[i], vals[i] = bytes256(t.key[on][:]), int(t.key[on][4])
+	}
+	return keys, vals
+}
+
+// ResetByValue must be called before NextByValue. Returns whether there are any entries.
+func (t *CounterUint256) ResetByValue(descending bool) bool {
+	return t.byvalue.reset(rankValues(len(t.key), -1, descending, cmp.Compare[int], func(fn func(int, int)) {
+		eachRowValue(t.key, fn)
+	}))
+}
+
+// NextByValue returns: key, value, EOF (true = EOF), ordered by value.
+func (t *CounterUint256) NextByValue() ([32]byte, int, bool) {
+	on, eof := t.byvalue.next()
+	return bytes256(t.key[on][:]), int(t.key[on][4]), eof
+}
@@ -0,0 +1,177 @@
+package binsearch
+
+import (
+ "errors"
+ "sort"
+ "testing"
+)
+
+func TestCounterBytesTopK(t *testing.T) {
+	keys := classKeys(400)
+	c := new(CounterBytes)
+	for i, x := range keys {
+		c.Add([]byte(x), (i * 7919) % 50)
+	}
+	c.Build()
+	// The brute force: every entry by value, ties in the order of Keys().
+	type entry struct {
+	 key string
+	 val int
+	}
+	all := make([]entry, 0, c.Len())
+	if c.Reset() {
+		for {
+			x, v, eof := c.Next()
+			all = append(all, entry{string(x), v})
+			if eof {
+				break
+			}
+		}
+	}
+	desc := append([]entry(nil), all...)
+	sort.SliceStable(desc, func(a, b int) bool { return desc[a].val > desc[b].val })
+	asc := append([]entry(nil), all...)
+	sort.SliceStable(asc, func(a, b int) bool { return asc[a].val < asc[b].val })
+	for _, k := range []int{0, 1, 25, len(all), len(all) + 10} {
+		tk, tv := c.TopK(k)
+		if len(tk) != min(k, len(all)) || len(tv) != len(tk) {
+			t.Fatal(k, len(tk))
+		}
+		for i := range tk {
+			if string(tk[i]) != desc[i].key || tv[i] != desc[i].val {
+				t.Fatalf(`TopK(%d)[%d] = %q, %d, want %q, %d`, k, i, tk[i], tv[i], desc[i].key, desc[i].val)
+			}
+		}
+	}
+	for _, descending := range []bool{true, false} {
+		want := asc
+		if descending {
+			want = desc
+		}
+		if !c.ResetByValue(descending) {
+			t.Fatal(`ResetByValue`)
+		}
+		n := 0
+		for {
+			x, v, eof := c.NextByValue()
+			if string(x) != want[n].key || v != want[n].val {
+				t.Fatalf(`NextByValue %d = %q, %d, want %q, %d`, n, x, v, want[n].key, want[n].val)
+			}
+			n++
+			if eof {
+				break
+			}
+		}
+		if n != len(want) {
+			t.Fatal(n)
+		}
+	}
+	if new(CounterBytes).ResetByValue(true) {
+		t.Fatal(`ResetByValue on an empty structure`)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	g := NewCounter[uint64, int](nil)
+	for i:=0; i<100; i++ {
+		g.Add(uint64(i), i % 10)
+	}
+	g.Build()
+	if gk, gv := g.TopK(3); gv[0] != 9 || gk[0] != 9 || gk[1] != 19 || gk[2] != 29 {
+		t.Fatal(gk, gv)
+	}
+	kv := new(KeyVal[string, float64])
+	kv.Add(`a`, 1.5)
+	kv.Add(`b`, -2)
+	kv.Add(`c`, 3)
+	if kk, _ := kv.TopK(10); len(kk) != 3 || kk[0] != `c` || kk[2] != `b` {
+		t.Fatal(kk)
+	}
+	kv.ResetByValue(false)
+	if k, v, eof := kv.NextByValue(); k != `b` || v != -2 || eof {
+		t.Fatal(k, v, eof)
+	}
+	f := new(CounterUint128)
+	f.Add([16]byte{1}, 5)
+	f.Add([16]byte{2}, 7)
+	f.Build()
+	if fk, fv := f.TopK(1); len(fk) != 1 || fk[0] != [16]byte{2} || fv[0] != 7 {
+		t.Fatal(fk, fv)
+	}
+	s := new(CounterString)
+	for _, x := range []string{`x`, `y`, `y`, ``} {
+		s.Add(x, 1)
+	}
+	s.Build()
+	if sk, sv := s.TopK(1); sk[0] != `y` || sv[0] != 2 {
+		t.Fatal(sk, sv)
+	}
+	if ek, _ := NewCounter[int, int](nil).TopK(5); len(ek) != 0 {
+		t.Fatal(ek)
+	}
+}
+
+func TestValueOrder(t *testing.T) {
+	type score uint16
+	kv := new(KeyVal[int, score])
+	of := new(KeyValBytesOf[string])
+	for i:=0; i<50; i++ {
+		kv.Add(i, score((i * 37) % 11))
+		of.Add([]byte{byte(i + 1)}, string(rune('z' - (i * 37) % 11)))
+	}
+	if kk, vv := kv.TopK(2); kk[0] != 8 || vv[0] != 10 || kk[1] != 19 {
+		t.Fatal(kk, vv)
+	}
+	if ok, ov := of.TopK(1); ok[0][0] != 1 || ov[0] != `z` {
+		t.Fatal(ok, ov)
+	}
+	compare, err := DefaultCompare[score]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := testing.AllocsPerRun(10, func() { compare(3, 5) }); n != 0 {
+		t.Fatalf(`comparing a named type allocates %v times`, n)
+	}
+	if _, err := DefaultCompare[[2]int](); !errors.Is(err, ErrNotOrdered) {
+		t.Fatal(err)
+	}
+	// A value type with no default order, first without and then with a comparison
+	type pair struct {
+	 a, b int
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != ErrNotOrdered {
+				t.Fatal(`TopK of an unordered value did not panic with ErrNotOrdered`, r)
+			}
+		}()
+		p := new(KeyVal[int, pair])
+		p.Add(1, pair{1, 2})
+		p.TopK(1)
+	}()
+	p := NewKeyValFunc[int](func(x, y pair) int { return (x.a + x.b) - (y.a + y.b) })
+	p.Add(1, pair{1, 2})
+	p.Add(2, pair{5, -4})
+	p.Add(3, pair{0, 9})
+	if pk, _ := p.TopK(3); pk[0] != 3 || pk[1] != 1 || pk[2] != 2 {
+		t.Fatal(pk)
+	}
+	if k, ok := p.FindByValue(pair{2, 2}); ok {
+		t.Fatal(`FindByValue found`, k)
+	}
+	if k, ok := p.FindByValue(pair{1, 2}); !ok || k != 1 {
+		t.Fatal(k, ok)
+	}
+	if m := p.Merge(NewKeyValFunc[int](p.compare), func(a, b pair) pair { return a }); !m.ResetByValue(false) {
+		t.Fatal(`Merge`)
+	} else if k, _, _ := m.NextByValue(); k != 2 {
+		t.Fatal(`Merge lost the value order`, k)
+	}
+	pb := NewKeyValBytesOfFunc(func(x, y pair) int { return y.a - x.a })
+	pb.Add([]byte(`a`), pair{1, 0})
+	pb.Add([]byte(`b`), pair{3, 0})
+	pb.ResetByValue(true)
+	if k, _, _ := pb.NextByValue(); string(k) != `a` {
+		t.Fatal(string(k))
+	}
+}
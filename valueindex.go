@@ -0,0 +1,228 @@
+package binsearch
+
+import (
+ "cmp"
+ "slices"
+ "sort"
+ "github.com/AlasdairF/Custom"
+)
+
+/*
+	FindByValue finds the keys that have a value, using an index of the positions of the keys sorted by value (then by position).
+	BuildValueIndex builds the index, and FindByValue builds it first if there isn't one.
+	Every function that changes the keys or values drops the index, so it is built again by the next FindByValue.
+	If there is an index Write writes it after the keys and sets valueIndexFlag in the number of keys, which can never be that large,
+	so files written without an index are read the same as before.
+*/
+
+const valueIndexFlag = 1 << 62
+
+func writeValueIndex(w custom.Interface, index []int) {
+	for _, i := range index {
+		w.WriteUint64Variable(uint64(i))
+	}
+}
+
+// readValueIndex reads the index of l keys, compare compares the values at two positions.
+// It returns nil unless the index has every position once, sorted by value then by position, so FindByValue builds a corrupt index again.
+// This is checked in one pass. A nil compare, for values that can't be ordered, always returns nil.
+func readValueIndex(r *custom.Reader, l int, compare func(a, b int) int) []int {
+	index := make([]int, l)
+	seen := make([]uint64, (l + 63) >> 6)
+	bad := compare == nil
+	for i := range index {
+		v := r.ReadUint64Variable()
+		if bad {
+			continue // the rest must still be read
+		}
+		if v >= uint64(l) || seen[v >> 6] & (1 << (v & 63)) != 0 {
+			bad = true
+			continue
+		}
+		seen[v >> 6] |= 1 << (v & 63)
+		index[i] = int(v)
+		if i > 0 {
+			if c := compare(index[i-1], index[i]); c > 0 || (c == 0 && index[i-1] > index[i]) {
+				bad = true
+			}
+		}
+	}
+	if bad {
+		return nil
+	}
+	return index
+}
+
+// valueSpan returns the span [from, to) of index with value v, value returns the value at a position.
+func valueSpan[V any](index []int, v V, value func(int) V, compare func(V, V) int) (int, int) {
+	from := sort.Search(len(index), func(i int) bool {
+		return compare(value(index[i]), v) >= 0
+	})
+	to := from + sort.Search(len(index) - from, func(i int) bool {
+		return compare(value(index[from + i]), v) > 0
+	})
+	return from, to
+}
+
+// ---------- KeyValBytes ----------
+
+// valueAt returns the value at index i, offs is from offsets.
+func (t *KeyValBytes) valueAt(offs *[65]int, i int) int {
+	if i >= offs[64] {
+		return t.overflow[i - offs[64]].K
+	}
+	on := 63
+	for offs[on] > i {
+		on--
+	}
+	return t.classValue(on, i - offs[on])
+}
+
+// BuildValueIndex builds the index used by FindByValue & FindAllByValue.
+func (t *KeyValBytes) BuildValueIndex() {
+	index := make([]int, t.total)
+	vals := make([]int, t.total)
+	t.eachValue(func(i, v int) {
+		index[i], vals[i] = i, v
+	})
+	slices.SortStableFunc(index, func(a, b int) int {
+		return cmp.Compare(vals[a], vals[b])
+	})
+	t.valindex = index
+}
+
+// valueSpan returns the offsets and the span of t.valindex with value v.
+func (t *KeyValBytes) valueSpan(v int) ([65]int, int, int) {
+	if t.valindex == nil {
+		t.BuildValueIndex()
+	}
+	offs := t.offsets()
+	from, to := valueSpan(t.valindex, v, func(i int) int {
+		return t.valueAt(&offs, i)
+	}, cmp.Compare[int])
+	return offs, from, to
+}
+
+// FindByValue returns the first key in the order of Next with value v, and whether there is one.
+func (t *KeyValBytes) FindByValue(v int) ([]byte, bool) {
+	offs, from, to := t.valueSpan(v)
+	if from == to {
+		return nil, false
+	}
+	key, _ := t.entry(&offs, t.valindex[from])
+	return key, true
+}
+
+// FindAllByValue returns all the keys with value v, in the order of Next.
+func (t *KeyValBytes) FindAllByValue(v int) [][]byte {
+	offs, from, to := t.valueSpan(v)
+	if from == to {
+		return nil
+	}
+	keys := make([][]byte, to - from)
+	for i, at := range t.valindex[from:to] {
+		keys[i], _ = t.entry(&offs, at)
+	}
+	return keys
+}
+
+// ---------- KeyValRunes ----------
+
+// BuildValueIndex builds the index used by FindByValue & FindAllByValue.
+func (t *KeyValRunes) BuildValueIndex() {
+	t.child.BuildValueIndex()
+}
+
+// FindByValue returns the first key in the order of Next with value v, and whether there is one.
+func (t *KeyValRunes) FindByValue(v int) ([]rune, bool) {
+	if key, ok := t.child.FindByValue(v); ok {
+		return decodeRunes(key, t.legacy), true
+	}
+	return nil, false
+}
+
+// FindAllByValue returns all the keys with value v, in the order of Next.
+func (t *KeyValRunes) FindAllByValue(v int) [][]rune {
+	keys := t.child.FindAllByValue(v)
+	if keys == nil {
+		return nil
+	}
+	runes := make([][]rune, len(keys))
+	for i, key := range keys {
+		runes[i] = decodeRunes(key, t.legacy)
+	}
+	return runes
+}
+
+// ---------- KeyValString ----------
+
+// BuildValueIndex builds the index used by FindByValue & FindAllByValue.
+func (t *KeyValString) BuildValueIndex() {
+	t.child.BuildValueIndex()
+}
+
+// FindByValue returns the first key in the order of Next with value v, and whether there is one.
+func (t *KeyValString) FindByValue(v int) (string, bool) {
+	key, ok := t.child.FindByValue(v)
+	return string(key), ok
+}
+
+// FindAllByValue returns all the keys with value v, in the order of Next.
+func (t *KeyValString) FindAllByValue(v int) []string {
+	keys := t.child.FindAllByValue(v)
+	if keys == nil {
+		return nil
+	}
+	strs := make([]string, len(keys))
+	for i, key := range keys {
+		strs[i] = string(key)
+	}
+	return strs
+}
+
+// ---------- KeyVal ----------
+
+// BuildValueIndex builds the index used by FindByValue & FindAllByValue.
+// V must be an integer, float or string type, or the structure made by NewKeyValFunc, otherwise it panics with ErrNotOrdered.
+func (t *KeyVal[K, V]) BuildValueIndex() {
+	compare := t.valueCompare()
+	index := make([]int, len(t.key))
+	for i := range index {
+		index[i] = i
+	}
+	slices.SortStableFunc(index, func(a, b int) int {
+		return compare(t.key[a].K, t.key[b].K)
+	})
+	t.valindex = index
+}
+
+// valueSpan returns the span of t.valindex with value v.
+func (t *KeyVal[K, V]) valueSpan(v V) (int, int) {
+	if t.valindex == nil {
+		t.BuildValueIndex()
+	}
+	return valueSpan(t.valindex, v, func(i int) V {
+		return t.key[i].K
+	}, t.valueCompare())
+}
+
+// FindByValue returns the first key in the order of Next with value v, and whether there is one.
+func (t *KeyVal[K, V]) FindByValue(v V) (K, bool) {
+	if from, to := t.valueSpan(v); from < to {
+		return t.key[t.valindex[from]].V, true
+	}
+	return *new(K), false
+}
+
+// FindAllByValue returns all the keys with value v, in the order of Next.
+func (t *KeyVal[K, V]) FindAllByValue(v V) []K {
+	from, to := t.valueSpan(v)
+	if from == to {
+		return nil
+	}
+	keys := make([]K, to - from)
+	for i, at := range t.valindex[from:to] {
+		keys[i] = t.key[at].V
+	}
+	return keys
+}
@@ -0,0 +1,180 @@
+package binsearch
+
+import (
+ "bytes"
+ "fmt"
+ "math/rand"
+ "slices"
+ "testing"
+ "github.com/AlasdairF/Custom"
+)
+
+func TestFindByValue(t *testing.T) {
+	rnd := rand.New(rand.NewSource(25))
+	kv := new(KeyValBytes)
+	r := new(KeyValRunes)
+	s := new(KeyValString)
+	want := make(map[int]map[string]bool)
+	for i:=0; i<3000; i++ {
+		x := fmt.Sprint(rnd.Intn(1 << 20))
+		if i % 50 == 0 {
+			x = fmt.Sprintf(`%070d`, i) // past the 64 byte overflow
+		}
+		if i == 7 {
+			x = ``
+		}
+		if _, ok := kv.Find([]byte(x)); ok {
+			continue
+		}
+		v := rnd.Intn(500)
+		kv.Add([]byte(x), v)
+		r.Add([]rune(x), v)
+		s.Add(x, v)
+		if want[v] == nil {
+			want[v] = make(map[string]bool)
+		}
+		want[v][x] = true
+	}
+	check := func(kv *KeyValBytes, r *KeyValRunes, s *KeyValString) {
+		t.Helper()
+		pos := make(map[string]int) // the position of each key in the order of Next
+		if kv.Reset() {
+			for {
+				x, _, eof := kv.Next()
+				pos[string(x)] = len(pos)
+				if eof {
+					break
+				}
+			}
+		}
+		for v:=-1; v<=500; v++ {
+			all := kv.FindAllByValue(v)
+			if len(all) != len(want[v]) {
+				t.Fatalf(`FindAllByValue(%d) found %d keys, want %d`, v, len(all), len(want[v]))
+			}
+			for i, x := range all {
+				if !want[v][string(x)] {
+					t.Fatalf(`FindAllByValue(%d) gave %q`, v, x)
+				}
+				if i > 0 && pos[string(all[i-1])] >= pos[string(x)] {
+					t.Fatalf(`FindAllByValue(%d) is not in the order of Next`, v)
+				}
+			}
+			x, ok := kv.FindByValue(v)
+			if ok != (len(all) > 0) || (ok && !bytes.Equal(x, all[0])) {
+				t.Fatalf(`FindByValue(%d) = %q, %v`, v, x, ok)
+			}
+			if n := len(r.FindAllByValue(v)); n != len(all) {
+				t.Fatalf(`KeyValRunes.FindAllByValue(%d) found %d keys, want %d`, v, n, len(all))
+			}
+			if x, _ := s.FindByValue(v); ok && x != string(all[0]) {
+				t.Fatalf(`KeyValString.FindByValue(%d) = %q, want %q`, v, x, all[0])
+			}
+		}
+	}
+	check(kv, r, s)
+	// Written with the index, then written without it, and read back both times.
+	for _, indexed := range []bool{true, false} {
+		if !indexed {
+			kv.valindex, r.child.valindex = nil, nil
+		}
+		kv2 := new(KeyValBytes)
+		r2 := new(KeyValRunes)
+		roundTrip(t, func(w custom.Interface) {
+			kv.Write(w)
+			r.Write(w)
+		}, func(rd *custom.Reader) {
+			kv2.Read(rd)
+			r2.Read(rd)
+		})
+		if (kv2.valindex != nil) != indexed || kv2.Len() != kv.Len() {
+			t.Fatal(`read with the index`, indexed, kv2.Len())
+		}
+		check(kv2, r2, s)
+	}
+}
+
+func TestFindByValueStale(t *testing.T) {
+	var kv KeyValBytes
+	kv.Add([]byte(`a`), 1)
+	kv.Add([]byte(`b`), 2)
+	if x, ok := kv.FindByValue(2); !ok || string(x) != `b` {
+		t.Fatal(string(x), ok)
+	}
+	kv.Update([]byte(`a`), func(int) int { return 2 })
+	kv.Update([]byte(`b`), func(int) int { return 3 })
+	if x, ok := kv.FindByValue(2); !ok || string(x) != `a` {
+		t.Fatal(`after Update`, string(x), ok)
+	}
+	kv.Remove([]byte(`a`))
+	kv.Add([]byte(`c`), 2)
+	if x, ok := kv.FindByValue(2); !ok || string(x) != `c` {
+		t.Fatal(`after Remove and Add`, string(x), ok)
+	}
+	kv.Retain(func(x []byte, v int) bool { return v == 3 })
+	if _, ok := kv.FindByValue(2); ok {
+		t.Fatal(`after Retain`)
+	}
+	var g KeyVal[int, string]
+	g.Add(1, `x`)
+	g.Add(2, `y`)
+	g.BuildValueIndex()
+	g.UpdateAll(func(v string) string { return v + v })
+	if k, ok := g.FindByValue(`yy`); !ok || k != 2 {
+		t.Fatal(`after UpdateAll`, k, ok)
+	}
+	g.Remove(1)
+	g.Add(0, `q`)
+	if k, ok := g.FindByValue(`q`); !ok || k != 0 {
+		t.Fatal(`after Remove and Add`, k, ok)
+	}
+	g.Retain(func(k int, v string) bool { return k != 0 })
+	if all := g.FindAllByValue(`q`); len(all) != 0 {
+		t.Fatal(`after Retain`, all)
+	}
+}
+
+func TestFindByValueCorrupt(t *testing.T) {
+	g := new(KeyValUint64)
+	for i:=0; i<10; i++ {
+		g.Add(uint64(i), i % 3)
+	}
+	g.BuildValueIndex()
+	good := append([]int(nil), g.valindex...)
+	// Out of range, a position twice, values out of order, and positions out of order within a value, as a corrupt file would be
+	for _, corrupt := range []func([]int){
+		func(index []int) { index[4] = 10 },
+		func(index []int) { index[4] = index[5] },
+		func(index []int) { index[0], index[9] = index[9], index[0] },
+		func(index []int) { index[0], index[1] = index[1], index[0] },
+	} {
+		copy(g.valindex, good)
+		corrupt(g.valindex)
+		g2 := new(KeyValUint64)
+		roundTrip(t, g.Write, g2.Read)
+		if g2.valindex != nil {
+			t.Fatal(`a corrupt index was loaded`, g2.valindex)
+		}
+		if all := g2.FindAllByValue(1); len(all) != 3 || all[0] != 1 || all[2] != 7 {
+			t.Fatal(all)
+		}
+	}
+	copy(g.valindex, good)
+	g2 := new(KeyValUint64)
+	roundTrip(t, g.Write, g2.Read)
+	if !slices.Equal(g2.valindex, good) {
+		t.Fatal(`the index was not loaded`, g2.valindex)
+	}
+	// The same for KeyValBytes
+	kv := new(KeyValBytes)
+	for i:=0; i<10; i++ {
+		kv.Add([]byte(fmt.Sprint(i * 111)), i % 3)
+	}
+	kv.BuildValueIndex()
+	kv.valindex[2], kv.valindex[3] = kv.valindex[3], kv.valindex[2]
+	kv2 := new(KeyValBytes)
+	roundTrip(t, kv.Write, kv2.Read)
+	if kv2.valindex != nil {
+		t.Fatal(`a corrupt KeyValBytes index was loaded`, kv2.valindex)
+	}
+}